@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/automatiza-mg/fila/internal/analista"
 	"github.com/automatiza-mg/fila/internal/database"
@@ -12,8 +13,13 @@ import (
 )
 
 type AnalistaCreateRequest struct {
-	SEIUnidadeID string `json:"sei_unidade_id"`
-	Orgao        string `json:"orgao"`
+	SEIUnidadeID string   `json:"sei_unidade_id"`
+	Orgao        string   `json:"orgao"`
+	Competencias []string `json:"competencias"`
+	Nivel        string   `json:"nivel"`
+	// LimiteProcessos é opcional. Se omitido, o analista recebe um processo
+	// por vez.
+	LimiteProcessos int `json:"limite_processos"`
 
 	validator.Validator `json:"-"`
 }
@@ -42,15 +48,25 @@ func (app *application) handleAnalistaCreate(w http.ResponseWriter, r *http.Requ
 	orgaosMsg := fmt.Sprintf("Deve ser um dos valores: %s", strings.Join(analista.AllowedOrgaos, ", "))
 	input.Check(validator.PermittedValue(input.Orgao, analista.AllowedOrgaos...), "orgao", orgaosMsg)
 	input.Check(unidadeOk, "sei_unidade_id", "A unidade informada deve ser válida")
+	if input.Nivel != "" {
+		checkNivel(&input.Validator, input.Nivel)
+	}
+	checkCompetencias(&input.Validator, input.Competencias)
+	if input.LimiteProcessos != 0 {
+		checkLimiteProcessos(&input.Validator, input.LimiteProcessos)
+	}
 	if !input.Valid() {
 		app.validationFailed(w, r, input.FieldErrors)
 		return
 	}
 
 	a, err := app.analistas.CreateAnalista(r.Context(), analista.CreateAnalistaParams{
-		UsuarioID:    usuario.ID,
-		SeiUnidadeID: unidade.ID,
-		Orgao:        input.Orgao,
+		UsuarioID:       usuario.ID,
+		SeiUnidadeID:    unidade.ID,
+		Orgao:           input.Orgao,
+		Competencias:    input.Competencias,
+		Nivel:           input.Nivel,
+		LimiteProcessos: input.LimiteProcessos,
 	})
 	if err != nil {
 		switch {
@@ -150,3 +166,245 @@ func (app *application) handleAnalistaRetornar(w http.ResponseWriter, r *http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type AnalistaCompetenciasRequest struct {
+	Competencias []string `json:"competencias"`
+	Nivel        string   `json:"nivel"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleAnalistaCompetencias(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getUsuario(r.Context())
+	if !usuario.HasPapel(database.PapelAnalista) {
+		app.writeError(w, http.StatusForbidden, "Apenas usuários com papel de analista podem ter dados complementares cadastrados")
+		return
+	}
+
+	var input AnalistaCompetenciasRequest
+	err := app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	checkNivel(&input.Validator, input.Nivel)
+	checkCompetencias(&input.Validator, input.Competencias)
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	a, err := app.analistas.AtualizarCompetencias(r.Context(), analista.AtualizarCompetenciasParams{
+		UsuarioID:    usuario.ID,
+		Competencias: input.Competencias,
+		Nivel:        input.Nivel,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, a)
+}
+
+type AnalistaLimiteProcessosRequest struct {
+	LimiteProcessos int `json:"limite_processos"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleAnalistaLimiteProcessos(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getUsuario(r.Context())
+	if !usuario.HasPapel(database.PapelAnalista) {
+		app.writeError(w, http.StatusForbidden, "Apenas usuários com papel de analista podem ter dados complementares cadastrados")
+		return
+	}
+
+	var input AnalistaLimiteProcessosRequest
+	err := app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	checkLimiteProcessos(&input.Validator, input.LimiteProcessos)
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	a, err := app.analistas.AtualizarLimiteProcessos(r.Context(), usuario.ID, input.LimiteProcessos)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, a)
+}
+
+func checkLimiteProcessos(v *validator.Validator, limite int) {
+	msg := fmt.Sprintf("Deve estar entre 1 e %d", analista.MaxLimiteProcessos)
+	v.Check(limite >= 1 && limite <= analista.MaxLimiteProcessos, "limite_processos", msg)
+}
+
+func checkNivel(v *validator.Validator, nivel string) {
+	msg := fmt.Sprintf("Deve ser um dos valores: %s", strings.Join(analista.AllowedNiveis, ", "))
+	v.Check(validator.PermittedValue(nivel, analista.AllowedNiveis...), "nivel", msg)
+}
+
+func checkCompetencias(v *validator.Validator, competencias []string) {
+	msg := fmt.Sprintf("Deve conter apenas os valores: %s", strings.Join(analista.AllowedCompetencias, ", "))
+	for _, c := range competencias {
+		if !validator.PermittedValue(c, analista.AllowedCompetencias...) {
+			v.SetFieldError("competencias", msg)
+			break
+		}
+	}
+	v.Check(validator.Unique(competencias), "competencias", "Não deve conter valores repetidos")
+}
+
+type AfastamentoProgramadoRequest struct {
+	Inicio time.Time `json:"inicio"`
+	Fim    time.Time `json:"fim"`
+	Motivo string    `json:"motivo"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleAnalistaAfastamentoList(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getUsuario(r.Context())
+	app.listAfastamentosProgramados(w, r, usuario.ID)
+}
+
+func (app *application) handleAnalistaAfastamentoProgramar(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getUsuario(r.Context())
+	app.programarAfastamento(w, r, usuario.ID)
+}
+
+func (app *application) handleAnalistaAfastamentoCancelar(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getUsuario(r.Context())
+	app.cancelarAfastamentoProgramado(w, r, usuario.ID)
+}
+
+func (app *application) handleMeusAfastamentosList(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getAuth(r.Context())
+	app.listAfastamentosProgramados(w, r, usuario.ID)
+}
+
+func (app *application) handleMeusAfastamentosProgramar(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getAuth(r.Context())
+	app.programarAfastamento(w, r, usuario.ID)
+}
+
+func (app *application) handleMeusAfastamentosCancelar(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getAuth(r.Context())
+	app.cancelarAfastamentoProgramado(w, r, usuario.ID)
+}
+
+// listAfastamentosProgramados responde com os afastamentos programados do
+// analista informado.
+func (app *application) listAfastamentosProgramados(w http.ResponseWriter, r *http.Request, analistaID int64) {
+	if _, err := app.analistas.GetAnalista(r.Context(), analistaID); err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	aa, err := app.analistas.ListAfastamentosProgramados(r.Context(), analistaID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, aa)
+}
+
+// programarAfastamento registra um afastamento programado para o analista
+// informado, em nome do usuário autenticado.
+func (app *application) programarAfastamento(w http.ResponseWriter, r *http.Request, analistaID int64) {
+	var input AfastamentoProgramadoRequest
+	err := app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	input.Check(!input.Inicio.IsZero(), "inicio", "Campo obrigatório")
+	input.Check(!input.Fim.IsZero(), "fim", "Campo obrigatório")
+	input.Check(validator.NotBlank(input.Motivo), "motivo", "Campo obrigatório")
+	if !input.Inicio.IsZero() && !input.Fim.IsZero() {
+		input.Check(input.Fim.After(input.Inicio), "fim", "Deve ser posterior ao início")
+	}
+	if !input.Inicio.IsZero() {
+		input.Check(input.Inicio.After(time.Now()), "inicio", "Deve ser uma data futura")
+	}
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	ap, err := app.analistas.ProgramarAfastamento(r.Context(), analista.ProgramarAfastamentoParams{
+		UsuarioID: analistaID,
+		Inicio:    input.Inicio,
+		Fim:       input.Fim,
+		Motivo:    input.Motivo,
+		CriadoPor: usuario.ID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		case errors.Is(err, analista.ErrAfastamentoPassado):
+			app.validationFailed(w, r, map[string]string{"inicio": "Deve ser uma data futura"})
+		case errors.Is(err, analista.ErrAfastamentoSobreposto):
+			app.writeError(w, http.StatusConflict, "O período informado coincide com outro afastamento programado")
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusCreated, ap)
+}
+
+// cancelarAfastamentoProgramado cancela um afastamento programado, ainda não
+// iniciado, do analista informado.
+func (app *application) cancelarAfastamentoProgramado(w http.ResponseWriter, r *http.Request, analistaID int64) {
+	afastamentoID, err := app.intParam(r, "afastamentoID")
+	if err != nil || afastamentoID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.analistas.CancelarAfastamentoProgramado(r.Context(), analistaID, afastamentoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		case errors.Is(err, analista.ErrAfastamentoIniciado):
+			app.writeError(w, http.StatusConflict, "O afastamento já foi iniciado e só pode ser encerrado pelo retorno do analista")
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
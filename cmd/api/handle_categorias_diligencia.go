@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/diligencias"
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+// handleCategoriaDiligenciaList retorna o catálogo de categorias de
+// diligência em uso. Com "todas=true", inclui versões anteriores e categorias
+// desativadas.
+func (app *application) handleCategoriaDiligenciaList(w http.ResponseWriter, r *http.Request) {
+	todas := r.URL.Query().Get("todas") == "true"
+
+	categorias, err := app.diligencias.ListCategorias(r.Context(), todas)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, categorias)
+}
+
+func (app *application) handleCategoriaDiligenciaDetail(w http.ResponseWriter, r *http.Request) {
+	categoriaID, err := app.intParam(r, "categoriaID")
+	if err != nil || categoriaID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	c, err := app.diligencias.GetCategoria(r.Context(), categoriaID)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, c)
+}
+
+type SubcategoriaDiligenciaRequest struct {
+	Codigo string `json:"codigo"`
+	Nome   string `json:"nome"`
+}
+
+type CategoriaDiligenciaCreateRequest struct {
+	Codigo        string                          `json:"codigo"`
+	Nome          string                          `json:"nome"`
+	Descricao     string                          `json:"descricao"`
+	ListaFechada  bool                            `json:"lista_fechada"`
+	Ordem         int                             `json:"ordem"`
+	Subcategorias []SubcategoriaDiligenciaRequest `json:"subcategorias"`
+	Ativar        bool                            `json:"ativar"`
+
+	validator.Validator `json:"-"`
+}
+
+// handleCategoriaDiligenciaCreate cria uma nova versão de categoria. Quando o
+// código já existe, a versão criada sucede a mais recente do mesmo código.
+func (app *application) handleCategoriaDiligenciaCreate(w http.ResponseWriter, r *http.Request) {
+	var input CategoriaDiligenciaCreateRequest
+	if err := app.decodeJSON(w, r, &input); err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	categoria := &diligencias.CategoriaDiligencia{
+		Codigo:        input.Codigo,
+		Nome:          input.Nome,
+		Descricao:     input.Descricao,
+		ListaFechada:  input.ListaFechada,
+		Ordem:         input.Ordem,
+		Subcategorias: make([]*diligencias.SubcategoriaDiligencia, len(input.Subcategorias)),
+	}
+	for i, sub := range input.Subcategorias {
+		categoria.Subcategorias[i] = &diligencias.SubcategoriaDiligencia{
+			Codigo: sub.Codigo,
+			Nome:   sub.Nome,
+		}
+	}
+
+	categoria.Check(&input.Validator)
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	c, err := app.diligencias.CreateCategoria(r.Context(), diligencias.CreateCategoriaParams{
+		Categoria: categoria,
+		UsuarioID: usuario.ID,
+		Ativar:    input.Ativar,
+	})
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/categorias-diligencia/%d", c.ID))
+	app.writeJSON(w, http.StatusCreated, c)
+}
+
+func (app *application) handleCategoriaDiligenciaAtivar(w http.ResponseWriter, r *http.Request) {
+	app.alterarCategoriaDiligencia(w, r, app.diligencias.AtivarCategoria)
+}
+
+func (app *application) handleCategoriaDiligenciaDesativar(w http.ResponseWriter, r *http.Request) {
+	app.alterarCategoriaDiligencia(w, r, app.diligencias.DesativarCategoria)
+}
+
+func (app *application) alterarCategoriaDiligencia(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, id int64) error) {
+	categoriaID, err := app.intParam(r, "categoriaID")
+	if err != nil || categoriaID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	if err := fn(r.Context(), categoriaID); err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
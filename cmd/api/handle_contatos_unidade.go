@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/diligencias"
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+// handleContatoUnidadeList lista os contatos de RH das unidades. O parâmetro
+// "unidade" filtra pela sigla da unidade.
+func (app *application) handleContatoUnidadeList(w http.ResponseWriter, r *http.Request) {
+	contatos, err := app.diligencias.ListContatos(r.Context(), r.URL.Query().Get("unidade"))
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, contatos)
+}
+
+func (app *application) handleContatoUnidadeDetail(w http.ResponseWriter, r *http.Request) {
+	contatoID, err := app.intParam(r, "contatoID")
+	if err != nil || contatoID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	c, err := app.diligencias.GetContato(r.Context(), contatoID)
+	if err != nil {
+		app.contatoUnidadeError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, c)
+}
+
+type ContatoUnidadeRequest struct {
+	UnidadeSigla string `json:"unidade_sigla"`
+	Nome         string `json:"nome"`
+	Email        string `json:"email"`
+}
+
+func (app *application) decodeContatoUnidade(w http.ResponseWriter, r *http.Request) (diligencias.ContatoParams, bool) {
+	var input ContatoUnidadeRequest
+	if err := app.decodeJSON(w, r, &input); err != nil {
+		app.decodeError(w, r, err)
+		return diligencias.ContatoParams{}, false
+	}
+
+	params := diligencias.ContatoParams{
+		UnidadeSigla: input.UnidadeSigla,
+		Nome:         input.Nome,
+		Email:        input.Email,
+	}
+
+	v := validator.New()
+	params.Check(v)
+	if !v.Valid() {
+		app.validationFailed(w, r, v.FieldErrors)
+		return params, false
+	}
+	return params, true
+}
+
+func (app *application) handleContatoUnidadeCreate(w http.ResponseWriter, r *http.Request) {
+	params, ok := app.decodeContatoUnidade(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := app.diligencias.CreateContato(r.Context(), params)
+	if err != nil {
+		app.contatoUnidadeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/contatos-unidade/%d", c.ID))
+	app.writeJSON(w, http.StatusCreated, c)
+}
+
+func (app *application) handleContatoUnidadeUpdate(w http.ResponseWriter, r *http.Request) {
+	contatoID, err := app.intParam(r, "contatoID")
+	if err != nil || contatoID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	params, ok := app.decodeContatoUnidade(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := app.diligencias.UpdateContato(r.Context(), contatoID, params)
+	if err != nil {
+		app.contatoUnidadeError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, c)
+}
+
+func (app *application) handleContatoUnidadeDelete(w http.ResponseWriter, r *http.Request) {
+	contatoID, err := app.intParam(r, "contatoID")
+	if err != nil || contatoID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	if err := app.diligencias.DeleteContato(r.Context(), contatoID); err != nil {
+		app.contatoUnidadeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) contatoUnidadeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		app.notFound(w, r)
+	case errors.Is(err, database.ErrContatoUnidadeTaken):
+		app.alreadyExists(w, r, "Um contato com esse email já existe para a unidade")
+	default:
+		app.serverError(w, r, err)
+	}
+}
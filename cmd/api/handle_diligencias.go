@@ -1,6 +1,7 @@
 package main
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,6 +17,7 @@ type DiligenciaItemRequest struct {
 	Tipo          string   `json:"tipo"`
 	Subcategorias []string `json:"subcategorias"`
 	Detalhe       string   `json:"detalhe"`
+	ItemOrigemID  *int64   `json:"item_origem_id"`
 }
 
 // SalvarDiligenciaRequest representa o corpo da requisição para substituir
@@ -87,6 +89,7 @@ func (app *application) handleDiligenciaRascunhoSalvar(w http.ResponseWriter, r
 			Tipo:          it.Tipo,
 			Subcategorias: it.Subcategorias,
 			Detalhe:       it.Detalhe,
+			ItemOrigemID:  it.ItemOrigemID,
 		})
 	}
 
@@ -176,10 +179,81 @@ func (app *application) handleDiligenciaList(w http.ResponseWriter, r *http.Requ
 	app.writeJSON(w, http.StatusOK, list)
 }
 
+// AtendimentoItemRequest representa o resultado da verificação de um item da
+// última diligência enviada.
+type AtendimentoItemRequest struct {
+	ItemID     int64  `json:"item_id"`
+	Atendido   *bool  `json:"atendido"`
+	Observacao string `json:"observacao"`
+}
+
+// AtendimentoDiligenciaRequest representa o corpo da requisição para
+// registrar o atendimento dos itens da última diligência enviada.
+type AtendimentoDiligenciaRequest struct {
+	Itens []AtendimentoItemRequest `json:"itens"`
+
+	validator.Validator `json:"-"`
+}
+
+// handleDiligenciaAtendimento registra quais itens da última diligência
+// enviada foram atendidos pela unidade de origem, ajustando o rascunho ativo
+// para listar apenas os itens pendentes.
+func (app *application) handleDiligenciaAtendimento(w http.ResponseWriter, r *http.Request) {
+	pa := app.getProcessoAposentadoriaFromRequest(w, r)
+	if pa == nil {
+		return
+	}
+
+	var input AtendimentoDiligenciaRequest
+	if err := app.decodeJSON(w, r, &input); err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	input.Check(len(input.Itens) > 0, "itens", "Informe ao menos um item")
+	ids := make([]int64, len(input.Itens))
+	for i, it := range input.Itens {
+		ids[i] = it.ItemID
+		input.Check(it.ItemID > 0, fmt.Sprintf("itens[%d].item_id", i), "Campo obrigatório")
+		input.Check(it.Atendido != nil, fmt.Sprintf("itens[%d].atendido", i), "Campo obrigatório")
+	}
+	input.Check(validator.Unique(ids), "itens", "Itens repetidos")
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	resultados := make([]diligencias.ResultadoItem, len(input.Itens))
+	for i, it := range input.Itens {
+		resultados[i] = diligencias.ResultadoItem{
+			ItemID:     it.ItemID,
+			Atendido:   *it.Atendido,
+			Observacao: it.Observacao,
+		}
+	}
+
+	sd, err := app.diligencias.RegistrarAtendimento(r.Context(), diligencias.RegistrarAtendimentoParams{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              usuario.ID,
+		Itens:                   resultados,
+	})
+	if err != nil {
+		app.handleDiligenciaError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, sd)
+}
+
 // handleDiligenciaError traduz erros do service de diligências em respostas
 // HTTP adequadas.
 func (app *application) handleDiligenciaError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalidos *diligencias.ItensInvalidosError
 	switch {
+	case errors.As(err, &invalidos):
+		app.validationFailed(w, r, invalidos.FieldErrors)
 	case errors.Is(err, database.ErrNotFound):
 		app.notFound(w, r)
 	case errors.Is(err, diligencias.ErrNotAssigned):
@@ -194,3 +268,56 @@ func (app *application) handleDiligenciaError(w http.ResponseWriter, r *http.Req
 		app.serverError(w, r, err)
 	}
 }
+
+// handleDiligenciaEstatisticas agrega as diligências enviadas por categoria,
+// subcategoria, unidade ou órgão de origem, com a tendência por semana ou mês.
+func (app *application) handleDiligenciaEstatisticas(w http.ResponseWriter, r *http.Request) {
+	var v validator.Validator
+
+	qs := r.URL.Query()
+	params := diligencias.EstatisticasParams{
+		Agrupamento: cmp.Or(qs.Get("agrupamento"), diligencias.AgrupamentoCategoria),
+		Intervalo:   cmp.Or(qs.Get("intervalo"), diligencias.IntervaloMes),
+	}
+	v.Check(validator.PermittedValue(
+		params.Agrupamento,
+		diligencias.AgrupamentoCategoria,
+		diligencias.AgrupamentoSubcategoria,
+		diligencias.AgrupamentoUnidade,
+		diligencias.AgrupamentoOrgao,
+	), "agrupamento", "Agrupamento inválido")
+	v.Check(validator.PermittedValue(
+		params.Intervalo,
+		diligencias.IntervaloSemana,
+		diligencias.IntervaloMes,
+	), "intervalo", "Intervalo inválido")
+
+	inicio, err := app.dateQuery(r, "inicio")
+	v.Check(err == nil, "inicio", "Data inválida, use o formato AAAA-MM-DD")
+	params.Inicio = inicio
+	fim, err := app.dateQuery(r, "fim")
+	v.Check(err == nil, "fim", "Data inválida, use o formato AAAA-MM-DD")
+	if fim != nil {
+		f := fim.AddDate(0, 0, 1)
+		params.Fim = &f
+	}
+	if params.Inicio != nil && params.Fim != nil {
+		v.Check(params.Fim.After(*params.Inicio), "fim", "Deve ser posterior ao início")
+	}
+
+	if formato := qs.Get("formato"); formato != "" {
+		v.Check(validator.PermittedValue(formato, formatoJSON, formatoCSV, formatoXLSX), "formato", "Formato inválido")
+	}
+	if !v.Valid() {
+		app.validationFailed(w, r, v.FieldErrors)
+		return
+	}
+
+	est, err := app.diligencias.Estatisticas(r.Context(), params)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeRelatorio(w, r, "diligencias_"+params.Agrupamento, est)
+}
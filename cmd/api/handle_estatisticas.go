@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/automatiza-mg/fila/internal/fila"
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+func (app *application) handleEstatisticas(w http.ResponseWriter, r *http.Request) {
+	var v validator.Validator
+
+	inicio, err := app.dateQuery(r, "inicio")
+	v.Check(err == nil, "inicio", "Data inválida, use o formato AAAA-MM-DD")
+	fim, err := app.dateQuery(r, "fim")
+	v.Check(err == nil, "fim", "Data inválida, use o formato AAAA-MM-DD")
+	if inicio != nil && fim != nil {
+		v.Check(!fim.Before(*inicio), "fim", "Deve ser posterior ao início")
+	}
+	if !v.Valid() {
+		app.validationFailed(w, r, v.FieldErrors)
+		return
+	}
+
+	// O fim do período é inclusivo.
+	if fim != nil {
+		next := fim.AddDate(0, 0, 1)
+		fim = &next
+	}
+
+	est, err := app.fila.Estatisticas(r.Context(), fila.EstatisticasParams{
+		Inicio: inicio,
+		Fim:    fim,
+	})
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, est)
+}
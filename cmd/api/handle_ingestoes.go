@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/pagination"
+	"github.com/automatiza-mg/fila/internal/processos"
+)
+
+func (app *application) handleIngestaoList(w http.ResponseWriter, r *http.Request) {
+	params := pagination.ParseQuery(r)
+
+	result, err := app.processos.ListIngestoes(r.Context(), processos.ListIngestoesParams{
+		Page:  params.Page,
+		Limit: params.Limit,
+	})
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, result)
+}
+
+func (app *application) handleIngestaoDetail(w http.ResponseWriter, r *http.Request) {
+	ingestaoID, err := app.intParam(r, "ingestaoID")
+	if err != nil {
+		app.notFound(w, r)
+		return
+	}
+
+	ing, err := app.processos.GetIngestao(r.Context(), ingestaoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, ing)
+}
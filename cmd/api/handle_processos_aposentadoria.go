@@ -6,7 +6,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/automatiza-mg/fila/internal/auth"
 	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/automatiza-mg/fila/internal/fila"
 	"github.com/automatiza-mg/fila/internal/pagination"
@@ -30,10 +32,11 @@ func (app *application) handleProcessoAposentadoriaList(w http.ResponseWriter, r
 	params := pagination.ParseQuery(r)
 
 	result, err := app.fila.ListProcesso(r.Context(), fila.ListProcessoAposentadoriaParams{
-		Numero: r.URL.Query().Get("numero"),
-		Status: r.URL.Query().Get("status"),
-		Page:   params.Page,
-		Limit:  params.Limit,
+		Numero:            r.URL.Query().Get("numero"),
+		Status:            r.URL.Query().Get("status"),
+		DiligenciaVencida: r.URL.Query().Get("diligencia_vencida") == "true",
+		Page:              params.Page,
+		Limit:             params.Limit,
 	})
 	if err != nil {
 		app.serverError(w, r, err)
@@ -139,6 +142,38 @@ func (app *application) handleProcessoAposentadoriaHistorico(w http.ResponseWrit
 	app.writeJSON(w, http.StatusOK, historico)
 }
 
+func (app *application) handleProcessoAposentadoriaEventos(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	eventos, err := app.fila.ListEventos(r.Context(), paID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, eventos)
+}
+
+func (app *application) handleProcessoAposentadoriaHistoricoScore(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	historico, err := app.fila.ListHistoricoScore(r.Context(), paID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, historico)
+}
+
 func (app *application) handleAnalistaProcessoAtribuido(w http.ResponseWriter, r *http.Request) {
 	usuario := app.getUsuario(r.Context())
 
@@ -156,6 +191,21 @@ func (app *application) handleAnalistaProcessoAtribuido(w http.ResponseWriter, r
 	app.writeJSON(w, http.StatusOK, pa)
 }
 
+func (app *application) handleAnalistaProcessosAtribuidos(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getUsuario(r.Context())
+
+	paa, err := app.fila.ListProcessosAtribuidos(r.Context(), usuario.ID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, paa)
+}
+
+// handleMeuProcessoAtribuido retorna apenas o processo com o requerimento mais
+// antigo e é mantido por compatibilidade. A lista completa está em
+// GET /meus-processos.
 func (app *application) handleMeuProcessoAtribuido(w http.ResponseWriter, r *http.Request) {
 	usuario := app.getAuth(r.Context())
 
@@ -173,6 +223,18 @@ func (app *application) handleMeuProcessoAtribuido(w http.ResponseWriter, r *htt
 	app.writeJSON(w, http.StatusOK, pa)
 }
 
+func (app *application) handleMeusProcessosAtribuidos(w http.ResponseWriter, r *http.Request) {
+	usuario := app.getAuth(r.Context())
+
+	paa, err := app.fila.ListProcessosAtribuidos(r.Context(), usuario.ID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, paa)
+}
+
 func (app *application) handleMeuHistorico(w http.ResponseWriter, r *http.Request) {
 	usuario := app.getAuth(r.Context())
 	params := pagination.ParseQuery(r)
@@ -294,6 +356,250 @@ func (app *application) handleProcessoAposentadoriaLeituraInvalida(w http.Respon
 	w.WriteHeader(http.StatusNoContent)
 }
 
+type ReprocessarProcessoRequest struct {
+	Motivo string `json:"motivo"`
+}
+
+func (app *application) handleProcessoAposentadoriaReprocessar(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input ReprocessarProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.ReprocessarProcesso(r.Context(), paID, usuario.ID, input.Motivo)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		case errors.Is(err, fila.ErrInvalidStatus):
+			app.writeError(w, http.StatusConflict, "O processo não está no status esperado para esta ação")
+		case errors.Is(err, fila.ErrReprocessamentoEmAndamento):
+			app.writeError(w, http.StatusConflict, "O reprocessamento do processo já está em andamento")
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type CorrigirProcessoRequest struct {
+	Motivo           string  `json:"motivo"`
+	CPF              *string `json:"cpf_requerente"`
+	DataNascimento   *string `json:"data_nascimento_requerente"`
+	DataRequerimento *string `json:"data_requerimento"`
+	Invalidez        *bool   `json:"invalidez"`
+	Judicial         *bool   `json:"judicial"`
+
+	validator.Validator `json:"-"`
+}
+
+// correcao valida os campos informados, registrando os erros de validação, e
+// os converte em [fila.CorrecaoDados].
+func (input *CorrigirProcessoRequest) correcao() fila.CorrecaoDados {
+	if input.CPF != nil {
+		input.Check(validator.Matches(*input.CPF, validator.CpfRX), "cpf_requerente", "Deve ser um CPF válido")
+	}
+	return fila.CorrecaoDados{
+		CPF:              input.CPF,
+		DataNascimento:   input.parseData(input.DataNascimento, "data_nascimento_requerente"),
+		DataRequerimento: input.parseData(input.DataRequerimento, "data_requerimento"),
+		Invalidez:        input.Invalidez,
+		Judicial:         input.Judicial,
+	}
+}
+
+// parseData converte uma data no formato AAAA-MM-DD, registrando o erro de
+// validação no campo informado.
+func (input *CorrigirProcessoRequest) parseData(v *string, campo string) *time.Time {
+	if v == nil {
+		return nil
+	}
+	t, err := time.Parse(time.DateOnly, *v)
+	if err != nil {
+		input.SetFieldError(campo, "Deve ser uma data no formato AAAA-MM-DD")
+		return nil
+	}
+	return &t
+}
+
+func (app *application) handleProcessoAposentadoriaCorrigir(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input CorrigirProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	dados := input.correcao()
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.CorrigirLeituraInvalida(r.Context(), fila.CorrigirProcessoParams{
+		ProcessoID: paID,
+		GestorID:   usuario.ID,
+		Motivo:     input.Motivo,
+		Dados:      dados,
+	})
+	if err != nil {
+		var incompletos *fila.DadosIncompletosError
+		switch {
+		case errors.As(err, &incompletos):
+			app.validationFailed(w, r, incompletos.FieldErrors)
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		case errors.Is(err, fila.ErrInvalidStatus):
+			app.writeError(w, http.StatusConflict, "O processo não está no status esperado para esta ação")
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) handleProcessoAposentadoriaUpdate(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input CorrigirProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	dados := input.correcao()
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.CorrigirDados(r.Context(), fila.CorrigirDadosParams{
+		ProcessoID: paID,
+		UsuarioID:  usuario.ID,
+		Analista:   usuario.HasPapel(auth.PapelAnalista),
+		Motivo:     input.Motivo,
+		Dados:      dados,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		case errors.Is(err, fila.ErrNotAssigned):
+			app.writeError(w, http.StatusForbidden, "Você não possui permissão para alterar este processo")
+		case errors.Is(err, fila.ErrInvalidStatus):
+			app.writeError(w, http.StatusConflict, "O processo não está no status esperado para esta ação")
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	pa, err := app.fila.GetProcessoAposentadoria(r.Context(), paID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, pa)
+}
+
+func (app *application) handleProcessoAposentadoriaAlteracoes(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	alteracoes, err := app.fila.ListAlteracoesCampos(r.Context(), paID)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, alteracoes)
+}
+
+type DevolverProcessoRequest struct {
+	Motivo          string `json:"motivo"`
+	ExcluirAnalista bool   `json:"excluir_analista"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleProcessoAposentadoriaDevolver(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input DevolverProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	input.Check(validator.NotBlank(input.Motivo), "motivo", "Campo obrigatório")
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.DevolverProcesso(r.Context(), fila.DevolverProcessoParams{
+		AnalistaID:      usuario.ID,
+		ProcessoID:      paID,
+		Motivo:          input.Motivo,
+		ExcluirAnalista: input.ExcluirAnalista,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		case errors.Is(err, fila.ErrNotAssigned):
+			app.writeError(w, http.StatusForbidden, "Você não possui permissão para alterar este processo")
+		case errors.Is(err, fila.ErrInvalidStatus):
+			app.writeError(w, http.StatusConflict, "O processo não está no status esperado para esta ação")
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (app *application) handleProcessoAposentadoriaRegistrarPublicacao(w http.ResponseWriter, r *http.Request) {
 	paID, err := app.intParam(r, "paID")
 	if err != nil || paID < 1 {
@@ -320,3 +626,182 @@ func (app *application) handleProcessoAposentadoriaRegistrarPublicacao(w http.Re
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type ReatribuirProcessoRequest struct {
+	AnalistaID int64  `json:"analista_id"`
+	Motivo     string `json:"motivo"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleProcessoAposentadoriaReatribuir(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input ReatribuirProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	input.Check(input.AnalistaID > 0, "analista_id", "Campo obrigatório")
+	input.Check(validator.NotBlank(input.Motivo), "motivo", "Campo obrigatório")
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.ReatribuirProcesso(r.Context(), fila.ReatribuirProcessoParams{
+		ProcessoID: paID,
+		AnalistaID: input.AnalistaID,
+		GestorID:   usuario.ID,
+		Motivo:     input.Motivo,
+	})
+	if err != nil {
+		app.gestaoProcessoError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type DesatribuirProcessoRequest struct {
+	Motivo string `json:"motivo"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleProcessoAposentadoriaDesatribuir(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input DesatribuirProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	input.Check(validator.NotBlank(input.Motivo), "motivo", "Campo obrigatório")
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.DesatribuirProcesso(r.Context(), fila.DesatribuirProcessoParams{
+		ProcessoID: paID,
+		GestorID:   usuario.ID,
+		Motivo:     input.Motivo,
+	})
+	if err != nil {
+		app.gestaoProcessoError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type FixarProcessoRequest struct {
+	AnalistaID int64  `json:"analista_id"`
+	Motivo     string `json:"motivo"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleProcessoAposentadoriaFixar(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	var input FixarProcessoRequest
+	err = app.decodeJSON(w, r, &input)
+	if err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	input.Check(input.AnalistaID > 0, "analista_id", "Campo obrigatório")
+	input.Check(validator.NotBlank(input.Motivo), "motivo", "Campo obrigatório")
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	pf, err := app.fila.FixarProcesso(r.Context(), fila.FixarProcessoParams{
+		ProcessoID: paID,
+		AnalistaID: input.AnalistaID,
+		GestorID:   usuario.ID,
+		Motivo:     input.Motivo,
+	})
+	if err != nil {
+		app.gestaoProcessoError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, pf)
+}
+
+func (app *application) handleProcessoAposentadoriaDesafixar(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.DesafixarProcesso(r.Context(), paID, usuario.ID)
+	if err != nil {
+		app.gestaoProcessoError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) handleProcessoAposentadoriaLimparExclusoes(w http.ResponseWriter, r *http.Request) {
+	paID, err := app.intParam(r, "paID")
+	if err != nil || paID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	err = app.fila.LimparExclusoes(r.Context(), paID, usuario.ID)
+	if err != nil {
+		app.gestaoProcessoError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// gestaoProcessoError escreve a resposta de erro das ações de gestão da fila.
+func (app *application) gestaoProcessoError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		app.notFound(w, r)
+	case errors.Is(err, fila.ErrInvalidStatus):
+		app.writeError(w, http.StatusConflict, "O processo não está no status esperado para esta ação")
+	case errors.Is(err, fila.ErrAnalistaIndisponivel):
+		app.writeError(w, http.StatusConflict, "O analista está afastado ou já possui um processo em análise")
+	default:
+		app.serverError(w, r, err)
+	}
+}
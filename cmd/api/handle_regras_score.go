@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/automatiza-mg/fila/internal/aposentadoria"
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/fila"
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+func (app *application) handleRegrasScoreList(w http.ResponseWriter, r *http.Request) {
+	versoes, err := app.fila.ListVersoesRegrasScore(r.Context())
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, versoes)
+}
+
+func (app *application) handleRegrasScoreAtiva(w http.ResponseWriter, r *http.Request) {
+	v, err := app.fila.GetVersaoRegrasScoreAtiva(r.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, v)
+}
+
+func (app *application) handleRegrasScoreDetail(w http.ResponseWriter, r *http.Request) {
+	versaoID, err := app.intParam(r, "versaoID")
+	if err != nil || versaoID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	v, err := app.fila.GetVersaoRegrasScore(r.Context(), versaoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, v)
+}
+
+type RegrasScoreCreateRequest struct {
+	Descricao      string                        `json:"descricao"`
+	Precedencias   []aposentadoria.Precedencia   `json:"precedencias"`
+	Criterios      []aposentadoria.Criterio      `json:"criterios"`
+	Envelhecimento *aposentadoria.Envelhecimento `json:"envelhecimento"`
+	Ativar         bool                          `json:"ativar"`
+
+	validator.Validator `json:"-"`
+}
+
+func (app *application) handleRegrasScoreCreate(w http.ResponseWriter, r *http.Request) {
+	var input RegrasScoreCreateRequest
+	if err := app.decodeJSON(w, r, &input); err != nil {
+		app.decodeError(w, r, err)
+		return
+	}
+
+	regras := &aposentadoria.RegrasScore{
+		Precedencias:   input.Precedencias,
+		Criterios:      input.Criterios,
+		Envelhecimento: input.Envelhecimento,
+	}
+	if regras.Precedencias == nil {
+		regras.Precedencias = []aposentadoria.Precedencia{}
+	}
+	if regras.Criterios == nil {
+		regras.Criterios = []aposentadoria.Criterio{}
+	}
+
+	input.Check(validator.NotBlank(input.Descricao), "descricao", "Campo obrigatório")
+	regras.Check(&input.Validator)
+	if !input.Valid() {
+		app.validationFailed(w, r, input.FieldErrors)
+		return
+	}
+
+	usuario := app.getAuth(r.Context())
+
+	v, err := app.fila.CreateVersaoRegrasScore(r.Context(), fila.CreateVersaoRegrasScoreParams{
+		Descricao: input.Descricao,
+		Regras:    regras,
+		UsuarioID: usuario.ID,
+		Ativar:    input.Ativar,
+	})
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/regras-score/%d", v.ID))
+	app.writeJSON(w, http.StatusCreated, v)
+}
+
+func (app *application) handleRegrasScoreAtivar(w http.ResponseWriter, r *http.Request) {
+	versaoID, err := app.intParam(r, "versaoID")
+	if err != nil || versaoID < 1 {
+		app.notFound(w, r)
+		return
+	}
+
+	err = app.fila.AtivarVersaoRegrasScore(r.Context(), versaoID)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			app.notFound(w, r)
+		default:
+			app.serverError(w, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
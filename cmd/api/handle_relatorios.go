@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+const (
+	formatoJSON = "json"
+	formatoCSV  = "csv"
+	formatoXLSX = "xlsx"
+)
+
+// exportavel é um relatório que pode ser exportado em CSV e XLSX.
+type exportavel interface {
+	WriteCSV(w io.Writer) error
+	WriteXLSX(w io.Writer) error
+}
+
+// periodoRelatorio lê o período de um relatório da query string. O período é
+// informado por "mes" (AAAA-MM) ou por "inicio" e "fim" (AAAA-MM-DD, ambos
+// inclusivos). Sem parâmetros, considera o mês corrente. O fim retornado não
+// pertence ao período.
+func (app *application) periodoRelatorio(r *http.Request, v *validator.Validator) (inicio, fim time.Time) {
+	if mes := r.URL.Query().Get("mes"); mes != "" {
+		t, err := time.ParseInLocation("2006-01", mes, time.Local)
+		v.Check(err == nil, "mes", "Mês inválido, use o formato AAAA-MM")
+		return t, t.AddDate(0, 1, 0)
+	}
+
+	now := time.Now()
+	inicio = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	fim = inicio.AddDate(0, 1, 0)
+
+	i, err := app.dateQuery(r, "inicio")
+	v.Check(err == nil, "inicio", "Data inválida, use o formato AAAA-MM-DD")
+	if i != nil {
+		inicio = *i
+	}
+	f, err := app.dateQuery(r, "fim")
+	v.Check(err == nil, "fim", "Data inválida, use o formato AAAA-MM-DD")
+	if f != nil {
+		fim = f.AddDate(0, 0, 1)
+	}
+
+	v.Check(fim.After(inicio), "fim", "Deve ser posterior ao início")
+	return inicio, fim
+}
+
+// writeRelatorio escreve o relatório no formato solicitado em "formato". CSV e
+// XLSX são enviados como anexo com o nome informado.
+func (app *application) writeRelatorio(w http.ResponseWriter, r *http.Request, nome string, rel exportavel) {
+	var (
+		buf         bytes.Buffer
+		contentType string
+		err         error
+	)
+
+	switch r.URL.Query().Get("formato") {
+	case formatoCSV:
+		contentType = "text/csv; charset=utf-8"
+		nome += ".csv"
+		err = rel.WriteCSV(&buf)
+	case formatoXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		nome += ".xlsx"
+		err = rel.WriteXLSX(&buf)
+	default:
+		app.writeJSON(w, http.StatusOK, rel)
+		return
+	}
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", nome))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func (app *application) handleRelatorioProdutividade(w http.ResponseWriter, r *http.Request) {
+	var v validator.Validator
+
+	inicio, fim := app.periodoRelatorio(r, &v)
+	formato := r.URL.Query().Get("formato")
+	if formato != "" {
+		v.Check(validator.PermittedValue(formato, formatoJSON, formatoCSV, formatoXLSX), "formato", "Formato inválido")
+	}
+	if !v.Valid() {
+		app.validationFailed(w, r, v.FieldErrors)
+		return
+	}
+
+	rel, err := app.relatorios.Produtividade(r.Context(), inicio, fim)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	nome := fmt.Sprintf("produtividade_%s_%s", inicio.Format(time.DateOnly), fim.AddDate(0, 0, -1).Format(time.DateOnly))
+	app.writeRelatorio(w, r, nome, rel)
+}
+
+func (app *application) handleRelatorioDevolucoes(w http.ResponseWriter, r *http.Request) {
+	var v validator.Validator
+
+	inicio, fim := app.periodoRelatorio(r, &v)
+	formato := r.URL.Query().Get("formato")
+	if formato != "" {
+		v.Check(validator.PermittedValue(formato, formatoJSON, formatoCSV, formatoXLSX), "formato", "Formato inválido")
+	}
+	if !v.Valid() {
+		app.validationFailed(w, r, v.FieldErrors)
+		return
+	}
+
+	rel, err := app.relatorios.Devolucoes(r.Context(), inicio, fim)
+	if err != nil {
+		app.serverError(w, r, err)
+		return
+	}
+
+	nome := fmt.Sprintf("devolucoes_%s_%s", inicio.Format(time.DateOnly), fim.AddDate(0, 0, -1).Format(time.DateOnly))
+	app.writeRelatorio(w, r, nome, rel)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/automatiza-mg/fila/internal/mail"
 	"github.com/automatiza-mg/fila/internal/postgres"
 	"github.com/automatiza-mg/fila/internal/processos"
+	"github.com/automatiza-mg/fila/internal/relatorios"
 	"github.com/automatiza-mg/fila/internal/sei"
 	"github.com/automatiza-mg/fila/internal/tasks"
 	"github.com/jackc/pgx/v5"
@@ -62,6 +63,7 @@ type application struct {
 	diligencias *diligencias.Service
 	fila        *fila.Service
 	processos   *processos.Service
+	relatorios  *relatorios.Service
 }
 
 func run(ctx context.Context) error {
@@ -119,9 +121,9 @@ func run(ctx context.Context) error {
 	proc := processos.New(pool, storage, sei, cache, queue)
 	apos := aposentadoria.New(pool, dl, cache)
 	auth := auth.New(pool, logger, queue)
-	anali := analista.New(pool, logger, sei, cache)
-	fila := fila.New(pool, queue, sei)
-	dil := diligencias.New(pool, logger)
+	anali := analista.New(pool, logger, sei, cache, queue)
+	fila := fila.New(pool, queue, cache, &cfg.Fila)
+	dil := diligencias.New(pool, logger, queue, &cfg.Diligencias)
 
 	if err := auth.RegisterHook(fila); err != nil {
 		return err
@@ -133,7 +135,72 @@ func run(ctx context.Context) error {
 	river.AddWorker(workers, tasks.NewDownloadPreviewWorker(pool, storage, sei, di))
 	river.AddWorker(workers, tasks.NewAnalisarProcessoWorker(pool, logger, ai, dl, apos))
 	river.AddWorker(workers, tasks.NewRecalcularScoresWorker(pool))
-	worker, err := tasks.NewWorker(ctx, pool, workers)
+	river.AddWorker(workers, tasks.NewVerificarRetornoDiligenciaWorker(pool, logger, sei))
+	river.AddWorker(workers, tasks.NewEnviarProcessoSEIWorker(pool, sei))
+	river.AddWorker(workers, tasks.NewIncluirOficioDiligenciaWorker(pool, sei))
+	river.AddWorker(workers, tasks.NewNotificarPrazosWorker(pool, logger, sender, &cfg.Fila.Prazos, cfg.ClientURL.String()))
+	river.AddWorker(workers, tasks.NewLembrarDiligenciasWorker(pool, logger, cfg.Diligencias.Lembretes))
+	river.AddWorker(workers, tasks.NewAtribuirProcessosWorker(logger, fila.AtribuirProcessos, fila.LiberarExclusaoTemporaria))
+	river.AddWorker(workers, tasks.NewAplicarAfastamentosWorker(logger, fila.AplicarAfastamentos))
+	river.AddWorker(workers, tasks.NewIngerirProcessosWorker(pool, logger, dl, func(ctx context.Context, numero string) error {
+		_, err := proc.CreateProcesso(ctx, numero)
+		return err
+	}, &cfg.Ingestao))
+
+	periodicJobs := []*river.PeriodicJob{
+		river.NewPeriodicJob(
+			river.PeriodicInterval(time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return tasks.VerificarRetornoDiligenciaArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(cfg.Fila.AfastamentosIntervalo),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return tasks.AplicarAfastamentosArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		// Os jobs diários também executam na inicialização, para que uma
+		// implantação não adie a próxima execução em mais um dia. A unicidade
+		// de cada job evita execuções repetidas no mesmo período.
+		//
+		// O recálculo diário mantém atualizados os pontos de envelhecimento,
+		// que dependem do tempo de espera de cada processo.
+		river.NewPeriodicJob(
+			river.PeriodicInterval(24*time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return tasks.RecalcularScoresArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(24*time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return tasks.NotificarPrazosArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(24*time.Hour),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return tasks.LembrarDiligenciasArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+	}
+	if cfg.Ingestao.Enabled {
+		periodicJobs = append(periodicJobs, river.NewPeriodicJob(
+			river.PeriodicInterval(cfg.Ingestao.Intervalo),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return tasks.IngerirProcessosArgs{}, nil
+			},
+			nil,
+		))
+	}
+
+	worker, err := tasks.NewWorker(ctx, pool, workers, tasks.WithPeriodicJobs(periodicJobs...))
 	if err != nil {
 		return err
 	}
@@ -152,9 +219,10 @@ func run(ctx context.Context) error {
 		auth:        auth,
 		diligencias: dil,
 		processos:   proc,
+		relatorios:  relatorios.New(pool),
 	}
 
-	fila.StartAtribuicaoWorker(ctx, 30*time.Second)
+	fila.StartAtribuicaoWorker(ctx, cfg.Fila.AtribuicaoIntervalo)
 
 	srv := &http.Server{
 		Addr:         *addr,
@@ -68,10 +68,16 @@ func (app *application) routes() http.Handler {
 
 				r.Get("/analista", app.handleAnalistaDetail)
 				r.Post("/analista", app.handleAnalistaCreate)
+				r.Put("/analista/competencias", app.handleAnalistaCompetencias)
+				r.Put("/analista/limite-processos", app.handleAnalistaLimiteProcessos)
 
 				r.Post("/analista/afastar", app.handleAnalistaAfastar)
 				r.Post("/analista/retornar", app.handleAnalistaRetornar)
+				r.Get("/analista/afastamentos", app.handleAnalistaAfastamentoList)
+				r.Post("/analista/afastamentos", app.handleAnalistaAfastamentoProgramar)
+				r.Delete("/analista/afastamentos/{afastamentoID}", app.handleAnalistaAfastamentoCancelar)
 				r.Get("/analista/processo", app.handleAnalistaProcessoAtribuido)
+				r.Get("/analista/processos", app.handleAnalistaProcessosAtribuidos)
 			})
 		})
 
@@ -83,6 +89,8 @@ func (app *application) routes() http.Handler {
 
 			r.Get("/", app.handleProcessoList)
 			r.Post("/", app.handleProcessoCreate)
+			r.Get("/ingestoes", app.handleIngestaoList)
+			r.Get("/ingestoes/{ingestaoID}", app.handleIngestaoDetail)
 			r.Get("/{processoID}", app.handleProcessoDetail)
 			r.Get("/{processoID}/documentos", app.handleProcessoDetailDocumentos)
 			r.Post("/{processoID}/preview/refresh", app.handleProcessoRefreshPreview)
@@ -96,24 +104,93 @@ func (app *application) routes() http.Handler {
 
 			r.Get("/", app.handleProcessoAposentadoriaList)
 			r.Get("/{paID}", app.handleProcessoAposentadoriaDetail)
+			r.Patch("/{paID}", app.handleProcessoAposentadoriaUpdate)
 			r.Get("/{paID}/historico", app.handleProcessoAposentadoriaHistorico)
+			r.Get("/{paID}/eventos", app.handleProcessoAposentadoriaEventos)
+			r.Get("/{paID}/historico-score", app.handleProcessoAposentadoriaHistoricoScore)
+			r.Get("/{paID}/alteracoes", app.handleProcessoAposentadoriaAlteracoes)
 			r.Post("/{paID}/prioridade", app.handleProcessoAposentadoriaSolicitarPrioridade)
 			r.Get("/{paID}/preview", app.handleAposentadoriaPreview)
 			r.Post("/{paID}/leitura-invalida", app.handleProcessoAposentadoriaLeituraInvalida)
 			r.Post("/{paID}/publicar", app.handleProcessoAposentadoriaRegistrarPublicacao)
+			r.Post("/{paID}/devolver", app.handleProcessoAposentadoriaDevolver)
 
 			r.Get("/{paID}/diligencias", app.handleDiligenciaList)
 			r.Get("/{paID}/diligencias/rascunho", app.handleDiligenciaRascunhoGet)
 			r.Put("/{paID}/diligencias/rascunho", app.handleDiligenciaRascunhoSalvar)
 			r.Delete("/{paID}/diligencias/rascunho", app.handleDiligenciaRascunhoDescartar)
 			r.Post("/{paID}/diligencias/rascunho/enviar", app.handleDiligenciaRascunhoEnviar)
+			r.Post("/{paID}/diligencias/atendimento", app.handleDiligenciaAtendimento)
 
 			r.Group(func(r chi.Router) {
 				r.Use(app.requirePapel(auth.PapelGestor, auth.PapelSubsecretario))
 				r.Post("/recalcular-scores", app.handleRecalcularScores)
+				r.Get("/estatisticas", app.handleEstatisticas)
+				r.Post("/{paID}/reatribuir", app.handleProcessoAposentadoriaReatribuir)
+				r.Post("/{paID}/desatribuir", app.handleProcessoAposentadoriaDesatribuir)
+				r.Put("/{paID}/fixacao", app.handleProcessoAposentadoriaFixar)
+				r.Delete("/{paID}/fixacao", app.handleProcessoAposentadoriaDesafixar)
+				r.Delete("/{paID}/exclusoes", app.handleProcessoAposentadoriaLimparExclusoes)
+				r.Post("/{paID}/reprocessar", app.handleProcessoAposentadoriaReprocessar)
+				r.Post("/{paID}/corrigir", app.handleProcessoAposentadoriaCorrigir)
 			})
 		})
 
+		r.Route("/relatorios", func(r chi.Router) {
+			r.Use(
+				app.requireAuth,
+				app.requirePapel(auth.PapelGestor, auth.PapelSubsecretario),
+			)
+
+			r.Get("/produtividade", app.handleRelatorioProdutividade)
+			r.Get("/diligencias", app.handleDiligenciaEstatisticas)
+			r.Get("/devolucoes", app.handleRelatorioDevolucoes)
+		})
+
+		r.Route("/regras-score", func(r chi.Router) {
+			r.Use(
+				app.requireAuth,
+				app.requirePapel(auth.PapelGestor, auth.PapelSubsecretario),
+			)
+
+			r.Get("/", app.handleRegrasScoreList)
+			r.Get("/ativa", app.handleRegrasScoreAtiva)
+			r.Get("/{versaoID}", app.handleRegrasScoreDetail)
+
+			r.Group(func(r chi.Router) {
+				r.Use(app.requirePapel(auth.PapelSubsecretario))
+				r.Post("/", app.handleRegrasScoreCreate)
+				r.Post("/{versaoID}/ativar", app.handleRegrasScoreAtivar)
+			})
+		})
+
+		r.Route("/categorias-diligencia", func(r chi.Router) {
+			r.Use(app.requireAuth)
+
+			r.Get("/", app.handleCategoriaDiligenciaList)
+			r.Get("/{categoriaID}", app.handleCategoriaDiligenciaDetail)
+
+			r.Group(func(r chi.Router) {
+				r.Use(app.requirePapel(auth.PapelGestor, auth.PapelSubsecretario))
+				r.Post("/", app.handleCategoriaDiligenciaCreate)
+				r.Post("/{categoriaID}/ativar", app.handleCategoriaDiligenciaAtivar)
+				r.Post("/{categoriaID}/desativar", app.handleCategoriaDiligenciaDesativar)
+			})
+		})
+
+		r.Route("/contatos-unidade", func(r chi.Router) {
+			r.Use(
+				app.requireAuth,
+				app.requirePapel(auth.PapelGestor, auth.PapelSubsecretario),
+			)
+
+			r.Get("/", app.handleContatoUnidadeList)
+			r.Post("/", app.handleContatoUnidadeCreate)
+			r.Get("/{contatoID}", app.handleContatoUnidadeDetail)
+			r.Put("/{contatoID}", app.handleContatoUnidadeUpdate)
+			r.Delete("/{contatoID}", app.handleContatoUnidadeDelete)
+		})
+
 		r.Route("/servidores", func(r chi.Router) {
 			r.Use(app.requireAuth)
 
@@ -170,7 +247,11 @@ func (app *application) routes() http.Handler {
 		r.Group(func(r chi.Router) {
 			r.Use(app.requireAuth)
 			r.Get("/meu-processo", app.handleMeuProcessoAtribuido)
+			r.Get("/meus-processos", app.handleMeusProcessosAtribuidos)
 			r.Get("/meu-historico", app.handleMeuHistorico)
+			r.Get("/meus-afastamentos", app.handleMeusAfastamentosList)
+			r.Post("/meus-afastamentos", app.handleMeusAfastamentosProgramar)
+			r.Delete("/meus-afastamentos/{afastamentoID}", app.handleMeusAfastamentosCancelar)
 		})
 	})
 
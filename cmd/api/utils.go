@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 const (
@@ -51,3 +52,17 @@ func (app *application) intParam(r *http.Request, key string) (int64, error) {
 	}
 	return v, nil
 }
+
+// dateQuery lê uma data no formato AAAA-MM-DD da query string. Retorna nil
+// quando o parâmetro não é informado.
+func (app *application) dateQuery(r *http.Request, key string) (*time.Time, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/automatiza-mg/fila/internal/aposentadoria"
+	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -29,6 +30,13 @@ func main() {
 	}
 	defer pool.Close()
 
+	// O score é calculado com a versão de regras ativa, a mesma usada no
+	// recálculo dos processos.
+	versao, err := aposentadoria.LoadVersaoAtiva(context.Background(), database.New(pool))
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	q := `
 	SELECT 
 		numero_processo, data_nascimento, data_requerimento, invalidez, judicial, score
@@ -56,7 +64,12 @@ func main() {
 			log.Fatal(err)
 		}
 
-		score := aposentadoria.CalculateScore(processo.DataNascimento, processo.Invalidez, processo.Judicial, false)
+		score := versao.Calcular(&database.ProcessoAposentadoria{
+			DataNascimentoRequerente: processo.DataNascimento,
+			DataRequerimento:         processo.DataRequerimento,
+			Invalidez:                processo.Invalidez,
+			Judicial:                 processo.Judicial,
+		}, time.Now()).Score
 
 		q := `UPDATE processos_aposentadoria_teste SET score = $2 WHERE numero_processo = $1`
 		_, err = pool.Exec(context.Background(), q, processo.NumeroProcesso, score)
@@ -71,5 +84,5 @@ func main() {
 		log.Fatal(err)
 	}
 
-	log.Printf("Total de %d processos atualizados", total)
+	log.Printf("Total de %d processos atualizados com a versão %d das regras", total, versao.Versao)
 }
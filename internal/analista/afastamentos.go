@@ -0,0 +1,160 @@
+package analista
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+var (
+	// ErrAfastamentoSobreposto é o erro retornado quando o período informado
+	// se sobrepõe a outro afastamento programado do analista.
+	ErrAfastamentoSobreposto = errors.New("afastamento sobreposto a outro afastamento programado")
+	// ErrAfastamentoIniciado é o erro retornado ao cancelar um afastamento
+	// programado que já foi iniciado, encerrado ou cancelado.
+	ErrAfastamentoIniciado = errors.New("afastamento programado já iniciado")
+	// ErrAfastamentoPassado é o erro retornado ao programar um afastamento
+	// com início no passado.
+	ErrAfastamentoPassado = errors.New("início do afastamento no passado")
+)
+
+// AfastamentoProgramado é um período futuro de afastamento do analista. O
+// analista é afastado automaticamente no início e retorna no fim do período.
+type AfastamentoProgramado struct {
+	ID          int64      `json:"id"`
+	AnalistaID  int64      `json:"analista_id"`
+	Inicio      time.Time  `json:"inicio"`
+	Fim         time.Time  `json:"fim"`
+	Motivo      string     `json:"motivo"`
+	CriadoPor   *int64     `json:"criado_por"`
+	CriadoEm    time.Time  `json:"criado_em"`
+	IniciadoEm  *time.Time `json:"iniciado_em"`
+	EncerradoEm *time.Time `json:"encerrado_em"`
+	CanceladoEm *time.Time `json:"cancelado_em"`
+}
+
+func mapAfastamentoProgramado(ap *database.AfastamentoProgramado) *AfastamentoProgramado {
+	return &AfastamentoProgramado{
+		ID:          ap.ID,
+		AnalistaID:  ap.AnalistaID,
+		Inicio:      ap.Inicio,
+		Fim:         ap.Fim,
+		Motivo:      ap.Motivo,
+		CriadoPor:   database.Ptr(ap.CriadoPor),
+		CriadoEm:    ap.CriadoEm,
+		IniciadoEm:  database.Ptr(ap.IniciadoEm),
+		EncerradoEm: database.Ptr(ap.EncerradoEm),
+		CanceladoEm: database.Ptr(ap.CanceladoEm),
+	}
+}
+
+// ProgramarAfastamentoParams são os parâmetros para programar um afastamento.
+type ProgramarAfastamentoParams struct {
+	UsuarioID int64
+	Inicio    time.Time
+	Fim       time.Time
+	Motivo    string
+	// CriadoPor é o usuário que programou o afastamento, o próprio analista
+	// ou um gestor.
+	CriadoPor int64
+}
+
+// ProgramarAfastamento registra um período de afastamento do analista, que é
+// aplicado automaticamente pelo job de afastamentos. Retorna
+// [ErrAfastamentoPassado] se o início já tiver passado,
+// [database.ErrNotFound] se o usuário não for analista e
+// [ErrAfastamentoSobreposto] se o período coincidir com outro afastamento
+// programado. Afastamentos imediatos são registrados por [Service.AfastarAnalista].
+func (s *Service) ProgramarAfastamento(ctx context.Context, params ProgramarAfastamentoParams) (*AfastamentoProgramado, error) {
+	if params.Inicio.Before(time.Now()) {
+		return nil, ErrAfastamentoPassado
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	r, err := store.GetAnalista(ctx, params.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	sobreposto, err := store.HasAfastamentoProgramadoSobreposto(ctx, r.UsuarioID, params.Inicio, params.Fim)
+	if err != nil {
+		return nil, err
+	}
+	if sobreposto {
+		return nil, ErrAfastamentoSobreposto
+	}
+
+	ap := &database.AfastamentoProgramado{
+		AnalistaID: r.UsuarioID,
+		Inicio:     params.Inicio,
+		Fim:        params.Fim,
+		Motivo:     params.Motivo,
+		CriadoPor:  sql.Null[int64]{V: params.CriadoPor, Valid: true},
+	}
+	if err := store.SaveAfastamentoProgramado(ctx, ap); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return mapAfastamentoProgramado(ap), nil
+}
+
+// ListAfastamentosProgramados retorna os afastamentos programados do analista,
+// do mais recente para o mais antigo.
+func (s *Service) ListAfastamentosProgramados(ctx context.Context, usuarioID int64) ([]*AfastamentoProgramado, error) {
+	rr, err := s.store.ListAfastamentosProgramados(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	aa := make([]*AfastamentoProgramado, len(rr))
+	for i, r := range rr {
+		aa[i] = mapAfastamentoProgramado(r)
+	}
+	return aa, nil
+}
+
+// CancelarAfastamentoProgramado cancela um afastamento programado que ainda
+// não foi iniciado. Retorna [database.ErrNotFound] se o afastamento não
+// pertencer ao analista e [ErrAfastamentoIniciado] se já tiver sido iniciado.
+// Afastamentos em curso são encerrados pelo retorno do analista.
+func (s *Service) CancelarAfastamentoProgramado(ctx context.Context, usuarioID, afastamentoID int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	ap, err := store.GetAfastamentoProgramado(ctx, afastamentoID)
+	if err != nil {
+		return err
+	}
+	if ap.AnalistaID != usuarioID {
+		return database.ErrNotFound
+	}
+	if ap.IniciadoEm.Valid || ap.EncerradoEm.Valid || ap.CanceladoEm.Valid {
+		return ErrAfastamentoIniciado
+	}
+
+	ap.CanceladoEm = sql.Null[time.Time]{V: time.Now(), Valid: true}
+	if err := store.UpdateAfastamentoProgramado(ctx, ap); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
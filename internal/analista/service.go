@@ -12,7 +12,11 @@ import (
 	"github.com/automatiza-mg/fila/internal/cache"
 	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/automatiza-mg/fila/internal/sei"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
 )
 
 var (
@@ -26,6 +30,25 @@ var (
 		"SEPLAG",
 		"SEE",
 	}
+
+	// AllowedCompetencias são as competências que podem ser atribuídas aos
+	// analistas.
+	AllowedCompetencias = []string{
+		database.CompetenciaJudicial,
+		database.CompetenciaInvalidez,
+		database.CompetenciaOrgaoSEE,
+	}
+
+	// MaxLimiteProcessos é o maior limite de processos em análise que pode ser
+	// definido para um analista.
+	MaxLimiteProcessos = 5
+
+	// AllowedNiveis são os níveis de senioridade dos analistas.
+	AllowedNiveis = []string{
+		database.NivelJunior,
+		database.NivelPleno,
+		database.NivelSenior,
+	}
 )
 
 // SeiClient define a interface para comunicação com o SEI.
@@ -33,6 +56,11 @@ type SeiClient interface {
 	ListarUnidades(ctx context.Context) (*sei.ListarUnidadesResponse, error)
 }
 
+// TaskInserter define a interface para inserção de tarefas na fila.
+type TaskInserter interface {
+	InsertTx(ctx context.Context, tx pgx.Tx, args river.JobArgs, opts *river.InsertOpts) (*rivertype.JobInsertResult, error)
+}
+
 // Analista representa os dados complementares de um usuário analista.
 type Analista struct {
 	UsuarioID          int64      `json:"usuario_id"`
@@ -41,6 +69,9 @@ type Analista struct {
 	SeiUnidadeSigla    string     `json:"sei_unidade_sigla"`
 	Afastado           bool       `json:"afastado"`
 	UltimaAtribuicaoEm *time.Time `json:"ultima_atribuicao_em"`
+	Competencias       []string   `json:"competencias"`
+	Nivel              string     `json:"nivel"`
+	LimiteProcessos    int        `json:"limite_processos"`
 }
 
 func mapAnalista(a *database.Analista) *Analista {
@@ -51,6 +82,9 @@ func mapAnalista(a *database.Analista) *Analista {
 		SeiUnidadeSigla:    a.SEIUnidadeSigla,
 		Afastado:           a.Afastado,
 		UltimaAtribuicaoEm: database.Ptr(a.UltimaAtribuicaoEm),
+		Competencias:       a.Competencias,
+		Nivel:              a.Nivel,
+		LimiteProcessos:    a.LimiteProcessos,
 	}
 }
 
@@ -61,24 +95,40 @@ type Service struct {
 	logger *slog.Logger
 	sei    SeiClient
 	cache  cache.Cache
+	queue  TaskInserter
 }
 
 // New cria uma nova instância de [Service].
-func New(pool *pgxpool.Pool, logger *slog.Logger, sei SeiClient, cache cache.Cache) *Service {
+func New(pool *pgxpool.Pool, logger *slog.Logger, sei SeiClient, cache cache.Cache, queue TaskInserter) *Service {
 	return &Service{
 		pool:   pool,
 		store:  database.New(pool),
 		logger: logger.With(slog.String("service", "analistas")),
 		sei:    sei,
 		cache:  cache,
+		queue:  queue,
 	}
 }
 
+// agendarAtribuicao agenda a atribuição de processos na transação informada,
+// já que o analista passou a poder receber processos.
+func (s *Service) agendarAtribuicao(ctx context.Context, tx pgx.Tx) error {
+	_, err := s.queue.InsertTx(ctx, tx, tasks.AtribuirProcessosArgs{}, nil)
+	return err
+}
+
 // CreateAnalistaParams são os parâmetros para criação de um analista.
 type CreateAnalistaParams struct {
 	UsuarioID    int64
 	SeiUnidadeID string
 	Orgao        string
+	Competencias []string
+	// Nivel é o nível de senioridade do analista. Se vazio, é usado
+	// [database.NivelPleno].
+	Nivel string
+	// LimiteProcessos é a quantidade máxima de processos em análise ao mesmo
+	// tempo. Se zero, o analista recebe um processo por vez.
+	LimiteProcessos int
 }
 
 // CreateAnalista cadastra os dados de analista para um determinado usuário.
@@ -105,16 +155,32 @@ func (s *Service) CreateAnalista(ctx context.Context, params CreateAnalistaParam
 		return nil, ErrInvalidUnidade
 	}
 
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	record := &database.Analista{
 		UsuarioID:       params.UsuarioID,
 		Orgao:           params.Orgao,
 		SEIUnidadeID:    unidade.ID,
 		SEIUnidadeSigla: unidade.Sigla,
+		Competencias:    params.Competencias,
+		Nivel:           params.Nivel,
+		LimiteProcessos: params.LimiteProcessos,
 	}
-	err = s.store.SaveAnalista(ctx, record)
+	err = s.store.WithTx(tx).SaveAnalista(ctx, record)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 
 	return mapAnalista(record), nil
 }
@@ -128,26 +194,138 @@ func (s *Service) GetAnalista(ctx context.Context, usuarioID int64) (*Analista,
 	return mapAnalista(r), nil
 }
 
-// AfastarAnalista marca um analista como afastado, não podendo receber novos processos.
+// AfastarAnalista marca um analista como afastado, não podendo receber novos
+// processos. O início do afastamento é registrado para os relatórios de
+// produtividade.
 func (s *Service) AfastarAnalista(ctx context.Context, usuarioID int64) error {
-	r, err := s.store.GetAnalista(ctx, usuarioID)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	r, err := store.GetAnalista(ctx, usuarioID)
 	if err != nil {
 		return err
 	}
 
 	r.Afastado = true
-	return s.store.UpdateAnalista(ctx, r)
+	if err := store.UpdateAnalista(ctx, r); err != nil {
+		return err
+	}
+	if err := store.IniciarAfastamentoAnalista(ctx, usuarioID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-// RetornarAnalista marca um analista como não afastado, podendo receber novos processos.
+// RetornarAnalista marca um analista como não afastado, podendo receber novos
+// processos. O afastamento em aberto e os afastamentos programados em curso
+// são encerrados.
 func (s *Service) RetornarAnalista(ctx context.Context, usuarioID int64) error {
-	r, err := s.store.GetAnalista(ctx, usuarioID)
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	r, err := store.GetAnalista(ctx, usuarioID)
 	if err != nil {
 		return err
 	}
 
 	r.Afastado = false
-	return s.store.UpdateAnalista(ctx, r)
+	if err := store.UpdateAnalista(ctx, r); err != nil {
+		return err
+	}
+	if err := store.EncerrarAfastamentoAnalista(ctx, usuarioID); err != nil {
+		return err
+	}
+	if err := store.EncerrarAfastamentosProgramadosEmCurso(ctx, usuarioID, time.Now()); err != nil {
+		return err
+	}
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// AtualizarCompetenciasParams são os parâmetros para atualização das
+// competências de um analista.
+type AtualizarCompetenciasParams struct {
+	UsuarioID    int64
+	Competencias []string
+	Nivel        string
+}
+
+// AtualizarCompetencias substitui as competências e o nível de senioridade de
+// um analista, que são usados para definir os processos que ele pode receber.
+func (s *Service) AtualizarCompetencias(ctx context.Context, params AtualizarCompetenciasParams) (*Analista, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	r, err := store.GetAnalista(ctx, params.UsuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Competencias = params.Competencias
+	r.Nivel = params.Nivel
+	if err := store.UpdateAnalista(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return mapAnalista(r), nil
+}
+
+// AtualizarLimiteProcessos altera a quantidade máxima de processos que o
+// analista pode manter em análise ao mesmo tempo. Processos já atribuídos não
+// são afetados pela redução do limite.
+func (s *Service) AtualizarLimiteProcessos(ctx context.Context, usuarioID int64, limite int) (*Analista, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	r, err := store.GetAnalista(ctx, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.LimiteProcessos = limite
+	if err := store.UpdateAnalista(ctx, r); err != nil {
+		return nil, err
+	}
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return mapAnalista(r), nil
 }
 
 // ListAnalistas retorna os dados dos analistas da aplicação.
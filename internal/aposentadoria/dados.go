@@ -0,0 +1,94 @@
+package aposentadoria
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+// Campos dos dados do requerente registrados na trilha de auditoria.
+const (
+	CampoCPF              = "cpf_requerente"
+	CampoDataNascimento   = "data_nascimento_requerente"
+	CampoDataRequerimento = "data_requerimento"
+	CampoInvalidez        = "invalidez"
+	CampoJudicial         = "judicial"
+)
+
+// DadosRequerente são os dados extraídos de um processo de aposentadoria que
+// podem ser corrigidos e que determinam o score do processo.
+type DadosRequerente struct {
+	CPF              string
+	DataNascimento   time.Time
+	DataRequerimento time.Time
+	Invalidez        bool
+	Judicial         bool
+}
+
+// DadosRequerenteProcesso retorna os dados do requerente de um processo de
+// aposentadoria.
+func DadosRequerenteProcesso(pa *database.ProcessoAposentadoria) DadosRequerente {
+	return DadosRequerente{
+		CPF:              pa.CPFRequerente,
+		DataNascimento:   pa.DataNascimentoRequerente,
+		DataRequerimento: pa.DataRequerimento,
+		Invalidez:        pa.Invalidez,
+		Judicial:         pa.Judicial,
+	}
+}
+
+// AtualizarDados aplica os dados ao processo de aposentadoria e salva o
+// processo, incluindo os alertas atribuídos a pa pelo chamador, registrando na
+// trilha de auditoria cada campo cujo valor foi alterado, com o motivo
+// informado. Nas correções manuais, a fonte dos campos alterados passa a ser
+// [database.FonteManual]. Retorna as alterações registradas. O score não é
+// recalculado.
+func AtualizarDados(ctx context.Context, store *database.Store, pa *database.ProcessoAposentadoria, dados DadosRequerente, origem, motivo string, usuarioID sql.Null[int64]) ([]*database.AlteracaoCampoProcesso, error) {
+	campos := []struct {
+		campo, anterior, novo string
+	}{
+		{CampoCPF, pa.CPFRequerente, dados.CPF},
+		{CampoDataNascimento, pa.DataNascimentoRequerente.Format(time.DateOnly), dados.DataNascimento.Format(time.DateOnly)},
+		{CampoDataRequerimento, pa.DataRequerimento.Format(time.DateOnly), dados.DataRequerimento.Format(time.DateOnly)},
+		{CampoInvalidez, strconv.FormatBool(pa.Invalidez), strconv.FormatBool(dados.Invalidez)},
+		{CampoJudicial, strconv.FormatBool(pa.Judicial), strconv.FormatBool(dados.Judicial)},
+	}
+
+	pa.CPFRequerente = dados.CPF
+	pa.DataNascimentoRequerente = dados.DataNascimento
+	pa.DataRequerimento = dados.DataRequerimento
+	pa.Invalidez = dados.Invalidez
+	pa.Judicial = dados.Judicial
+	if err := store.UpdateDadosProcessoAposentadoria(ctx, pa); err != nil {
+		return nil, err
+	}
+
+	var alteracoes []*database.AlteracaoCampoProcesso
+	for _, c := range campos {
+		if c.anterior == c.novo {
+			continue
+		}
+		a := &database.AlteracaoCampoProcesso{
+			ProcessoAposentadoriaID: pa.ID,
+			Campo:                   c.campo,
+			ValorAnterior:           c.anterior,
+			ValorNovo:               c.novo,
+			Origem:                  origem,
+			Motivo:                  motivo,
+			UsuarioID:               usuarioID,
+		}
+		if err := store.SaveAlteracaoCampoProcesso(ctx, a); err != nil {
+			return nil, err
+		}
+		if origem == database.OrigemAlteracaoManual {
+			if err := store.UpdateFonteCampoProcesso(ctx, pa.ID, c.campo, database.FonteManual); err != nil {
+				return nil, err
+			}
+		}
+		alteracoes = append(alteracoes, a)
+	}
+	return alteracoes, nil
+}
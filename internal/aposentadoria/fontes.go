@@ -0,0 +1,170 @@
+package aposentadoria
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/datalake"
+)
+
+// Tipos de discrepância entre a IA e as fontes externas.
+const (
+	// DiscrepanciaDivergencia indica que a IA e a fonte externa informam
+	// valores diferentes para o campo.
+	DiscrepanciaDivergencia = "DIVERGENCIA"
+	// DiscrepanciaFonteIndisponivel indica que a fonte externa não pôde ser
+	// consultada e o valor da IA não foi confirmado.
+	DiscrepanciaFonteIndisponivel = "FONTE_INDISPONIVEL"
+)
+
+// Extracao reúne os valores extraídos pela IA e os obtidos das fontes
+// externas para os dados do requerente de um processo.
+type Extracao struct {
+	// IA são os dados extraídos dos documentos pela IA. Datas que a IA não
+	// informou em formato válido ficam zeradas.
+	IA DadosRequerente
+	// Servidor são os dados do servidor no datalake, nil se a consulta falhou.
+	Servidor *datalake.Servidor
+	// DataRecebimento é a data de recebimento do processo no SEI, nil se a
+	// consulta falhou.
+	DataRecebimento *time.Time
+}
+
+// Dados combina os valores da extração, dando preferência às fontes externas,
+// e retorna a procedência de cada campo.
+func (e Extracao) Dados() (DadosRequerente, []*database.FonteCampoProcesso) {
+	dados := e.IA
+
+	cpf := &database.FonteCampoProcesso{
+		Campo:   CampoCPF,
+		Fonte:   database.FonteIA,
+		ValorIA: valorNull(e.IA.CPF),
+	}
+	judicial := &database.FonteCampoProcesso{
+		Campo:   CampoJudicial,
+		Fonte:   database.FonteIA,
+		ValorIA: valorNull(strconv.FormatBool(e.IA.Judicial)),
+	}
+
+	nascimento := &database.FonteCampoProcesso{
+		Campo:        CampoDataNascimento,
+		Fonte:        database.FonteIA,
+		ValorIA:      valorData(e.IA.DataNascimento),
+		FonteExterna: valorNull(database.FonteDatalake),
+	}
+	invalidez := &database.FonteCampoProcesso{
+		Campo:        CampoInvalidez,
+		Fonte:        database.FonteIA,
+		ValorIA:      valorNull(strconv.FormatBool(e.IA.Invalidez)),
+		FonteExterna: valorNull(database.FonteDatalake),
+	}
+	if e.Servidor != nil {
+		dados.DataNascimento = e.Servidor.DataNascimento
+		nascimento.Fonte = database.FonteDatalake
+		nascimento.ValorExterno = valorData(e.Servidor.DataNascimento)
+
+		// A deficiência registrada no datalake caracteriza a invalidez mesmo
+		// que não conste nos documentos.
+		dados.Invalidez = e.IA.Invalidez || e.Servidor.PossuiDeficiencia
+		if e.Servidor.PossuiDeficiencia {
+			invalidez.Fonte = database.FonteDatalake
+		}
+		invalidez.ValorExterno = valorNull(strconv.FormatBool(e.Servidor.PossuiDeficiencia))
+	}
+
+	requerimento := &database.FonteCampoProcesso{
+		Campo:        CampoDataRequerimento,
+		Fonte:        database.FonteIA,
+		ValorIA:      valorData(e.IA.DataRequerimento),
+		FonteExterna: valorNull(database.FonteSEI),
+	}
+	if e.DataRecebimento != nil {
+		dados.DataRequerimento = *e.DataRecebimento
+		requerimento.Fonte = database.FonteSEI
+		requerimento.ValorExterno = valorData(*e.DataRecebimento)
+	}
+
+	return dados, []*database.FonteCampoProcesso{cpf, nascimento, requerimento, invalidez, judicial}
+}
+
+// SalvarFontes registra a procedência dos campos de um processo de
+// aposentadoria.
+func SalvarFontes(ctx context.Context, store *database.Store, paID int64, fontes []*database.FonteCampoProcesso) error {
+	for _, f := range fontes {
+		f.ProcessoAposentadoriaID = paID
+		if err := store.SaveFonteCampoProcesso(ctx, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Discrepancia é uma diferença entre o valor extraído pela IA e uma fonte
+// externa, ou a impossibilidade de confirmar o valor da IA.
+type Discrepancia struct {
+	Campo        string  `json:"campo"`
+	Tipo         string  `json:"tipo"`
+	ValorIA      *string `json:"valor_ia"`
+	FonteExterna string  `json:"fonte_externa"`
+	ValorExterno *string `json:"valor_externo"`
+}
+
+// Discrepancias retorna as discrepâncias entre a IA e as fontes externas dos
+// campos informados. Campos corrigidos manualmente não são considerados.
+func Discrepancias(fontes []*database.FonteCampoProcesso) []Discrepancia {
+	dd := make([]Discrepancia, 0)
+	for _, f := range fontes {
+		if f.Fonte == database.FonteManual || !f.FonteExterna.Valid {
+			continue
+		}
+
+		d := Discrepancia{
+			Campo:        f.Campo,
+			ValorIA:      database.Ptr(f.ValorIA),
+			FonteExterna: f.FonteExterna.V,
+			ValorExterno: database.Ptr(f.ValorExterno),
+		}
+		switch {
+		case !f.ValorExterno.Valid:
+			d.Tipo = DiscrepanciaFonteIndisponivel
+		case !f.ValorIA.Valid || f.ValorIA.V != f.ValorExterno.V:
+			d.Tipo = DiscrepanciaDivergencia
+		default:
+			continue
+		}
+		dd = append(dd, d)
+	}
+	return dd
+}
+
+// alertasExtracao são os alertas de texto gerados pela extração antes do
+// registro da procedência, substituídos pelas discrepâncias.
+var alertasExtracao = []string{
+	"Não foi possível obter os dados do servidor no datalake. Utilizando dados extraídos pela IA.",
+	"Não foi possível obter a data de recebimento no SEI. Utilizando data extraída pela IA.",
+}
+
+// RemoverAlertasExtracao retorna os alertas sem os alertas de texto gerados
+// pela extração, mantendo os demais, como as falhas de tramitação no SEI.
+func RemoverAlertasExtracao(alertas []string) []string {
+	return slices.DeleteFunc(slices.Clone(alertas), func(a string) bool {
+		return slices.Contains(alertasExtracao, a)
+	})
+}
+
+func valorNull(v string) sql.Null[string] {
+	return sql.Null[string]{V: v, Valid: true}
+}
+
+// valorData formata uma data para o registro da procedência, retornando nulo
+// para a data zero.
+func valorData(t time.Time) sql.Null[string] {
+	if t.IsZero() {
+		return sql.Null[string]{}
+	}
+	return valorNull(t.Format(time.DateOnly))
+}
@@ -0,0 +1,113 @@
+package aposentadoria
+
+import (
+	"testing"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/datalake"
+)
+
+func TestExtracaoDados(t *testing.T) {
+	ia := DadosRequerente{
+		CPF:              "12345678901",
+		DataNascimento:   time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC),
+		DataRequerimento: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("fontes externas disponíveis", func(t *testing.T) {
+		recebimento := time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC)
+		e := Extracao{
+			IA: ia,
+			Servidor: &datalake.Servidor{
+				DataNascimento:    time.Date(1961, 1, 1, 0, 0, 0, 0, time.UTC),
+				PossuiDeficiencia: true,
+			},
+			DataRecebimento: &recebimento,
+		}
+
+		dados, fontes := e.Dados()
+		if !dados.DataNascimento.Equal(e.Servidor.DataNascimento) || !dados.Invalidez || !dados.DataRequerimento.Equal(recebimento) {
+			t.Fatalf("unexpected dados: %+v", dados)
+		}
+
+		got := make(map[string]string)
+		for _, f := range fontes {
+			got[f.Campo] = f.Fonte
+		}
+		want := map[string]string{
+			CampoCPF:              database.FonteIA,
+			CampoDataNascimento:   database.FonteDatalake,
+			CampoDataRequerimento: database.FonteSEI,
+			CampoInvalidez:        database.FonteDatalake,
+			CampoJudicial:         database.FonteIA,
+		}
+		for campo, fonte := range want {
+			if got[campo] != fonte {
+				t.Errorf("campo %s: expected fonte %s, got %s", campo, fonte, got[campo])
+			}
+		}
+
+		dd := Discrepancias(fontes)
+		if len(dd) != 3 {
+			t.Fatalf("expected 3 discrepâncias, got %+v", dd)
+		}
+		for _, d := range dd {
+			if d.Tipo != DiscrepanciaDivergencia {
+				t.Errorf("campo %s: expected %s, got %s", d.Campo, DiscrepanciaDivergencia, d.Tipo)
+			}
+		}
+	})
+
+	t.Run("fontes externas indisponíveis", func(t *testing.T) {
+		e := Extracao{IA: ia}
+
+		dados, fontes := e.Dados()
+		if dados != ia {
+			t.Fatalf("expected dados da IA, got %+v", dados)
+		}
+		for _, f := range fontes {
+			if f.Fonte != database.FonteIA {
+				t.Errorf("campo %s: expected fonte IA, got %s", f.Campo, f.Fonte)
+			}
+		}
+
+		dd := Discrepancias(fontes)
+		if len(dd) != 3 {
+			t.Fatalf("expected 3 discrepâncias, got %+v", dd)
+		}
+		for _, d := range dd {
+			if d.Tipo != DiscrepanciaFonteIndisponivel {
+				t.Errorf("campo %s: expected %s, got %s", d.Campo, DiscrepanciaFonteIndisponivel, d.Tipo)
+			}
+		}
+	})
+
+	t.Run("correção manual", func(t *testing.T) {
+		e := Extracao{IA: ia}
+
+		_, fontes := e.Dados()
+		for _, f := range fontes {
+			f.Fonte = database.FonteManual
+		}
+		if dd := Discrepancias(fontes); len(dd) != 0 {
+			t.Fatalf("expected no discrepâncias, got %+v", dd)
+		}
+	})
+}
+
+func TestRemoverAlertasExtracao(t *testing.T) {
+	alertas := []string{
+		"Não foi possível obter os dados do servidor no datalake. Utilizando dados extraídos pela IA.",
+		"Falha ao enviar processo no SEI",
+		"Não foi possível obter a data de recebimento no SEI. Utilizando data extraída pela IA.",
+	}
+
+	got := RemoverAlertasExtracao(alertas)
+	if len(got) != 1 || got[0] != "Falha ao enviar processo no SEI" {
+		t.Fatalf("expected only the SEI alerta, got %v", got)
+	}
+	if len(alertas) != 3 {
+		t.Fatalf("expected input to be preserved, got %v", alertas)
+	}
+}
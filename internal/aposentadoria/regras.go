@@ -0,0 +1,277 @@
+package aposentadoria
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+// Condicao identifica um dado do processo avaliado por uma regra de score.
+type Condicao string
+
+const (
+	// CondicaoJudicial é satisfeita quando o processo é judicializado.
+	CondicaoJudicial Condicao = "judicial"
+	// CondicaoPrioridade é satisfeita quando o processo possui prioridade aprovada.
+	CondicaoPrioridade Condicao = "prioridade"
+	// CondicaoInvalidez é satisfeita quando o requerente possui doença grave ou invalidez.
+	CondicaoInvalidez Condicao = "invalidez"
+	// CondicaoIdadeMinima é satisfeita quando a idade do requerente é maior ou
+	// igual ao valor da regra.
+	CondicaoIdadeMinima Condicao = "idade_minima"
+)
+
+var condicoes = []Condicao{
+	CondicaoJudicial,
+	CondicaoPrioridade,
+	CondicaoInvalidez,
+	CondicaoIdadeMinima,
+}
+
+// Precedencia é uma regra que, quando satisfeita, fixa o score do processo
+// independentemente dos critérios. As precedências são avaliadas na ordem em
+// que aparecem.
+type Precedencia struct {
+	Codigo    string   `json:"codigo"`
+	Descricao string   `json:"descricao"`
+	Condicao  Condicao `json:"condicao"`
+	Valor     int      `json:"valor,omitempty"`
+	Score     int      `json:"score"`
+}
+
+// Criterio é uma regra que soma pontos ao score do processo quando satisfeita.
+type Criterio struct {
+	Codigo    string   `json:"codigo"`
+	Descricao string   `json:"descricao"`
+	Condicao  Condicao `json:"condicao"`
+	Valor     int      `json:"valor,omitempty"`
+	Pontos    int      `json:"pontos"`
+}
+
+// Envelhecimento é uma regra opcional que soma pontos ao score conforme o
+// tempo de espera desde a data do requerimento, evitando que processos de
+// score baixo fiquem indefinidamente atrás de processos mais novos.
+type Envelhecimento struct {
+	Descricao string `json:"descricao"`
+	// Dias de espera necessários para cada incremento de pontos.
+	Dias int `json:"dias"`
+	// Pontos somados a cada período de Dias.
+	Pontos int `json:"pontos"`
+	// Pontuação máxima obtida pelo envelhecimento.
+	Maximo int `json:"maximo"`
+}
+
+// RegrasScore é o conjunto de regras usado para calcular o score dos
+// processos de aposentadoria.
+type RegrasScore struct {
+	Precedencias   []Precedencia   `json:"precedencias"`
+	Criterios      []Criterio      `json:"criterios"`
+	Envelhecimento *Envelhecimento `json:"envelhecimento,omitempty"`
+}
+
+// DadosScore são os dados de um processo usados no cálculo do score.
+type DadosScore struct {
+	DataNascimento   time.Time
+	DataRequerimento time.Time
+	Invalidez        bool
+	Judicial         bool
+	Prioridade       bool
+}
+
+// TipoRegra indica se uma regra avaliada é uma precedência ou um critério.
+type TipoRegra string
+
+const (
+	TipoPrecedencia    TipoRegra = "precedencia"
+	TipoCriterio       TipoRegra = "criterio"
+	TipoEnvelhecimento TipoRegra = "envelhecimento"
+)
+
+// CodigoEnvelhecimento é o código da regra de envelhecimento no detalhamento
+// do score.
+const CodigoEnvelhecimento = "envelhecimento"
+
+// CriterioAvaliado representa uma regra avaliada durante o cálculo do score.
+// Pontos é a contribuição efetiva da regra para o score final: critérios não
+// contribuem quando uma precedência é aplicada.
+type CriterioAvaliado struct {
+	Codigo     string    `json:"codigo"`
+	Descricao  string    `json:"descricao"`
+	Tipo       TipoRegra `json:"tipo"`
+	Satisfeito bool      `json:"satisfeito"`
+	Pontos     int       `json:"pontos"`
+}
+
+// ResultadoScore é o detalhamento de um cálculo de score: os dados usados,
+// todas as regras avaliadas e a precedência aplicada, se houver.
+type ResultadoScore struct {
+	Versao      int                `json:"versao,omitempty"`
+	Score       int                `json:"score"`
+	CalculadoEm time.Time          `json:"calculado_em"`
+	Idade       int                `json:"idade"`
+	DiasEspera  int                `json:"dias_espera"`
+	Invalidez   bool               `json:"invalidez"`
+	Judicial    bool               `json:"judicial"`
+	Prioridade  bool               `json:"prioridade"`
+	Precedencia string             `json:"precedencia,omitempty"`
+	Criterios   []CriterioAvaliado `json:"criterios"`
+}
+
+// RegrasPadrao retorna as regras de score descritas em docs/score.md.
+func RegrasPadrao() *RegrasScore {
+	return &RegrasScore{
+		Precedencias: []Precedencia{
+			{Codigo: "judicial", Descricao: "Processo judicializado", Condicao: CondicaoJudicial, Score: ScoreJudicial},
+			{Codigo: "prioridade", Descricao: "Prioridade aprovada", Condicao: CondicaoPrioridade, Score: ScorePrioridade},
+		},
+		Criterios: []Criterio{
+			{Codigo: "idade_60", Descricao: "Idade do requerente >= 60 anos", Condicao: CondicaoIdadeMinima, Valor: 60, Pontos: 1},
+			{Codigo: "idade_80", Descricao: "Idade do requerente >= 80 anos", Condicao: CondicaoIdadeMinima, Valor: 80, Pontos: 2},
+			{Codigo: "invalidez", Descricao: "Doença grave ou invalidez", Condicao: CondicaoInvalidez, Pontos: 2},
+		},
+	}
+}
+
+// ParseRegras decodifica um conjunto de regras armazenado em JSON.
+func ParseRegras(data []byte) (*RegrasScore, error) {
+	var regras RegrasScore
+	if err := json.Unmarshal(data, &regras); err != nil {
+		return nil, fmt.Errorf("invalid regras: %w", err)
+	}
+	return &regras, nil
+}
+
+// Check valida o conjunto de regras, registrando os erros encontrados no
+// validador informado.
+func (r *RegrasScore) Check(v *validator.Validator) {
+	codigos := make([]string, 0, len(r.Precedencias)+len(r.Criterios))
+
+	for i, p := range r.Precedencias {
+		field := fmt.Sprintf("precedencias[%d]", i)
+		checkRegra(v, field, p.Codigo, p.Condicao, p.Valor)
+		v.Check(p.Score >= 0, field+".score", "Deve ser maior ou igual a zero")
+		codigos = append(codigos, p.Codigo)
+	}
+
+	for i, c := range r.Criterios {
+		field := fmt.Sprintf("criterios[%d]", i)
+		checkRegra(v, field, c.Codigo, c.Condicao, c.Valor)
+		codigos = append(codigos, c.Codigo)
+	}
+
+	if e := r.Envelhecimento; e != nil {
+		v.Check(e.Dias > 0, "envelhecimento.dias", "Deve ser maior que zero")
+		v.Check(e.Pontos > 0, "envelhecimento.pontos", "Deve ser maior que zero")
+		v.Check(e.Maximo >= e.Pontos, "envelhecimento.maximo", "Deve ser maior ou igual aos pontos")
+	}
+
+	v.Check(len(r.Precedencias)+len(r.Criterios) > 0, "regras", "Deve conter ao menos uma regra")
+	v.Check(validator.Unique(codigos), "regras", "Os códigos das regras devem ser únicos")
+}
+
+func checkRegra(v *validator.Validator, field, codigo string, condicao Condicao, valor int) {
+	v.Check(validator.NotBlank(codigo), field+".codigo", "Campo obrigatório")
+	v.Check(validator.PermittedValue(condicao, condicoes...), field+".condicao", "Condição inválida")
+	if condicao == CondicaoIdadeMinima {
+		v.Check(valor > 0, field+".valor", "Deve ser maior que zero")
+	}
+}
+
+// Calcular calcula o score de um processo com base nas regras.
+//
+// A primeira precedência satisfeita fixa o score. Caso nenhuma seja
+// satisfeita, o score é a soma dos pontos dos critérios satisfeitos e dos
+// pontos de envelhecimento. Todas as regras são avaliadas e incluídas no
+// resultado.
+func (r *RegrasScore) Calcular(d DadosScore, now time.Time) ResultadoScore {
+	res := ResultadoScore{
+		CalculadoEm: now,
+		Idade:       calculateAgeAt(d.DataNascimento, now),
+		DiasEspera:  diasEntre(d.DataRequerimento, now),
+		Invalidez:   d.Invalidez,
+		Judicial:    d.Judicial,
+		Prioridade:  d.Prioridade,
+		Criterios:   make([]CriterioAvaliado, 0, len(r.Precedencias)+len(r.Criterios)),
+	}
+
+	for _, p := range r.Precedencias {
+		c := CriterioAvaliado{
+			Codigo:     p.Codigo,
+			Descricao:  p.Descricao,
+			Tipo:       TipoPrecedencia,
+			Satisfeito: avaliar(p.Condicao, p.Valor, d, now),
+		}
+		if c.Satisfeito && res.Precedencia == "" {
+			res.Precedencia = p.Codigo
+			res.Score = p.Score
+			c.Pontos = p.Score
+		}
+		res.Criterios = append(res.Criterios, c)
+	}
+
+	for _, cr := range r.Criterios {
+		c := CriterioAvaliado{
+			Codigo:     cr.Codigo,
+			Descricao:  cr.Descricao,
+			Tipo:       TipoCriterio,
+			Satisfeito: avaliar(cr.Condicao, cr.Valor, d, now),
+		}
+		if c.Satisfeito && res.Precedencia == "" {
+			res.Score += cr.Pontos
+			c.Pontos = cr.Pontos
+		}
+		res.Criterios = append(res.Criterios, c)
+	}
+
+	if e := r.Envelhecimento; e != nil {
+		c := CriterioAvaliado{
+			Codigo:    CodigoEnvelhecimento,
+			Descricao: e.Descricao,
+			Tipo:      TipoEnvelhecimento,
+		}
+		pontos := e.pontos(res.DiasEspera)
+		c.Satisfeito = pontos > 0
+		if c.Satisfeito && res.Precedencia == "" {
+			res.Score += pontos
+			c.Pontos = pontos
+		}
+		res.Criterios = append(res.Criterios, c)
+	}
+
+	return res
+}
+
+// pontos retorna os pontos de envelhecimento para os dias de espera
+// informados, limitados ao máximo da regra.
+func (e *Envelhecimento) pontos(dias int) int {
+	if e.Dias <= 0 || dias <= 0 {
+		return 0
+	}
+	return min((dias/e.Dias)*e.Pontos, e.Maximo)
+}
+
+// diasEntre retorna a quantidade de dias completos entre t e now. Retorna zero
+// quando t é zero ou posterior a now.
+func diasEntre(t, now time.Time) int {
+	if t.IsZero() || !now.After(t) {
+		return 0
+	}
+	return int(now.Sub(t).Hours() / 24)
+}
+
+func avaliar(condicao Condicao, valor int, d DadosScore, now time.Time) bool {
+	switch condicao {
+	case CondicaoJudicial:
+		return d.Judicial
+	case CondicaoPrioridade:
+		return d.Prioridade
+	case CondicaoInvalidez:
+		return d.Invalidez
+	case CondicaoIdadeMinima:
+		return calculateAgeAt(d.DataNascimento, now) >= valor
+	default:
+		return false
+	}
+}
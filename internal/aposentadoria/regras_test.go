@@ -0,0 +1,193 @@
+package aposentadoria
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/validator"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegrasCalcular(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+	regras := &RegrasScore{
+		Precedencias: []Precedencia{
+			{Codigo: "prioridade", Condicao: CondicaoPrioridade, Score: 10},
+			{Codigo: "judicial", Condicao: CondicaoJudicial, Score: 9},
+		},
+		Criterios: []Criterio{
+			{Codigo: "idade_70", Descricao: "70+", Condicao: CondicaoIdadeMinima, Valor: 70, Pontos: 3},
+			{Codigo: "invalidez", Descricao: "Invalidez", Condicao: CondicaoInvalidez, Pontos: 4},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		dados    DadosScore
+		expected ResultadoScore
+	}{
+		{
+			name:  "nenhum critério",
+			dados: DadosScore{DataNascimento: now.AddDate(-50, 0, 0)},
+			expected: ResultadoScore{
+				CalculadoEm: now,
+				Idade:       50,
+				Criterios: []CriterioAvaliado{
+					{Codigo: "prioridade", Tipo: TipoPrecedencia},
+					{Codigo: "judicial", Tipo: TipoPrecedencia},
+					{Codigo: "idade_70", Descricao: "70+", Tipo: TipoCriterio},
+					{Codigo: "invalidez", Descricao: "Invalidez", Tipo: TipoCriterio},
+				},
+			},
+		},
+		{
+			name:  "soma dos critérios",
+			dados: DadosScore{DataNascimento: now.AddDate(-70, 0, 0), Invalidez: true},
+			expected: ResultadoScore{
+				Score:       7,
+				CalculadoEm: now,
+				Idade:       70,
+				Invalidez:   true,
+				Criterios: []CriterioAvaliado{
+					{Codigo: "prioridade", Tipo: TipoPrecedencia},
+					{Codigo: "judicial", Tipo: TipoPrecedencia},
+					{Codigo: "idade_70", Descricao: "70+", Tipo: TipoCriterio, Satisfeito: true, Pontos: 3},
+					{Codigo: "invalidez", Descricao: "Invalidez", Tipo: TipoCriterio, Satisfeito: true, Pontos: 4},
+				},
+			},
+		},
+		{
+			name:  "ordem das precedências",
+			dados: DadosScore{DataNascimento: now.AddDate(-70, 0, 0), Judicial: true, Prioridade: true},
+			expected: ResultadoScore{
+				Score:       10,
+				CalculadoEm: now,
+				Idade:       70,
+				Judicial:    true,
+				Prioridade:  true,
+				Precedencia: "prioridade",
+				Criterios: []CriterioAvaliado{
+					{Codigo: "prioridade", Tipo: TipoPrecedencia, Satisfeito: true, Pontos: 10},
+					{Codigo: "judicial", Tipo: TipoPrecedencia, Satisfeito: true},
+					{Codigo: "idade_70", Descricao: "70+", Tipo: TipoCriterio, Satisfeito: true},
+					{Codigo: "invalidez", Descricao: "Invalidez", Tipo: TipoCriterio},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := regras.Calcular(tt.dados, now)
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Fatalf("mismatch:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegrasEnvelhecimento(t *testing.T) {
+	now := time.Date(2026, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+	regras := &RegrasScore{
+		Precedencias: []Precedencia{
+			{Codigo: "judicial", Condicao: CondicaoJudicial, Score: 9},
+		},
+		Criterios: []Criterio{
+			{Codigo: "invalidez", Condicao: CondicaoInvalidez, Pontos: 2},
+		},
+		Envelhecimento: &Envelhecimento{Dias: 30, Pontos: 1, Maximo: 3},
+	}
+
+	tests := []struct {
+		name       string
+		dados      DadosScore
+		score      int
+		diasEspera int
+		pontos     int
+	}{
+		{
+			name:       "antes do primeiro período",
+			dados:      DadosScore{DataRequerimento: now.AddDate(0, 0, -29)},
+			score:      0,
+			diasEspera: 29,
+		},
+		{
+			name:       "soma aos critérios",
+			dados:      DadosScore{DataRequerimento: now.AddDate(0, 0, -65), Invalidez: true},
+			score:      4,
+			diasEspera: 65,
+			pontos:     2,
+		},
+		{
+			name:       "limitado ao máximo",
+			dados:      DadosScore{DataRequerimento: now.AddDate(-1, 0, 0)},
+			score:      3,
+			diasEspera: 365,
+			pontos:     3,
+		},
+		{
+			name:       "ignorado com precedência",
+			dados:      DadosScore{DataRequerimento: now.AddDate(-1, 0, 0), Judicial: true},
+			score:      9,
+			diasEspera: 365,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := regras.Calcular(tt.dados, now)
+			if got.Score != tt.score {
+				t.Errorf("expected score %d, got %d", tt.score, got.Score)
+			}
+			if got.DiasEspera != tt.diasEspera {
+				t.Errorf("expected dias_espera %d, got %d", tt.diasEspera, got.DiasEspera)
+			}
+
+			c := got.Criterios[len(got.Criterios)-1]
+			if c.Tipo != TipoEnvelhecimento || c.Pontos != tt.pontos {
+				t.Errorf("expected envelhecimento with %d pontos, got %+v", tt.pontos, c)
+			}
+		})
+	}
+}
+
+func TestRegrasCheck(t *testing.T) {
+	v := validator.New()
+	RegrasPadrao().Check(v)
+	if !v.Valid() {
+		t.Fatalf("expected regras padrão to be valid, got %v", v.FieldErrors)
+	}
+
+	regras := &RegrasScore{
+		Criterios: []Criterio{
+			{Codigo: "idade", Condicao: CondicaoIdadeMinima},
+			{Codigo: "idade", Condicao: "desconhecida"},
+		},
+		Envelhecimento: &Envelhecimento{Pontos: 2, Maximo: 1},
+	}
+	v = validator.New()
+	regras.Check(v)
+	for _, field := range []string{"criterios[0].valor", "criterios[1].condicao", "regras", "envelhecimento.dias", "envelhecimento.maximo"} {
+		if v.Message(field) == "" {
+			t.Errorf("expected error for %q", field)
+		}
+	}
+}
+
+func TestParseRegras(t *testing.T) {
+	data, err := json.Marshal(RegrasPadrao())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regras, err := ParseRegras(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(RegrasPadrao(), regras); diff != "" {
+		t.Fatalf("mismatch:\n%s", diff)
+	}
+}
@@ -11,7 +11,10 @@ const (
 )
 
 func CalculateAge(t time.Time) int {
-	now := time.Now()
+	return calculateAgeAt(t, time.Now())
+}
+
+func calculateAgeAt(t, now time.Time) int {
 	age := now.Year() - t.Year()
 
 	if now.Month() < t.Month() ||
@@ -22,7 +25,8 @@ func CalculateAge(t time.Time) int {
 	return age
 }
 
-// CalculateScore calcula o score de um processo de aposentadoria.
+// CalculateScore calcula o score de um processo de aposentadoria usando
+// as regras padrão ([RegrasPadrao]).
 //
 // Se o processo for judicializado, retorna [ScoreJudicial].
 // Se o processo possuir prioridade aprovada, retorna [ScorePrioridade].
@@ -32,26 +36,11 @@ func CalculateAge(t time.Time) int {
 //   - Se a idade é maior ou igual a 80, adiciona dois pontos.
 //   - Se o requerente possui doença grave ou invalidez, adiciona dois pontos.
 func CalculateScore(dataNasc time.Time, invalidez, judicial, prioridade bool) int {
-	if judicial {
-		return ScoreJudicial
-	}
-
-	if prioridade {
-		return ScorePrioridade
-	}
-
-	score := 0
-
-	age := CalculateAge(dataNasc)
-	if age >= 60 {
-		score += 1
-	}
-	if age >= 80 {
-		score += 2
-	}
-	if invalidez {
-		score += 2
-	}
-
-	return score
+	res := RegrasPadrao().Calcular(DadosScore{
+		DataNascimento: dataNasc,
+		Invalidez:      invalidez,
+		Judicial:       judicial,
+		Prioridade:     prioridade,
+	}, time.Now())
+	return res.Score
 }
@@ -0,0 +1,90 @@
+package aposentadoria
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+// VersaoRegras é uma versão de [RegrasScore] carregada do banco de dados.
+type VersaoRegras struct {
+	ID     int64
+	Versao int
+	Regras *RegrasScore
+}
+
+// LoadVersaoAtiva carrega a versão de regras de score em vigor.
+func LoadVersaoAtiva(ctx context.Context, store *database.Store) (*VersaoRegras, error) {
+	v, err := store.GetVersaoRegrasScoreAtiva(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versão ativa: %w", err)
+	}
+
+	regras, err := ParseRegras(v.Regras)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersaoRegras{
+		ID:     v.ID,
+		Versao: v.Versao,
+		Regras: regras,
+	}, nil
+}
+
+// DadosProcesso retorna os dados de um processo de aposentadoria usados no
+// cálculo do score.
+func DadosProcesso(pa *database.ProcessoAposentadoria) DadosScore {
+	return DadosScore{
+		DataNascimento:   pa.DataNascimentoRequerente,
+		DataRequerimento: pa.DataRequerimento,
+		Invalidez:        pa.Invalidez,
+		Judicial:         pa.Judicial,
+		Prioridade:       pa.Prioridade,
+	}
+}
+
+// Calcular calcula o score de um processo de aposentadoria com esta versão
+// das regras.
+func (v *VersaoRegras) Calcular(pa *database.ProcessoAposentadoria, now time.Time) ResultadoScore {
+	res := v.Regras.Calcular(DadosProcesso(pa), now)
+	res.Versao = v.Versao
+	return res
+}
+
+// Registrar salva o resultado do cálculo como o score atual do processo,
+// permitindo explicar posteriormente como o valor foi obtido. Quando o score
+// difere do anterior, ou não há score anterior, o cálculo também é registrado
+// no histórico de scores do processo.
+func (v *VersaoRegras) Registrar(ctx context.Context, store *database.Store, paID int64, anterior sql.Null[int], res ResultadoScore) error {
+	detalhes, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	err = store.UpsertScoreProcessoAposentadoria(ctx, &database.ScoreProcessoAposentadoria{
+		ProcessoAposentadoriaID: paID,
+		VersaoRegrasID:          v.ID,
+		Score:                   res.Score,
+		Detalhes:                detalhes,
+	})
+	if err != nil {
+		return err
+	}
+
+	if anterior.Valid && anterior.V == res.Score {
+		return nil
+	}
+
+	return store.SaveHistoricoScoreProcesso(ctx, &database.HistoricoScoreProcesso{
+		ProcessoAposentadoriaID: paID,
+		VersaoRegrasID:          v.ID,
+		ScoreAnterior:           anterior,
+		ScoreNovo:               res.Score,
+		Detalhes:                detalhes,
+	})
+}
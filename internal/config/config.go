@@ -5,11 +5,14 @@ import (
 
 	"github.com/automatiza-mg/fila/internal/blob"
 	"github.com/automatiza-mg/fila/internal/datalake"
+	"github.com/automatiza-mg/fila/internal/diligencias"
 	"github.com/automatiza-mg/fila/internal/docintel"
+	"github.com/automatiza-mg/fila/internal/fila"
 	"github.com/automatiza-mg/fila/internal/llm"
 	"github.com/automatiza-mg/fila/internal/mail"
 	"github.com/automatiza-mg/fila/internal/postgres"
 	"github.com/automatiza-mg/fila/internal/sei"
+	"github.com/automatiza-mg/fila/internal/tasks"
 	"github.com/caarlos0/env/v11"
 )
 
@@ -18,13 +21,16 @@ type Config struct {
 	ClientURL *url.URL `env:"CLIENT_URL,notEmpty"`
 	RedisURL  string   `env:"REDIS_URL,notEmpty" envDefault:"redis://localhost:6379"`
 
-	Mail     mail.Config
-	Postgres postgres.Config
-	SEI      sei.Config
-	DataLake datalake.Config
-	Blob     blob.Config
-	DocIntel docintel.Config
-	LLM      llm.Config
+	Mail        mail.Config
+	Postgres    postgres.Config
+	SEI         sei.Config
+	DataLake    datalake.Config
+	Blob        blob.Config
+	DocIntel    docintel.Config
+	LLM         llm.Config
+	Fila        fila.Config
+	Diligencias diligencias.Config
+	Ingestao    tasks.IngestaoConfig
 }
 
 func NewFromEnv() (*Config, error) {
@@ -0,0 +1,269 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AfastamentoAnalista registra um período em que o analista esteve afastado.
+// Fim é nulo enquanto o afastamento está em curso. Manual indica que o
+// afastamento foi aberto diretamente, e não por um afastamento programado.
+type AfastamentoAnalista struct {
+	ID         int64               `db:"id"`
+	AnalistaID int64               `db:"analista_id"`
+	Inicio     time.Time           `db:"inicio"`
+	Fim        sql.Null[time.Time] `db:"fim"`
+	Manual     bool                `db:"manual"`
+}
+
+// IniciarAfastamentoAnalista abre um novo afastamento manual para o analista.
+// Se o analista já possuir um afastamento em aberto, ele passa a ser manual.
+func (s *Store) IniciarAfastamentoAnalista(ctx context.Context, analistaID int64) error {
+	q := `
+	INSERT INTO afastamentos_analista (analista_id, manual)
+	VALUES ($1, TRUE)
+	ON CONFLICT (analista_id) WHERE fim IS NULL DO UPDATE SET
+		manual = TRUE`
+
+	_, err := s.db.Exec(ctx, q, analistaID)
+	return err
+}
+
+// IniciarAfastamentoAnalistaEm abre um novo afastamento para o analista com
+// início no instante informado, usado pelos afastamentos programados. Não faz
+// nada se o analista já possuir um afastamento em aberto.
+func (s *Store) IniciarAfastamentoAnalistaEm(ctx context.Context, analistaID int64, inicio time.Time) error {
+	q := `
+	INSERT INTO afastamentos_analista (analista_id, inicio)
+	VALUES ($1, $2)
+	ON CONFLICT (analista_id) WHERE fim IS NULL DO NOTHING`
+
+	_, err := s.db.Exec(ctx, q, analistaID, inicio)
+	return err
+}
+
+// HasAfastamentoManualAberto informa se o analista possui um afastamento
+// manual em aberto.
+func (s *Store) HasAfastamentoManualAberto(ctx context.Context, analistaID int64) (bool, error) {
+	q := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM afastamentos_analista
+		WHERE analista_id = $1
+		AND fim IS NULL
+		AND manual
+	)`
+
+	var ok bool
+	err := s.db.QueryRow(ctx, q, analistaID).Scan(&ok)
+	return ok, err
+}
+
+// EncerrarAfastamentoAnalista encerra o afastamento em aberto do analista, se
+// houver.
+func (s *Store) EncerrarAfastamentoAnalista(ctx context.Context, analistaID int64) error {
+	return s.EncerrarAfastamentoAnalistaEm(ctx, analistaID, time.Now())
+}
+
+// EncerrarAfastamentoAnalistaEm encerra o afastamento em aberto do analista,
+// se houver, no instante informado. O fim nunca é anterior ao início do
+// afastamento.
+func (s *Store) EncerrarAfastamentoAnalistaEm(ctx context.Context, analistaID int64, fim time.Time) error {
+	q := `
+	UPDATE afastamentos_analista SET
+		fim = GREATEST(inicio, $2)
+	WHERE analista_id = $1 AND fim IS NULL`
+
+	_, err := s.db.Exec(ctx, q, analistaID, fim)
+	return err
+}
+
+// ListAfastamentosAnalista retorna os afastamentos de um analista, do mais
+// recente para o mais antigo.
+func (s *Store) ListAfastamentosAnalista(ctx context.Context, analistaID int64) ([]*AfastamentoAnalista, error) {
+	q := `
+	SELECT id, analista_id, inicio, fim, manual
+	FROM afastamentos_analista
+	WHERE analista_id = $1
+	ORDER BY inicio DESC`
+
+	rows, err := s.db.Query(ctx, q, analistaID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[AfastamentoAnalista])
+}
+
+// AfastamentoProgramado é um período de afastamento futuro do analista,
+// aplicado automaticamente no início e no fim do período.
+type AfastamentoProgramado struct {
+	ID          int64               `db:"id"`
+	AnalistaID  int64               `db:"analista_id"`
+	Inicio      time.Time           `db:"inicio"`
+	Fim         time.Time           `db:"fim"`
+	Motivo      string              `db:"motivo"`
+	CriadoPor   sql.Null[int64]     `db:"criado_por"`
+	CriadoEm    time.Time           `db:"criado_em"`
+	IniciadoEm  sql.Null[time.Time] `db:"iniciado_em"`
+	EncerradoEm sql.Null[time.Time] `db:"encerrado_em"`
+	CanceladoEm sql.Null[time.Time] `db:"cancelado_em"`
+}
+
+const afastamentoProgramadoColumns = `
+	id, analista_id, inicio, fim, motivo, criado_por, criado_em,
+	iniciado_em, encerrado_em, cancelado_em`
+
+// SaveAfastamentoProgramado registra um novo afastamento programado.
+func (s *Store) SaveAfastamentoProgramado(ctx context.Context, ap *AfastamentoProgramado) error {
+	q := `
+	INSERT INTO afastamentos_programados (analista_id, inicio, fim, motivo, criado_por)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, criado_em`
+	args := []any{ap.AnalistaID, ap.Inicio, ap.Fim, ap.Motivo, ap.CriadoPor}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&ap.ID, &ap.CriadoEm)
+}
+
+// GetAfastamentoProgramado retorna um afastamento programado pelo ID. Retorna
+// [ErrNotFound] caso não seja encontrado.
+func (s *Store) GetAfastamentoProgramado(ctx context.Context, id int64) (*AfastamentoProgramado, error) {
+	q := `SELECT` + afastamentoProgramadoColumns + `
+	FROM afastamentos_programados
+	WHERE id = $1
+	FOR UPDATE`
+
+	return s.getAfastamentoProgramado(ctx, q, id)
+}
+
+// GetAfastamentoProgramadoAIniciar retorna, bloqueando o registro, um
+// afastamento programado não cancelado cujo início já foi alcançado em agora
+// e que ainda não foi aplicado. Retorna [ErrNotFound] se não houver.
+func (s *Store) GetAfastamentoProgramadoAIniciar(ctx context.Context, agora time.Time) (*AfastamentoProgramado, error) {
+	q := `SELECT` + afastamentoProgramadoColumns + `
+	FROM afastamentos_programados
+	WHERE iniciado_em IS NULL
+	AND encerrado_em IS NULL
+	AND cancelado_em IS NULL
+	AND inicio <= $1
+	ORDER BY inicio
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED`
+
+	return s.getAfastamentoProgramado(ctx, q, agora)
+}
+
+// GetAfastamentoProgramadoAEncerrar retorna, bloqueando o registro, um
+// afastamento programado em curso cujo fim já foi alcançado em agora. Retorna
+// [ErrNotFound] se não houver.
+func (s *Store) GetAfastamentoProgramadoAEncerrar(ctx context.Context, agora time.Time) (*AfastamentoProgramado, error) {
+	q := `SELECT` + afastamentoProgramadoColumns + `
+	FROM afastamentos_programados
+	WHERE iniciado_em IS NOT NULL
+	AND encerrado_em IS NULL
+	AND fim <= $1
+	ORDER BY fim
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED`
+
+	return s.getAfastamentoProgramado(ctx, q, agora)
+}
+
+func (s *Store) getAfastamentoProgramado(ctx context.Context, q string, args ...any) (*AfastamentoProgramado, error) {
+	rows, err := s.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	ap, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[AfastamentoProgramado])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return ap, nil
+}
+
+// ListAfastamentosProgramados retorna os afastamentos programados de um
+// analista, do mais recente para o mais antigo.
+func (s *Store) ListAfastamentosProgramados(ctx context.Context, analistaID int64) ([]*AfastamentoProgramado, error) {
+	q := `SELECT` + afastamentoProgramadoColumns + `
+	FROM afastamentos_programados
+	WHERE analista_id = $1
+	ORDER BY inicio DESC`
+
+	rows, err := s.db.Query(ctx, q, analistaID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[AfastamentoProgramado])
+}
+
+// UpdateAfastamentoProgramado atualiza a situação de um afastamento programado.
+func (s *Store) UpdateAfastamentoProgramado(ctx context.Context, ap *AfastamentoProgramado) error {
+	q := `
+	UPDATE afastamentos_programados SET
+		iniciado_em = $2,
+		encerrado_em = $3,
+		cancelado_em = $4
+	WHERE id = $1`
+	args := []any{ap.ID, ap.IniciadoEm, ap.EncerradoEm, ap.CanceladoEm}
+
+	_, err := s.db.Exec(ctx, q, args...)
+	return err
+}
+
+// HasAfastamentoProgramadoSobreposto informa se o analista possui algum
+// afastamento programado, não cancelado nem encerrado, que se sobreponha ao
+// intervalo [inicio, fim).
+func (s *Store) HasAfastamentoProgramadoSobreposto(ctx context.Context, analistaID int64, inicio, fim time.Time) (bool, error) {
+	q := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM afastamentos_programados
+		WHERE analista_id = $1
+		AND cancelado_em IS NULL
+		AND encerrado_em IS NULL
+		AND inicio < $3
+		AND fim > $2
+	)`
+
+	var ok bool
+	err := s.db.QueryRow(ctx, q, analistaID, inicio, fim).Scan(&ok)
+	return ok, err
+}
+
+// EncerrarAfastamentosProgramadosEmCurso encerra, no instante informado, os
+// afastamentos programados em curso do analista. É usado quando o analista
+// retorna antes do fim previsto.
+func (s *Store) EncerrarAfastamentosProgramadosEmCurso(ctx context.Context, analistaID int64, agora time.Time) error {
+	q := `
+	UPDATE afastamentos_programados SET
+		encerrado_em = $2
+	WHERE analista_id = $1
+	AND iniciado_em IS NOT NULL
+	AND encerrado_em IS NULL`
+
+	_, err := s.db.Exec(ctx, q, analistaID, agora)
+	return err
+}
+
+// HasAfastamentoProgramadoEmCurso informa se o analista possui algum
+// afastamento programado já iniciado e ainda não encerrado.
+func (s *Store) HasAfastamentoProgramadoEmCurso(ctx context.Context, analistaID int64) (bool, error) {
+	q := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM afastamentos_programados
+		WHERE analista_id = $1
+		AND iniciado_em IS NOT NULL
+		AND encerrado_em IS NULL
+	)`
+
+	var ok bool
+	err := s.db.QueryRow(ctx, q, analistaID).Scan(&ok)
+	return ok, err
+}
@@ -0,0 +1,228 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAfastamentoAnalistaLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+
+	// Iniciar duas vezes mantém apenas um afastamento em aberto.
+	for range 2 {
+		if err := store.IniciarAfastamentoAnalista(t.Context(), usuario.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	afastamentos, err := store.ListAfastamentosAnalista(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(afastamentos) != 1 || afastamentos[0].Fim.Valid {
+		t.Fatalf("expected 1 open afastamento, got %+v", afastamentos)
+	}
+
+	if err := store.EncerrarAfastamentoAnalista(t.Context(), usuario.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.IniciarAfastamentoAnalista(t.Context(), usuario.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	afastamentos, err = store.ListAfastamentosAnalista(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(afastamentos) != 2 {
+		t.Fatalf("expected 2 afastamentos, got %d", len(afastamentos))
+	}
+	abertos := 0
+	for _, a := range afastamentos {
+		if !a.Fim.Valid {
+			abertos++
+		}
+	}
+	if abertos != 1 {
+		t.Fatalf("expected 1 open afastamento, got %d", abertos)
+	}
+}
+
+func TestAfastamentoProgramado(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+	agora := time.Now()
+
+	ap := &AfastamentoProgramado{
+		AnalistaID: usuario.ID,
+		Inicio:     agora.Add(-time.Hour),
+		Fim:        agora.Add(time.Hour),
+		Motivo:     "Férias",
+	}
+	if err := store.SaveAfastamentoProgramado(t.Context(), ap); err != nil {
+		t.Fatal(err)
+	}
+
+	// Períodos coincidentes são detectados, mas períodos adjacentes não.
+	ok, err := store.HasAfastamentoProgramadoSobreposto(t.Context(), usuario.ID, agora, agora.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected overlapping afastamento")
+	}
+	ok, err = store.HasAfastamentoProgramadoSobreposto(t.Context(), usuario.ID, ap.Fim, ap.Fim.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected no overlapping afastamento")
+	}
+
+	_, err = store.GetAfastamentoProgramadoAEncerrar(t.Context(), ap.Fim)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before iniciar, got %v", err)
+	}
+
+	read, err := store.GetAfastamentoProgramadoAIniciar(t.Context(), agora)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != ap.ID || read.Motivo != ap.Motivo {
+		t.Fatalf("unexpected afastamento: %+v", read)
+	}
+
+	read.IniciadoEm = sql.Null[time.Time]{V: agora, Valid: true}
+	if err := store.UpdateAfastamentoProgramado(t.Context(), read); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = store.GetAfastamentoProgramadoAIniciar(t.Context(), agora)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after iniciar, got %v", err)
+	}
+	emCurso, err := store.HasAfastamentoProgramadoEmCurso(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !emCurso {
+		t.Fatal("expected afastamento em curso")
+	}
+
+	// O afastamento só é encerrado após o fim.
+	_, err = store.GetAfastamentoProgramadoAEncerrar(t.Context(), agora)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before fim, got %v", err)
+	}
+	read, err = store.GetAfastamentoProgramadoAEncerrar(t.Context(), ap.Fim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != ap.ID {
+		t.Fatalf("expected afastamento %d, got %d", ap.ID, read.ID)
+	}
+
+	if err := store.EncerrarAfastamentosProgramadosEmCurso(t.Context(), usuario.ID, agora); err != nil {
+		t.Fatal(err)
+	}
+	emCurso, err = store.HasAfastamentoProgramadoEmCurso(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if emCurso {
+		t.Fatal("expected no afastamento em curso")
+	}
+
+	aa, err := store.ListAfastamentosProgramados(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aa) != 1 || !aa[0].EncerradoEm.Valid {
+		t.Fatalf("expected 1 encerrado afastamento, got %+v", aa)
+	}
+}
+
+func TestAfastamentoAnalistaEm(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+	inicio := time.Now().Add(-48 * time.Hour).Truncate(time.Microsecond)
+	fim := inicio.Add(24 * time.Hour)
+
+	if err := store.IniciarAfastamentoAnalistaEm(t.Context(), usuario.ID, inicio); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EncerrarAfastamentoAnalistaEm(t.Context(), usuario.ID, fim); err != nil {
+		t.Fatal(err)
+	}
+
+	afastamentos, err := store.ListAfastamentosAnalista(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(afastamentos) != 1 {
+		t.Fatalf("expected 1 afastamento, got %d", len(afastamentos))
+	}
+	a := afastamentos[0]
+	if !a.Inicio.Equal(inicio) || !a.Fim.Valid || !a.Fim.V.Equal(fim) {
+		t.Fatalf("unexpected afastamento: %+v", a)
+	}
+}
+
+func TestAfastamentoAnalistaManual(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+
+	// O afastamento aberto por um afastamento programado não é manual.
+	if err := store.IniciarAfastamentoAnalistaEm(t.Context(), usuario.ID, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	manual, err := store.HasAfastamentoManualAberto(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manual {
+		t.Fatal("expected no manual afastamento")
+	}
+
+	// O afastamento imediato durante o programado torna o afastamento manual.
+	if err := store.IniciarAfastamentoAnalista(t.Context(), usuario.ID); err != nil {
+		t.Fatal(err)
+	}
+	manual, err = store.HasAfastamentoManualAberto(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manual {
+		t.Fatal("expected manual afastamento")
+	}
+
+	afastamentos, err := store.ListAfastamentosAnalista(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(afastamentos) != 1 || !afastamentos[0].Manual {
+		t.Fatalf("expected 1 manual afastamento, got %+v", afastamentos)
+	}
+
+	if err := store.EncerrarAfastamentoAnalista(t.Context(), usuario.ID); err != nil {
+		t.Fatal(err)
+	}
+	manual, err = store.HasAfastamentoManualAberto(t.Context(), usuario.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manual {
+		t.Fatal("expected no manual afastamento after encerrar")
+	}
+}
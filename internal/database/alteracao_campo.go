@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Origens das alterações dos dados de um processo de aposentadoria.
+const (
+	// OrigemAlteracaoManual é a correção feita por um usuário.
+	OrigemAlteracaoManual = "MANUAL"
+	// OrigemAlteracaoReprocessamento é a alteração resultante de uma nova
+	// extração dos documentos do processo.
+	OrigemAlteracaoReprocessamento = "REPROCESSAMENTO"
+)
+
+// AlteracaoCampoProcesso registra a alteração de um dos dados extraídos de
+// um processo de aposentadoria.
+type AlteracaoCampoProcesso struct {
+	ID                      int64            `db:"id"`
+	ProcessoAposentadoriaID int64            `db:"processo_aposentadoria_id"`
+	Campo                   string           `db:"campo"`
+	ValorAnterior           string           `db:"valor_anterior"`
+	ValorNovo               string           `db:"valor_novo"`
+	Origem                  string           `db:"origem"`
+	Motivo                  string           `db:"motivo"`
+	UsuarioID               sql.Null[int64]  `db:"usuario_id"`
+	Usuario                 sql.Null[string] `db:"usuario"`
+	CriadoEm                time.Time        `db:"criado_em"`
+}
+
+// SaveAlteracaoCampoProcesso registra a alteração de um campo.
+func (s *Store) SaveAlteracaoCampoProcesso(ctx context.Context, a *AlteracaoCampoProcesso) error {
+	q := `
+	INSERT INTO alteracoes_campos_processo (
+		processo_aposentadoria_id, campo, valor_anterior, valor_novo, origem,
+		motivo, usuario_id
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, criado_em`
+	args := []any{
+		a.ProcessoAposentadoriaID,
+		a.Campo,
+		a.ValorAnterior,
+		a.ValorNovo,
+		a.Origem,
+		a.Motivo,
+		a.UsuarioID,
+	}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&a.ID, &a.CriadoEm)
+}
+
+// ListAlteracoesCamposProcesso retorna as alterações dos dados de um processo
+// de aposentadoria, da mais recente para a mais antiga, com o nome do usuário
+// responsável.
+func (s *Store) ListAlteracoesCamposProcesso(ctx context.Context, paID int64) ([]*AlteracaoCampoProcesso, error) {
+	q := `
+	SELECT
+		a.id, a.processo_aposentadoria_id, a.campo, a.valor_anterior,
+		a.valor_novo, a.origem, a.motivo, a.usuario_id, u.nome AS usuario,
+		a.criado_em
+	FROM alteracoes_campos_processo a
+	LEFT JOIN usuarios u ON u.id = a.usuario_id
+	WHERE a.processo_aposentadoria_id = $1
+	ORDER BY a.criado_em DESC, a.id DESC`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[AlteracaoCampoProcesso])
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestUpdateDadosProcessoAposentadoria(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+	pa := seedProcessoAposentadoria(t, store, "38201-000070/2024-10")
+
+	got, err := store.GetProcessoAposentadoriaByProcessoID(t.Context(), pa.ProcessoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, got.ID)
+	}
+
+	pa.CPFRequerente = "12345678901"
+	pa.DataNascimentoRequerente = time.Date(1960, 5, 10, 0, 0, 0, 0, time.UTC)
+	pa.Invalidez = true
+	pa.Alertas = []string{"Dados corrigidos"}
+	if err := store.UpdateDadosProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CPFRequerente != "12345678901" || !got.Invalidez || len(got.Alertas) != 1 {
+		t.Fatalf("unexpected processo: %+v", got)
+	}
+
+	a := &AlteracaoCampoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		Campo:                   "cpf_requerente",
+		ValorAnterior:           "",
+		ValorNovo:               "12345678901",
+		Origem:                  OrigemAlteracaoManual,
+		UsuarioID:               sql.Null[int64]{V: usuario.ID, Valid: true},
+	}
+	if err := store.SaveAlteracaoCampoProcesso(t.Context(), a); err != nil {
+		t.Fatal(err)
+	}
+
+	aa, err := store.ListAlteracoesCamposProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aa) != 1 || aa[0].ID != a.ID || aa[0].Origem != OrigemAlteracaoManual {
+		t.Fatalf("unexpected alterações: %+v", aa)
+	}
+	if !aa[0].Usuario.Valid || aa[0].Usuario.V != usuario.Nome {
+		t.Fatalf("expected usuario %q, got %+v", usuario.Nome, aa[0].Usuario)
+	}
+}
@@ -12,6 +12,27 @@ import (
 
 var (
 	ErrAnalistaExists = errors.New("analista already exists")
+	// ErrLimiteProcessos é o erro retornado quando um processo é colocado em
+	// análise para um analista que já atingiu o seu limite de processos.
+	ErrLimiteProcessos = errors.New("analista atingiu o limite de processos em análise")
+)
+
+// Competências dos analistas. Um processo só é atribuído a analistas que
+// possuam todas as competências exigidas por ele.
+const (
+	// CompetenciaJudicial permite a análise de processos judicializados.
+	CompetenciaJudicial = "JUDICIAL"
+	// CompetenciaInvalidez permite a análise de processos de invalidez.
+	CompetenciaInvalidez = "INVALIDEZ"
+	// CompetenciaOrgaoSEE permite a análise de processos originados na SEE.
+	CompetenciaOrgaoSEE = "ORGAO_SEE"
+)
+
+// Níveis de senioridade dos analistas.
+const (
+	NivelJunior = "JUNIOR"
+	NivelPleno  = "PLENO"
+	NivelSenior = "SENIOR"
 )
 
 type Analista struct {
@@ -21,13 +42,31 @@ type Analista struct {
 	SEIUnidadeSigla    string              `db:"sei_unidade_sigla" json:"sei_unidade_sigla"`
 	Afastado           bool                `db:"afastado" json:"afastado"`
 	UltimaAtribuicaoEm sql.Null[time.Time] `db:"ultima_atribuicao_em" json:"ultima_atribuicao_em"`
+	Competencias       []string            `db:"competencias" json:"competencias"`
+	Nivel              string              `db:"nivel" json:"nivel"`
+	// LimiteProcessos é a quantidade máxima de processos EM_ANALISE que o
+	// analista pode manter ao mesmo tempo.
+	LimiteProcessos int `db:"limite_processos" json:"limite_processos"`
 }
 
 // SaveAnalista insere os dados de analista vinculado a um usuário no banco de dados.
 func (s *Store) SaveAnalista(ctx context.Context, analista *Analista) error {
 	q := `
-	INSERT INTO analistas (usuario_id, orgao, sei_unidade_id, sei_unidade_sigla, afastado, ultima_atribuicao_em)
-	VALUES ($1, $2, $3, $4, $5, $6)`
+	INSERT INTO analistas (
+		usuario_id, orgao, sei_unidade_id, sei_unidade_sigla, afastado,
+		ultima_atribuicao_em, competencias, nivel, limite_processos
+	)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	if analista.Competencias == nil {
+		analista.Competencias = []string{}
+	}
+	if analista.Nivel == "" {
+		analista.Nivel = NivelPleno
+	}
+	if analista.LimiteProcessos == 0 {
+		analista.LimiteProcessos = 1
+	}
 
 	args := []any{
 		analista.UsuarioID,
@@ -36,6 +75,9 @@ func (s *Store) SaveAnalista(ctx context.Context, analista *Analista) error {
 		analista.SEIUnidadeSigla,
 		analista.Afastado,
 		analista.UltimaAtribuicaoEm,
+		analista.Competencias,
+		analista.Nivel,
+		analista.LimiteProcessos,
 	}
 
 	_, err := s.db.Exec(ctx, q, args...)
@@ -53,7 +95,7 @@ func (s *Store) GetAnalista(ctx context.Context, usuarioID int64) (*Analista, er
 	q := `
 	SELECT
 		usuario_id, orgao, sei_unidade_id, sei_unidade_sigla,
-		afastado, ultima_atribuicao_em
+		afastado, ultima_atribuicao_em, competencias, nivel, limite_processos
 	FROM analistas
 	WHERE usuario_id = $1`
 
@@ -75,7 +117,7 @@ func (s *Store) ListAnalistas(ctx context.Context) ([]*Analista, error) {
 	q := `
 	SELECT
 		usuario_id, orgao, sei_unidade_id, sei_unidade_sigla,
-		afastado, ultima_atribuicao_em
+		afastado, ultima_atribuicao_em, competencias, nivel, limite_processos
 	FROM analistas`
 
 	rows, err := s.db.Query(ctx, q)
@@ -90,7 +132,7 @@ func (s *Store) GetAnalistasMap(ctx context.Context, ids []int64) (map[int64]*An
 	q := `
 	SELECT
 		usuario_id, orgao, sei_unidade_id, sei_unidade_sigla,
-		afastado, ultima_atribuicao_em
+		afastado, ultima_atribuicao_em, competencias, nivel, limite_processos
 	FROM analistas
 	WHERE usuario_id = ANY($1)`
 
@@ -116,10 +158,17 @@ func (s *Store) UpdateAnalista(ctx context.Context, analista *Analista) error {
 		sei_unidade_id = $3,
 		sei_unidade_sigla = $4,
 		afastado = $5,
-		ultima_atribuicao_em = $6
+		ultima_atribuicao_em = $6,
+		competencias = $7,
+		nivel = $8,
+		limite_processos = $9
 	WHERE usuario_id = $1
 	RETURNING ultima_atribuicao_em`
 
+	if analista.Competencias == nil {
+		analista.Competencias = []string{}
+	}
+
 	args := []any{
 		analista.UsuarioID,
 		analista.Orgao,
@@ -127,6 +176,9 @@ func (s *Store) UpdateAnalista(ctx context.Context, analista *Analista) error {
 		analista.SEIUnidadeSigla,
 		analista.Afastado,
 		analista.UltimaAtribuicaoEm,
+		analista.Competencias,
+		analista.Nivel,
+		analista.LimiteProcessos,
 	}
 
 	err := s.db.QueryRow(ctx, q, args...).Scan(&analista.UltimaAtribuicaoEm)
@@ -146,25 +198,54 @@ func (s *Store) DeleteAnalista(ctx context.Context, usuarioID int64) error {
 	return nil
 }
 
-func (s *Store) GetAnalistaDisponivel(ctx context.Context) (int64, error) {
+// GetAnalistaDisponivel retorna um analista abaixo do seu limite de processos
+// em análise que seja elegível para ao menos um processo aguardando
+// atribuição, dando precedência aos analistas com processos fixados, em
+// seguida aos que possuem menos processos em análise e, por fim, aos que
+// receberam processos há mais tempo. Processos aguardando desde antes de limite podem ser
+// atribuídos a analistas SENIOR sem as competências exigidas.
+func (s *Store) GetAnalistaDisponivel(ctx context.Context, limite time.Time) (int64, error) {
 	q := `
 	SELECT a.usuario_id
 	FROM analistas a
 	JOIN usuarios u ON u.id = a.usuario_id
 	WHERE afastado = FALSE
 	AND u.email_verificado = TRUE
-	AND NOT EXISTS (
-		SELECT 1
+	AND (
+		SELECT COUNT(*)
 		FROM processos_aposentadoria pa
 		WHERE analista_id = u.id
 		AND status = 'EM_ANALISE'
+	) < a.limite_processos
+	AND EXISTS (
+		SELECT 1
+		FROM processos_aposentadoria pa
+		JOIN processos p ON p.id = pa.processo_id
+		` + joinFixacaoAtiva + `
+		WHERE pa.status IN ('RETORNO_DILIGENCIA', 'ANALISE_PENDENTE')
+		AND (pf.analista_id IS NULL OR pf.analista_id = a.usuario_id)
+		AND ` + condicaoElegivel("$1") + `
 	)
-	ORDER BY a.ultima_atribuicao_em ASC NULLS FIRST
+	ORDER BY
+		EXISTS (
+			SELECT 1
+			FROM processos_fixados pf
+			JOIN processos_aposentadoria pa ON pa.id = pf.processo_aposentadoria_id
+			WHERE pf.analista_id = a.usuario_id
+			AND pa.status IN ('RETORNO_DILIGENCIA', 'ANALISE_PENDENTE')
+		) DESC,
+		(
+			SELECT COUNT(*)
+			FROM processos_aposentadoria pa
+			WHERE pa.analista_id = a.usuario_id
+			AND pa.status = 'EM_ANALISE'
+		) ASC,
+		a.ultima_atribuicao_em ASC NULLS FIRST
 	LIMIT 1
-	FOR UPDATE SKIP LOCKED`
+	FOR UPDATE OF a SKIP LOCKED`
 
 	var usuarioID int64
-	err := s.db.QueryRow(ctx, q).Scan(&usuarioID)
+	err := s.db.QueryRow(ctx, q, limite).Scan(&usuarioID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return 0, ErrNotFound
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CategoriaDiligencia representa uma versão de uma categoria do catálogo de
+// diligências. O código identifica a categoria entre versões e apenas uma
+// versão de cada código pode estar ativa.
+type CategoriaDiligencia struct {
+	ID           int64           `db:"id"`
+	Codigo       string          `db:"codigo"`
+	Versao       int             `db:"versao"`
+	Nome         string          `db:"nome"`
+	Descricao    string          `db:"descricao"`
+	ListaFechada bool            `db:"lista_fechada"`
+	Ordem        int             `db:"ordem"`
+	Ativa        bool            `db:"ativa"`
+	CriadoPor    sql.Null[int64] `db:"criado_por"`
+	CriadoEm     time.Time       `db:"criado_em"`
+}
+
+// SubcategoriaDiligencia representa um item da lista fechada de uma versão de
+// categoria de diligência.
+type SubcategoriaDiligencia struct {
+	ID          int64  `db:"id"`
+	CategoriaID int64  `db:"categoria_id"`
+	Codigo      string `db:"codigo"`
+	Nome        string `db:"nome"`
+	Ordem       int    `db:"ordem"`
+}
+
+// SaveCategoriaDiligencia cria uma nova versão, inativa, de uma categoria de
+// diligência, com o próximo número de versão disponível para o código.
+func (s *Store) SaveCategoriaDiligencia(ctx context.Context, c *CategoriaDiligencia) error {
+	q := `
+	INSERT INTO categorias_diligencia (codigo, versao, nome, descricao, lista_fechada, ordem, criado_por)
+	SELECT $1, COALESCE(MAX(versao), 0) + 1, $2, $3, $4, $5, $6
+	FROM categorias_diligencia
+	WHERE codigo = $1
+	RETURNING id, versao, ativa, criado_em`
+	args := []any{c.Codigo, c.Nome, c.Descricao, c.ListaFechada, c.Ordem, c.CriadoPor}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&c.ID, &c.Versao, &c.Ativa, &c.CriadoEm)
+}
+
+// GetCategoriaDiligencia retorna uma versão de categoria de diligência pelo
+// ID. Retorna [ErrNotFound] caso não exista.
+func (s *Store) GetCategoriaDiligencia(ctx context.Context, id int64) (*CategoriaDiligencia, error) {
+	q := `
+	SELECT
+		id, codigo, versao, nome, descricao, lista_fechada, ordem, ativa,
+		criado_por, criado_em
+	FROM categorias_diligencia
+	WHERE id = $1`
+
+	rows, err := s.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	c, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[CategoriaDiligencia])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListCategoriasDiligencia retorna as categorias de diligência ordenadas para
+// exibição. Quando apenasAtivas é falso, inclui versões anteriores e
+// categorias desativadas.
+func (s *Store) ListCategoriasDiligencia(ctx context.Context, apenasAtivas bool) ([]*CategoriaDiligencia, error) {
+	q := `
+	SELECT
+		id, codigo, versao, nome, descricao, lista_fechada, ordem, ativa,
+		criado_por, criado_em
+	FROM categorias_diligencia
+	WHERE ativa OR NOT $1
+	ORDER BY ordem, codigo, versao DESC`
+
+	rows, err := s.db.Query(ctx, q, apenasAtivas)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[CategoriaDiligencia])
+}
+
+// AtivarCategoriaDiligencia define a versão informada como a única ativa do
+// seu código. Deve ser executado dentro de uma transação.
+func (s *Store) AtivarCategoriaDiligencia(ctx context.Context, id int64) error {
+	q := `
+	UPDATE categorias_diligencia SET ativa = FALSE
+	WHERE ativa AND id <> $1
+	AND codigo = (SELECT codigo FROM categorias_diligencia WHERE id = $1)`
+
+	_, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.db.Exec(ctx, `UPDATE categorias_diligencia SET ativa = TRUE WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DesativarCategoriaDiligencia desativa uma versão de categoria de diligência,
+// que deixa de ser aceita em novos itens.
+func (s *Store) DesativarCategoriaDiligencia(ctx context.Context, id int64) error {
+	tag, err := s.db.Exec(ctx, `UPDATE categorias_diligencia SET ativa = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SaveSubcategoriaDiligencia insere um item na lista fechada de uma versão de
+// categoria de diligência.
+func (s *Store) SaveSubcategoriaDiligencia(ctx context.Context, sub *SubcategoriaDiligencia) error {
+	q := `
+	INSERT INTO subcategorias_diligencia (categoria_id, codigo, nome, ordem)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id`
+	args := []any{sub.CategoriaID, sub.Codigo, sub.Nome, sub.Ordem}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&sub.ID)
+}
+
+// ListSubcategoriasDiligencia retorna as subcategorias das versões de
+// categoria informadas, na ordem de exibição.
+func (s *Store) ListSubcategoriasDiligencia(ctx context.Context, categoriaIDs []int64) ([]*SubcategoriaDiligencia, error) {
+	q := `
+	SELECT id, categoria_id, codigo, nome, ordem
+	FROM subcategorias_diligencia
+	WHERE categoria_id = ANY($1)
+	ORDER BY categoria_id, ordem, id`
+
+	rows, err := s.db.Query(ctx, q, categoriaIDs)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[SubcategoriaDiligencia])
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCategoriaDiligenciaLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	// A migração cria o catálogo inicial de docs/diligencia.md.
+	ativas, err := store.ListCategoriasDiligencia(t.Context(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ativas) != 7 {
+		t.Fatalf("expected 7 categorias, got %d", len(ativas))
+	}
+	inicial := ativas[0]
+	if inicial.Codigo != "documentos_ausentes" || !inicial.ListaFechada || inicial.Versao != 1 {
+		t.Fatalf("unexpected categoria inicial: %+v", inicial)
+	}
+
+	subs, err := store.ListSubcategoriasDiligencia(t.Context(), []int64{inicial.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 17 {
+		t.Fatalf("expected 17 subcategorias, got %d", len(subs))
+	}
+
+	c := &CategoriaDiligencia{
+		Codigo:       inicial.Codigo,
+		Nome:         inicial.Nome,
+		ListaFechada: true,
+		Ordem:        inicial.Ordem,
+	}
+	if err := store.SaveCategoriaDiligencia(t.Context(), c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Versao != 2 || c.Ativa {
+		t.Fatalf("unexpected categoria: %+v", c)
+	}
+
+	sub := &SubcategoriaDiligencia{CategoriaID: c.ID, Codigo: "novo", Nome: "Novo documento"}
+	if err := store.SaveSubcategoriaDiligencia(t.Context(), sub); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.AtivarCategoriaDiligencia(t.Context(), c.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	anterior, err := store.GetCategoriaDiligencia(t.Context(), inicial.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anterior.Ativa {
+		t.Fatal("expected versão anterior to be inactive")
+	}
+
+	todas, err := store.ListCategoriasDiligencia(t.Context(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todas) != 8 {
+		t.Fatalf("expected 8 versões, got %d", len(todas))
+	}
+
+	if err := store.DesativarCategoriaDiligencia(t.Context(), c.ID); err != nil {
+		t.Fatal(err)
+	}
+	ativas, err = store.ListCategoriasDiligencia(t.Context(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ativas) != 6 {
+		t.Fatalf("expected 6 categorias ativas, got %d", len(ativas))
+	}
+
+	err = store.AtivarCategoriaDiligencia(t.Context(), c.ID+1000)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
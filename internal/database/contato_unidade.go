@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrContatoUnidadeTaken é o erro retornado ao tentar salvar um contato com
+// email já cadastrado para a mesma unidade.
+var ErrContatoUnidadeTaken = errors.New("duplicate contato email for unidade")
+
+// ContatoUnidade é um contato de RH de uma unidade do SEI, notificado sobre as
+// diligências dos processos originados na unidade.
+type ContatoUnidade struct {
+	ID           int64     `db:"id"`
+	UnidadeSigla string    `db:"unidade_sigla"`
+	Nome         string    `db:"nome"`
+	Email        string    `db:"email"`
+	CriadoEm     time.Time `db:"criado_em"`
+	AtualizadoEm time.Time `db:"atualizado_em"`
+}
+
+func contatoUnidadeError(err error) error {
+	if err != nil && strings.Contains(err.Error(), "contatos_unidade_email_key") {
+		return ErrContatoUnidadeTaken
+	}
+	return err
+}
+
+// SaveContatoUnidade cadastra um contato de unidade. Retorna
+// [ErrContatoUnidadeTaken] se o email já estiver cadastrado para a unidade.
+func (s *Store) SaveContatoUnidade(ctx context.Context, c *ContatoUnidade) error {
+	q := `
+	INSERT INTO contatos_unidade (unidade_sigla, nome, email)
+	VALUES ($1, $2, $3)
+	RETURNING id, criado_em, atualizado_em`
+	args := []any{c.UnidadeSigla, c.Nome, c.Email}
+
+	err := s.db.QueryRow(ctx, q, args...).Scan(&c.ID, &c.CriadoEm, &c.AtualizadoEm)
+	return contatoUnidadeError(err)
+}
+
+// UpdateContatoUnidade atualiza os dados de um contato de unidade. Retorna
+// [ErrContatoUnidadeTaken] se o email já estiver cadastrado para a unidade.
+func (s *Store) UpdateContatoUnidade(ctx context.Context, c *ContatoUnidade) error {
+	q := `
+	UPDATE contatos_unidade SET
+		unidade_sigla = $2,
+		nome = $3,
+		email = $4,
+		atualizado_em = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING atualizado_em`
+	args := []any{c.ID, c.UnidadeSigla, c.Nome, c.Email}
+
+	err := s.db.QueryRow(ctx, q, args...).Scan(&c.AtualizadoEm)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return contatoUnidadeError(err)
+}
+
+// GetContatoUnidade retorna um contato de unidade pelo ID.
+func (s *Store) GetContatoUnidade(ctx context.Context, id int64) (*ContatoUnidade, error) {
+	q := `
+	SELECT id, unidade_sigla, nome, email, criado_em, atualizado_em
+	FROM contatos_unidade
+	WHERE id = $1`
+
+	rows, err := s.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	c, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[ContatoUnidade])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListContatosUnidade retorna os contatos cadastrados para a unidade
+// informada, ou de todas as unidades se sigla for vazia.
+func (s *Store) ListContatosUnidade(ctx context.Context, sigla string) ([]*ContatoUnidade, error) {
+	q := `
+	SELECT id, unidade_sigla, nome, email, criado_em, atualizado_em
+	FROM contatos_unidade
+	WHERE $1 = '' OR unidade_sigla = $1
+	ORDER BY unidade_sigla, nome, email`
+
+	rows, err := s.db.Query(ctx, q, sigla)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ContatoUnidade])
+}
+
+// DeleteContatoUnidade remove um contato de unidade.
+func (s *Store) DeleteContatoUnidade(ctx context.Context, id int64) error {
+	tag, err := s.db.Exec(ctx, `DELETE FROM contatos_unidade WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
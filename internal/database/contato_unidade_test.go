@@ -0,0 +1,60 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestContatoUnidadeLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	c := &ContatoUnidade{UnidadeSigla: "SEE/RH", Nome: "Maria", Email: "maria@example.com"}
+	if err := store.SaveContatoUnidade(t.Context(), c); err != nil {
+		t.Fatal(err)
+	}
+	if c.ID == 0 {
+		t.Fatal("expected ID to be populated")
+	}
+
+	dup := &ContatoUnidade{UnidadeSigla: "SEE/RH", Email: "maria@example.com"}
+	if err := store.SaveContatoUnidade(t.Context(), dup); !errors.Is(err, ErrContatoUnidadeTaken) {
+		t.Fatalf("expected ErrContatoUnidadeTaken, got %v", err)
+	}
+
+	outro := &ContatoUnidade{UnidadeSigla: "SEF/RH", Email: "maria@example.com"}
+	if err := store.SaveContatoUnidade(t.Context(), outro); err != nil {
+		t.Fatal(err)
+	}
+
+	cc, err := store.ListContatosUnidade(t.Context(), "SEE/RH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cc) != 1 || cc[0].ID != c.ID {
+		t.Fatalf("unexpected contatos: %+v", cc)
+	}
+
+	c.Nome = "Maria Silva"
+	if err := store.UpdateContatoUnidade(t.Context(), c); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.GetContatoUnidade(t.Context(), c.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Nome != "Maria Silva" {
+		t.Fatalf("unexpected nome: %q", got.Nome)
+	}
+
+	if err := store.DeleteContatoUnidade(t.Context(), c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetContatoUnidade(t.Context(), c.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := store.DeleteContatoUnidade(t.Context(), c.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
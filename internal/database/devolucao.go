@@ -0,0 +1,167 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DevolucaoProcesso registra a devolução de um processo à fila pelo analista
+// que o tinha em análise.
+type DevolucaoProcesso struct {
+	ID                      int64  `db:"id"`
+	ProcessoAposentadoriaID int64  `db:"processo_aposentadoria_id"`
+	AnalistaID              int64  `db:"analista_id"`
+	Motivo                  string `db:"motivo"`
+	// ExcluirAnalista indica que o analista não deve voltar a receber o
+	// processo.
+	ExcluirAnalista bool      `db:"excluir_analista"`
+	CriadoEm        time.Time `db:"criado_em"`
+}
+
+// SaveDevolucaoProcesso registra a devolução de um processo.
+func (s *Store) SaveDevolucaoProcesso(ctx context.Context, d *DevolucaoProcesso) error {
+	q := `
+	INSERT INTO devolucoes_processo (processo_aposentadoria_id, analista_id, motivo, excluir_analista)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, criado_em`
+	args := []any{d.ProcessoAposentadoriaID, d.AnalistaID, d.Motivo, d.ExcluirAnalista}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&d.ID, &d.CriadoEm)
+}
+
+// ExcluirAnalistaProcesso impede que o processo de aposentadoria volte a ser
+// atribuído ao analista, exceto se for fixado para ele. Uma exclusão
+// temporária existente passa a ser permanente.
+func (s *Store) ExcluirAnalistaProcesso(ctx context.Context, paID, analistaID int64) error {
+	q := `
+	INSERT INTO processos_analistas_excluidos (processo_aposentadoria_id, analista_id)
+	VALUES ($1, $2)
+	ON CONFLICT (processo_aposentadoria_id, analista_id) DO UPDATE SET
+		temporaria = FALSE,
+		expira_em = NULL`
+
+	_, err := s.db.Exec(ctx, q, paID, analistaID)
+	return err
+}
+
+// ExcluirAnalistaProcessoTemporariamente impede que o processo de
+// aposentadoria seja atribuído ao analista até que a exclusão seja removida
+// por [Store.DeleteExclusaoTemporaria] ou até expiraEm. Não altera uma
+// exclusão permanente.
+func (s *Store) ExcluirAnalistaProcessoTemporariamente(ctx context.Context, paID, analistaID int64, expiraEm time.Time) error {
+	q := `
+	INSERT INTO processos_analistas_excluidos (processo_aposentadoria_id, analista_id, temporaria, expira_em)
+	VALUES ($1, $2, TRUE, $3)
+	ON CONFLICT (processo_aposentadoria_id, analista_id) DO UPDATE SET
+		expira_em = EXCLUDED.expira_em
+	WHERE processos_analistas_excluidos.temporaria`
+
+	_, err := s.db.Exec(ctx, q, paID, analistaID, expiraEm)
+	return err
+}
+
+// DeleteExclusaoTemporaria remove a exclusão temporária do analista no
+// processo de aposentadoria, se houver.
+func (s *Store) DeleteExclusaoTemporaria(ctx context.Context, paID, analistaID int64) error {
+	q := `
+	DELETE FROM processos_analistas_excluidos
+	WHERE processo_aposentadoria_id = $1
+	AND analista_id = $2
+	AND temporaria`
+
+	_, err := s.db.Exec(ctx, q, paID, analistaID)
+	return err
+}
+
+// DeleteExclusoesTemporariasExpiradas remove as exclusões temporárias
+// expiradas de todos os processos, retornando a quantidade removida.
+func (s *Store) DeleteExclusoesTemporariasExpiradas(ctx context.Context) (int64, error) {
+	q := `
+	DELETE FROM processos_analistas_excluidos
+	WHERE temporaria
+	AND expira_em <= CURRENT_TIMESTAMP`
+
+	tag, err := s.db.Exec(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteAnalistasExcluidos remove todas as exclusões do processo de
+// aposentadoria, retornando a quantidade de exclusões permanentes removidas.
+func (s *Store) DeleteAnalistasExcluidos(ctx context.Context, paID int64) (int, error) {
+	q := `
+	WITH removidas AS (
+		DELETE FROM processos_analistas_excluidos
+		WHERE processo_aposentadoria_id = $1
+		RETURNING temporaria
+	)
+	SELECT COUNT(*) FILTER (WHERE NOT temporaria) FROM removidas`
+
+	var n int
+	err := s.db.QueryRow(ctx, q, paID).Scan(&n)
+	return n, err
+}
+
+// ListAnalistasExcluidos retorna os IDs dos analistas excluídos
+// permanentemente do processo de aposentadoria.
+func (s *Store) ListAnalistasExcluidos(ctx context.Context, paID int64) ([]int64, error) {
+	q := `
+	SELECT analista_id
+	FROM processos_analistas_excluidos
+	WHERE processo_aposentadoria_id = $1
+	AND NOT temporaria
+	ORDER BY criado_em`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int64])
+}
+
+// DevolucaoPeriodo é uma devolução de processo com os dados do processo e do
+// analista, usada no relatório de devoluções.
+type DevolucaoPeriodo struct {
+	ID                      int64     `db:"id"`
+	ProcessoAposentadoriaID int64     `db:"processo_aposentadoria_id"`
+	Numero                  string    `db:"numero"`
+	AnalistaID              int64     `db:"analista_id"`
+	Analista                string    `db:"analista"`
+	Unidade                 string    `db:"sei_unidade_sigla"`
+	Motivo                  string    `db:"motivo"`
+	ExcluirAnalista         bool      `db:"excluir_analista"`
+	CriadoEm                time.Time `db:"criado_em"`
+}
+
+// ListDevolucoesPeriodo retorna as devoluções de processos realizadas no
+// intervalo [inicio, fim), da mais recente para a mais antiga.
+func (s *Store) ListDevolucoesPeriodo(ctx context.Context, inicio, fim time.Time) ([]*DevolucaoPeriodo, error) {
+	q := `
+	SELECT
+		d.id,
+		d.processo_aposentadoria_id,
+		p.numero,
+		d.analista_id,
+		u.nome AS analista,
+		a.sei_unidade_sigla,
+		d.motivo,
+		d.excluir_analista,
+		d.criado_em
+	FROM devolucoes_processo d
+	JOIN processos_aposentadoria pa ON pa.id = d.processo_aposentadoria_id
+	JOIN processos p ON p.id = pa.processo_id
+	JOIN analistas a ON a.usuario_id = d.analista_id
+	JOIN usuarios u ON u.id = d.analista_id
+	WHERE d.criado_em >= $1 AND d.criado_em < $2
+	ORDER BY d.criado_em DESC, d.id DESC`
+
+	rows, err := s.db.Query(ctx, q, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[DevolucaoPeriodo])
+}
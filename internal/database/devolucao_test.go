@@ -0,0 +1,45 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListDevolucoesPeriodo(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, analista := seedAnalista(t, store)
+	pa := seedProcessoAposentadoria(t, store, "38201-000061/2024-10")
+
+	d := &DevolucaoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              analista.UsuarioID,
+		Motivo:                  "Processo de parente",
+		ExcluirAnalista:         true,
+	}
+	if err := store.SaveDevolucaoProcesso(t.Context(), d); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	rr, err := store.ListDevolucoesPeriodo(t.Context(), now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 1 {
+		t.Fatalf("expected 1 devolução, got %d", len(rr))
+	}
+	r := rr[0]
+	if r.ID != d.ID || r.Numero != "38201-000061/2024-10" || r.Analista != usuario.Nome || !r.ExcluirAnalista {
+		t.Fatalf("unexpected devolução: %+v", r)
+	}
+
+	rr, err = store.ListDevolucoesPeriodo(t.Context(), now.Add(time.Hour), now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 0 {
+		t.Fatalf("expected no devoluções, got %d", len(rr))
+	}
+}
@@ -28,15 +28,40 @@ type SolicitacaoDiligencia struct {
 	Status                  StatusSolicitacaoDiligencia `db:"status"`
 	CriadoEm                time.Time                   `db:"criado_em"`
 	EnviadaEm               sql.Null[time.Time]         `db:"enviada_em"`
+	// Oficio é o HTML do ofício gerado no envio da diligência.
+	Oficio string `db:"oficio"`
+	// SEIDocumentoID e SEIDocumentoNumero identificam o ofício incluído no
+	// processo do SEI.
+	SEIDocumentoID     sql.Null[string] `db:"sei_documento_id"`
+	SEIDocumentoNumero sql.Null[string] `db:"sei_documento_numero"`
+	// SEIInclusaoIniciadaEm registra o envio do ofício ao SEI enquanto o
+	// documento criado ainda não foi salvo na solicitação.
+	SEIInclusaoIniciadaEm sql.Null[time.Time] `db:"sei_inclusao_iniciada_em"`
+	// PrazoResposta é a data limite para o atendimento da diligência pela
+	// unidade de origem.
+	PrazoResposta          sql.Null[time.Time] `db:"prazo_resposta"`
+	VencimentoNotificadoEm sql.Null[time.Time] `db:"vencimento_notificado_em"`
 }
 
 // ItemDiligencia representa uma diligência individual dentro de uma solicitação.
+// CategoriaID referencia a versão do catálogo usada no preenchimento; itens
+// anteriores ao catálogo podem não ter categoria.
 type ItemDiligencia struct {
-	ID                      int64    `db:"id"`
-	SolicitacaoDiligenciaID int64    `db:"solicitacao_diligencia_id"`
-	Tipo                    string   `db:"tipo"`
-	Subcategorias           []string `db:"subcategorias"`
-	Detalhe                 string   `db:"detalhe"`
+	ID                      int64           `db:"id"`
+	SolicitacaoDiligenciaID int64           `db:"solicitacao_diligencia_id"`
+	CategoriaID             sql.Null[int64] `db:"categoria_id"`
+	Tipo                    string          `db:"tipo"`
+	Subcategorias           []string        `db:"subcategorias"`
+	Detalhe                 string          `db:"detalhe"`
+	// ItemOrigemID referencia o item da diligência anterior que deu origem a
+	// este item, quando ele é reaproveitado em uma nova rodada.
+	ItemOrigemID sql.Null[int64] `db:"item_origem_id"`
+	// Atendido registra se a unidade de origem atendeu ao item, verificado
+	// pelo analista no retorno da diligência. É nulo enquanto não verificado.
+	Atendido                 sql.Null[bool]      `db:"atendido"`
+	ObservacaoAtendimento    string              `db:"observacao_atendimento"`
+	AtendimentoRegistradoPor sql.Null[int64]     `db:"atendimento_registrado_por"`
+	AtendimentoRegistradoEm  sql.Null[time.Time] `db:"atendimento_registrado_em"`
 }
 
 // SaveSolicitacaoDiligencia insere uma nova solicitação de diligência. Caso
@@ -62,15 +87,17 @@ func (s *Store) SaveSolicitacaoDiligencia(ctx context.Context, sd *SolicitacaoDi
 	return nil
 }
 
-// UpdateSolicitacaoDiligencia atualiza os campos mutáveis (status, enviada_em)
-// de uma solicitação de diligência.
+// UpdateSolicitacaoDiligencia atualiza os campos mutáveis (status, enviada_em,
+// oficio, prazo_resposta) de uma solicitação de diligência.
 func (s *Store) UpdateSolicitacaoDiligencia(ctx context.Context, sd *SolicitacaoDiligencia) error {
 	q := `
 	UPDATE solicitacoes_diligencia SET
 		status = $2,
-		enviada_em = $3
+		enviada_em = $3,
+		oficio = $4,
+		prazo_resposta = $5
 	WHERE id = $1`
-	args := []any{sd.ID, sd.Status, sd.EnviadaEm}
+	args := []any{sd.ID, sd.Status, sd.EnviadaEm, sd.Oficio, sd.PrazoResposta}
 
 	_, err := s.db.Exec(ctx, q, args...)
 	if err != nil {
@@ -84,7 +111,9 @@ func (s *Store) UpdateSolicitacaoDiligencia(ctx context.Context, sd *Solicitacao
 func (s *Store) GetSolicitacaoDiligencia(ctx context.Context, id int64) (*SolicitacaoDiligencia, error) {
 	q := `
 	SELECT
-		id, processo_aposentadoria_id, analista_id, status, criado_em, enviada_em
+		id, processo_aposentadoria_id, analista_id, status, criado_em, enviada_em,
+		oficio, sei_documento_id, sei_documento_numero, sei_inclusao_iniciada_em,
+		prazo_resposta, vencimento_notificado_em
 	FROM solicitacoes_diligencia
 	WHERE id = $1`
 
@@ -107,7 +136,9 @@ func (s *Store) GetSolicitacaoDiligencia(ctx context.Context, id int64) (*Solici
 func (s *Store) GetRascunhoDiligencia(ctx context.Context, paID, analistaID int64) (*SolicitacaoDiligencia, error) {
 	q := `
 	SELECT
-		id, processo_aposentadoria_id, analista_id, status, criado_em, enviada_em
+		id, processo_aposentadoria_id, analista_id, status, criado_em, enviada_em,
+		oficio, sei_documento_id, sei_documento_numero, sei_inclusao_iniciada_em,
+		prazo_resposta, vencimento_notificado_em
 	FROM solicitacoes_diligencia
 	WHERE processo_aposentadoria_id = $1
 	  AND analista_id = $2
@@ -140,7 +171,9 @@ type ListSolicitacoesDiligenciaParams struct {
 func (s *Store) ListSolicitacoesDiligenciaByProcesso(ctx context.Context, params ListSolicitacoesDiligenciaParams) ([]*SolicitacaoDiligencia, error) {
 	q := `
 	SELECT
-		id, processo_aposentadoria_id, analista_id, status, criado_em, enviada_em
+		id, processo_aposentadoria_id, analista_id, status, criado_em, enviada_em,
+		oficio, sei_documento_id, sei_documento_numero, sei_inclusao_iniciada_em,
+		prazo_resposta, vencimento_notificado_em
 	FROM solicitacoes_diligencia
 	WHERE processo_aposentadoria_id = $1
 	  AND (status::text = $2 OR $2 = '')
@@ -153,6 +186,59 @@ func (s *Store) ListSolicitacoesDiligenciaByProcesso(ctx context.Context, params
 	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[SolicitacaoDiligencia])
 }
 
+// SetDocumentoSEISolicitacaoDiligencia registra o documento do SEI em que o
+// ofício de uma solicitação de diligência foi incluído.
+func (s *Store) SetDocumentoSEISolicitacaoDiligencia(ctx context.Context, id int64, documentoID, numero string) error {
+	q := `
+	UPDATE solicitacoes_diligencia SET
+		sei_documento_id = $2,
+		sei_documento_numero = $3,
+		sei_inclusao_iniciada_em = NULL
+	WHERE id = $1`
+
+	tag, err := s.db.Exec(ctx, q, id, documentoID, numero)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// IniciarInclusaoSEISolicitacaoDiligencia registra que o ofício de uma
+// solicitação de diligência está sendo enviado ao SEI.
+func (s *Store) IniciarInclusaoSEISolicitacaoDiligencia(ctx context.Context, id int64) error {
+	q := `
+	UPDATE solicitacoes_diligencia SET
+		sei_inclusao_iniciada_em = CURRENT_TIMESTAMP
+	WHERE id = $1`
+
+	tag, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CancelarInclusaoSEISolicitacaoDiligencia remove o registro de envio do
+// ofício ao SEI, quando a inclusão foi recusada e pode ser tentada novamente.
+func (s *Store) CancelarInclusaoSEISolicitacaoDiligencia(ctx context.Context, id int64) error {
+	q := `
+	UPDATE solicitacoes_diligencia SET
+		sei_inclusao_iniciada_em = NULL
+	WHERE id = $1`
+
+	_, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // DeleteSolicitacaoDiligencia exclui uma solicitação de diligência. Os itens
 // associados são removidos em cascata pela FK.
 func (s *Store) DeleteSolicitacaoDiligencia(ctx context.Context, id int64) error {
@@ -167,10 +253,10 @@ func (s *Store) DeleteSolicitacaoDiligencia(ctx context.Context, id int64) error
 // SaveItemDiligencia insere um novo item em uma solicitação de diligência.
 func (s *Store) SaveItemDiligencia(ctx context.Context, item *ItemDiligencia) error {
 	q := `
-	INSERT INTO itens_diligencia (solicitacao_diligencia_id, tipo, subcategorias, detalhe)
-	VALUES ($1, $2, $3, $4)
+	INSERT INTO itens_diligencia (solicitacao_diligencia_id, categoria_id, tipo, subcategorias, detalhe, item_origem_id)
+	VALUES ($1, $2, $3, $4, $5, $6)
 	RETURNING id`
-	args := []any{item.SolicitacaoDiligenciaID, item.Tipo, item.Subcategorias, item.Detalhe}
+	args := []any{item.SolicitacaoDiligenciaID, item.CategoriaID, item.Tipo, item.Subcategorias, item.Detalhe, item.ItemOrigemID}
 
 	err := s.db.QueryRow(ctx, q, args...).Scan(&item.ID)
 	if err != nil {
@@ -184,7 +270,9 @@ func (s *Store) SaveItemDiligencia(ctx context.Context, item *ItemDiligencia) er
 func (s *Store) ListItensDiligencia(ctx context.Context, solicitacaoID int64) ([]*ItemDiligencia, error) {
 	q := `
 	SELECT
-		id, solicitacao_diligencia_id, tipo, subcategorias, detalhe
+		id, solicitacao_diligencia_id, categoria_id, tipo, subcategorias, detalhe,
+		item_origem_id, atendido, observacao_atendimento, atendimento_registrado_por,
+		atendimento_registrado_em
 	FROM itens_diligencia
 	WHERE solicitacao_diligencia_id = $1
 	ORDER BY id ASC`
@@ -205,3 +293,36 @@ func (s *Store) DeleteItensDiligencia(ctx context.Context, solicitacaoID int64)
 	}
 	return nil
 }
+
+// DeleteItemDiligencia remove um item de uma solicitação de diligência.
+func (s *Store) DeleteItemDiligencia(ctx context.Context, id int64) error {
+	q := `DELETE FROM itens_diligencia WHERE id = $1`
+	_, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetAtendimentoItemDiligencia registra o resultado da verificação do
+// atendimento de um item de diligência enviado.
+func (s *Store) SetAtendimentoItemDiligencia(ctx context.Context, item *ItemDiligencia) error {
+	q := `
+	UPDATE itens_diligencia SET
+		atendido = $2,
+		observacao_atendimento = $3,
+		atendimento_registrado_por = $4,
+		atendimento_registrado_em = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING atendimento_registrado_em`
+	args := []any{item.ID, item.Atendido, item.ObservacaoAtendimento, item.AtendimentoRegistradoPor}
+
+	err := s.db.QueryRow(ctx, q, args...).Scan(&item.AtendimentoRegistradoEm)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AgrupamentoDiligencia define como os itens de diligência enviados são
+// agrupados nas estatísticas.
+type AgrupamentoDiligencia string
+
+const (
+	// AgrupamentoCategoria agrupa pelo tipo (categoria) do item.
+	AgrupamentoCategoria AgrupamentoDiligencia = "categoria"
+	// AgrupamentoSubcategoria agrupa por categoria e subcategoria. Itens sem
+	// subcategoria não são contabilizados.
+	AgrupamentoSubcategoria AgrupamentoDiligencia = "subcategoria"
+	// AgrupamentoUnidade agrupa pela unidade do SEI de origem do processo.
+	AgrupamentoUnidade AgrupamentoDiligencia = "unidade"
+	// AgrupamentoOrgao agrupa pelo órgão de origem do processo, obtido da
+	// sigla da unidade do SEI (ex: SEPLAG/DCCTA -> SEPLAG).
+	AgrupamentoOrgao AgrupamentoDiligencia = "orgao"
+)
+
+var agrupamentosDiligencia = map[AgrupamentoDiligencia]struct {
+	chave string
+	join  string
+}{
+	AgrupamentoCategoria:    {chave: "i.tipo"},
+	AgrupamentoSubcategoria: {chave: "i.tipo || ' / ' || sub.nome", join: "CROSS JOIN LATERAL UNNEST(i.subcategorias) AS sub(nome)"},
+	AgrupamentoUnidade:      {chave: "p.sei_unidade_sigla"},
+	AgrupamentoOrgao:        {chave: "SPLIT_PART(p.sei_unidade_sigla, '/', 1)"},
+}
+
+// IntervaloDiligencia define o intervalo de tempo usado na tendência das
+// estatísticas de diligência.
+type IntervaloDiligencia string
+
+const (
+	IntervaloSemana IntervaloDiligencia = "week"
+	IntervaloMes    IntervaloDiligencia = "month"
+)
+
+// EstatisticasDiligenciaParams define os filtros de
+// [Store.ListEstatisticasDiligencia].
+type EstatisticasDiligenciaParams struct {
+	Agrupamento AgrupamentoDiligencia
+	Intervalo   IntervaloDiligencia
+	Inicio      sql.Null[time.Time]
+	Fim         sql.Null[time.Time]
+}
+
+// EstatisticaDiligencia é a quantidade de diligências enviadas em um período
+// para uma chave de agrupamento.
+type EstatisticaDiligencia struct {
+	Periodo      time.Time `db:"periodo"`
+	Chave        string    `db:"chave"`
+	Solicitacoes int       `db:"solicitacoes"`
+	Itens        int       `db:"itens"`
+}
+
+// ListEstatisticasDiligencia agrega os itens das solicitações de diligência
+// enviadas, pela data de envio, no intervalo e agrupamento informados.
+func (s *Store) ListEstatisticasDiligencia(ctx context.Context, params EstatisticasDiligenciaParams) ([]*EstatisticaDiligencia, error) {
+	agrupamento, ok := agrupamentosDiligencia[params.Agrupamento]
+	if !ok {
+		return nil, fmt.Errorf("invalid agrupamento: %q", params.Agrupamento)
+	}
+	if params.Intervalo != IntervaloSemana && params.Intervalo != IntervaloMes {
+		return nil, fmt.Errorf("invalid intervalo: %q", params.Intervalo)
+	}
+
+	q := fmt.Sprintf(`
+	SELECT
+		DATE_TRUNC($1, sd.enviada_em) AS periodo,
+		%s AS chave,
+		COUNT(DISTINCT sd.id) AS solicitacoes,
+		COUNT(DISTINCT i.id) AS itens
+	FROM itens_diligencia i
+	JOIN solicitacoes_diligencia sd ON sd.id = i.solicitacao_diligencia_id
+	JOIN processos_aposentadoria pa ON pa.id = sd.processo_aposentadoria_id
+	JOIN processos p ON p.id = pa.processo_id
+	%s
+	WHERE sd.status = 'enviada'
+	AND ($2::timestamptz IS NULL OR sd.enviada_em >= $2)
+	AND ($3::timestamptz IS NULL OR sd.enviada_em < $3)
+	GROUP BY 1, 2
+	ORDER BY 1, 2`, agrupamento.chave, agrupamento.join)
+	args := []any{string(params.Intervalo), params.Inicio, params.Fim}
+
+	rows, err := s.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[EstatisticaDiligencia])
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestListEstatisticasDiligencia(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	_, analista := seedAnalista(t, store)
+
+	p := &Processo{Numero: "38201-000060/2024-10", SeiUnidadeSigla: "SEE/RH"}
+	if err := store.SaveProcesso(t.Context(), p); err != nil {
+		t.Fatal(err)
+	}
+	pa := &ProcessoAposentadoria{ProcessoID: p.ID, Status: StatusProcessoEmDiligencia}
+	if err := store.SaveProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+
+	sd := &SolicitacaoDiligencia{ProcessoAposentadoriaID: pa.ID, AnalistaID: analista.UsuarioID}
+	if err := store.SaveSolicitacaoDiligencia(t.Context(), sd); err != nil {
+		t.Fatal(err)
+	}
+	itens := []*ItemDiligencia{
+		{SolicitacaoDiligenciaID: sd.ID, Tipo: "documentos_ausentes", Subcategorias: []string{"requerimento", "certidao"}},
+		{SolicitacaoDiligenciaID: sd.ID, Tipo: "outros", Subcategorias: []string{}},
+		// Subcategorias repetidas contam o item uma única vez.
+		{SolicitacaoDiligenciaID: sd.ID, Tipo: "documentos_ausentes", Subcategorias: []string{"requerimento", "requerimento"}},
+	}
+	for _, it := range itens {
+		if err := store.SaveItemDiligencia(t.Context(), it); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Rascunhos não entram nas estatísticas.
+	rascunho := &SolicitacaoDiligencia{ProcessoAposentadoriaID: pa.ID, AnalistaID: analista.UsuarioID}
+	if err := store.SaveSolicitacaoDiligencia(t.Context(), rascunho); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveItemDiligencia(t.Context(), &ItemDiligencia{SolicitacaoDiligenciaID: rascunho.ID, Tipo: "outros", Subcategorias: []string{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	sd.Status = StatusSolicitacaoEnviada
+	sd.EnviadaEm = sql.Null[time.Time]{V: time.Now(), Valid: true}
+	if err := store.UpdateSolicitacaoDiligencia(t.Context(), sd); err != nil {
+		t.Fatal(err)
+	}
+
+	ignorePeriodo := cmpopts.IgnoreFields(EstatisticaDiligencia{}, "Periodo")
+
+	tests := []struct {
+		agrupamento AgrupamentoDiligencia
+		expected    []*EstatisticaDiligencia
+	}{
+		{
+			agrupamento: AgrupamentoCategoria,
+			expected: []*EstatisticaDiligencia{
+				{Chave: "documentos_ausentes", Solicitacoes: 1, Itens: 2},
+				{Chave: "outros", Solicitacoes: 1, Itens: 1},
+			},
+		},
+		{
+			agrupamento: AgrupamentoSubcategoria,
+			expected: []*EstatisticaDiligencia{
+				{Chave: "documentos_ausentes / certidao", Solicitacoes: 1, Itens: 1},
+				{Chave: "documentos_ausentes / requerimento", Solicitacoes: 1, Itens: 2},
+			},
+		},
+		{
+			agrupamento: AgrupamentoUnidade,
+			expected: []*EstatisticaDiligencia{
+				{Chave: "SEE/RH", Solicitacoes: 1, Itens: 3},
+			},
+		},
+		{
+			agrupamento: AgrupamentoOrgao,
+			expected: []*EstatisticaDiligencia{
+				{Chave: "SEE", Solicitacoes: 1, Itens: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := store.ListEstatisticasDiligencia(t.Context(), EstatisticasDiligenciaParams{
+			Agrupamento: tt.agrupamento,
+			Intervalo:   IntervaloMes,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tt.expected, got, ignorePeriodo); diff != "" {
+			t.Errorf("%s mismatch:\n%s", tt.agrupamento, diff)
+		}
+	}
+
+	_, err := store.ListEstatisticasDiligencia(t.Context(), EstatisticasDiligenciaParams{
+		Agrupamento: "invalido",
+		Intervalo:   IntervaloMes,
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid agrupamento")
+	}
+}
@@ -337,3 +337,142 @@ func TestListSolicitacoesDiligencia_StatusFilter(t *testing.T) {
 		t.Fatalf("expected rascunho id %d, got %d", rascunho.ID, onlyRascunhos[0].ID)
 	}
 }
+
+func TestSetDocumentoSEISolicitacaoDiligencia(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000011/2024-10")
+	_, analista := seedAnalista(t, store)
+
+	sd := &SolicitacaoDiligencia{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              analista.UsuarioID,
+	}
+	if err := store.SaveSolicitacaoDiligencia(t.Context(), sd); err != nil {
+		t.Fatal(err)
+	}
+
+	sd.Oficio = "<p>Ofício</p>"
+	if err := store.UpdateSolicitacaoDiligencia(t.Context(), sd); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.IniciarInclusaoSEISolicitacaoDiligencia(t.Context(), sd.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GetSolicitacaoDiligencia(t.Context(), sd.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.SEIInclusaoIniciadaEm.Valid {
+		t.Fatal("expected inclusão to be started")
+	}
+
+	if err := store.SetDocumentoSEISolicitacaoDiligencia(t.Context(), sd.ID, "123", "0000123"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = store.GetSolicitacaoDiligencia(t.Context(), sd.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Oficio != sd.Oficio || got.SEIDocumentoID.V != "123" || got.SEIDocumentoNumero.V != "0000123" || got.SEIInclusaoIniciadaEm.Valid {
+		t.Fatalf("unexpected solicitação: %+v", got)
+	}
+
+	err = store.SetDocumentoSEISolicitacaoDiligencia(t.Context(), sd.ID+1000, "1", "1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAtendimentoItemDiligencia(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000012/2024-10")
+	_, analista := seedAnalista(t, store)
+
+	anterior := &SolicitacaoDiligencia{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              analista.UsuarioID,
+		Status:                  StatusSolicitacaoEnviada,
+	}
+	if err := store.SaveSolicitacaoDiligencia(t.Context(), anterior); err != nil {
+		t.Fatal(err)
+	}
+	origem := &ItemDiligencia{
+		SolicitacaoDiligenciaID: anterior.ID,
+		Tipo:                    "Alteração de Dados Após o Envio",
+		Subcategorias:           []string{},
+		Detalhe:                 "Benefício concedido após o envio.",
+	}
+	if err := store.SaveItemDiligencia(t.Context(), origem); err != nil {
+		t.Fatal(err)
+	}
+
+	origem.Atendido = sql.Null[bool]{V: false, Valid: true}
+	origem.ObservacaoAtendimento = "Comprovação não enviada"
+	origem.AtendimentoRegistradoPor = sql.Null[int64]{V: analista.UsuarioID, Valid: true}
+	if err := store.SetAtendimentoItemDiligencia(t.Context(), origem); err != nil {
+		t.Fatal(err)
+	}
+	if !origem.AtendimentoRegistradoEm.Valid {
+		t.Fatal("expected AtendimentoRegistradoEm to be populated")
+	}
+
+	rascunho := &SolicitacaoDiligencia{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              analista.UsuarioID,
+	}
+	if err := store.SaveSolicitacaoDiligencia(t.Context(), rascunho); err != nil {
+		t.Fatal(err)
+	}
+	copia := &ItemDiligencia{
+		SolicitacaoDiligenciaID: rascunho.ID,
+		Tipo:                    origem.Tipo,
+		Subcategorias:           origem.Subcategorias,
+		Detalhe:                 origem.Detalhe,
+		ItemOrigemID:            sql.Null[int64]{V: origem.ID, Valid: true},
+	}
+	if err := store.SaveItemDiligencia(t.Context(), copia); err != nil {
+		t.Fatal(err)
+	}
+
+	itens, err := store.ListItensDiligencia(t.Context(), anterior.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := itens[0]
+	if !got.Atendido.Valid || got.Atendido.V || got.ObservacaoAtendimento != "Comprovação não enviada" {
+		t.Fatalf("unexpected atendimento: %+v", got)
+	}
+	if got.AtendimentoRegistradoPor.V != analista.UsuarioID {
+		t.Fatalf("expected registrado por %d, got %+v", analista.UsuarioID, got.AtendimentoRegistradoPor)
+	}
+
+	itens, err = store.ListItensDiligencia(t.Context(), rascunho.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(itens) != 1 || itens[0].ItemOrigemID.V != origem.ID || itens[0].Atendido.Valid {
+		t.Fatalf("unexpected copia: %+v", itens)
+	}
+
+	if err := store.DeleteItemDiligencia(t.Context(), copia.ID); err != nil {
+		t.Fatal(err)
+	}
+	itens, err = store.ListItensDiligencia(t.Context(), rascunho.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(itens) != 0 {
+		t.Fatalf("expected no itens, got %d", len(itens))
+	}
+
+	err = store.SetAtendimentoItemDiligencia(t.Context(), &ItemDiligencia{ID: copia.ID})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
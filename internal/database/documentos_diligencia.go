@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveDocumentosSEIDiligencia registra os números dos documentos do processo
+// no SEI no envio para diligência, substituindo o registro anterior.
+func (s *Store) SaveDocumentosSEIDiligencia(ctx context.Context, paID int64, documentos []string) error {
+	q := `
+	INSERT INTO documentos_sei_diligencia (processo_aposentadoria_id, documentos)
+	VALUES ($1, $2)
+	ON CONFLICT (processo_aposentadoria_id) DO UPDATE SET
+		documentos = EXCLUDED.documentos,
+		registrado_em = CURRENT_TIMESTAMP`
+
+	if documentos == nil {
+		documentos = []string{}
+	}
+	_, err := s.db.Exec(ctx, q, paID, documentos)
+	return err
+}
+
+// GetDocumentosSEIDiligencia retorna os números dos documentos do processo no
+// SEI registrados no envio para diligência. Retorna [ErrNotFound] se não
+// houver registro.
+func (s *Store) GetDocumentosSEIDiligencia(ctx context.Context, paID int64) ([]string, error) {
+	q := `
+	SELECT documentos
+	FROM documentos_sei_diligencia
+	WHERE processo_aposentadoria_id = $1`
+
+	var documentos []string
+	err := s.db.QueryRow(ctx, q, paID).Scan(&documentos)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return documentos, nil
+}
+
+// DeleteDocumentosSEIDiligencia remove o registro dos documentos do processo
+// no SEI, encerrando a diligência em curso.
+func (s *Store) DeleteDocumentosSEIDiligencia(ctx context.Context, paID int64) error {
+	q := `DELETE FROM documentos_sei_diligencia WHERE processo_aposentadoria_id = $1`
+
+	_, err := s.db.Exec(ctx, q, paID)
+	return err
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDocumentosSEIDiligencia(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000090/2024-10")
+
+	_, err := store.GetDocumentosSEIDiligencia(t.Context(), pa.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.SaveDocumentosSEIDiligencia(t.Context(), pa.ID, []string{"100"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveDocumentosSEIDiligencia(t.Context(), pa.ID, []string{"100", "101"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GetDocumentosSEIDiligencia(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, []string{"100", "101"}) {
+		t.Fatalf("unexpected documentos: %v", got)
+	}
+
+	if err := store.DeleteDocumentosSEIDiligencia(t.Context(), pa.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetDocumentosSEIDiligencia(t.Context(), pa.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PeriodoEstatisticas delimita o período considerado nas estatísticas da fila.
+// Limites inválidos não restringem o período.
+type PeriodoEstatisticas struct {
+	Inicio sql.Null[time.Time]
+	Fim    sql.Null[time.Time]
+}
+
+func (p PeriodoEstatisticas) args() []any {
+	return []any{p.Inicio, p.Fim}
+}
+
+// ContagemStatus é a quantidade de processos em um status.
+type ContagemStatus struct {
+	Status     StatusProcesso `db:"status"`
+	Quantidade int            `db:"quantidade"`
+}
+
+// CountProcessosPorStatus retorna a quantidade de processos criados no
+// período, agrupados pelo status atual.
+func (s *Store) CountProcessosPorStatus(ctx context.Context, periodo PeriodoEstatisticas) ([]*ContagemStatus, error) {
+	q := `
+	SELECT status, COUNT(*) AS quantidade
+	FROM processos_aposentadoria
+	WHERE ($1::timestamptz IS NULL OR criado_em >= $1)
+	AND ($2::timestamptz IS NULL OR criado_em < $2)
+	GROUP BY status
+	ORDER BY status`
+
+	rows, err := s.db.Query(ctx, q, periodo.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ContagemStatus])
+}
+
+// ContagemScore é a quantidade de processos com um score.
+type ContagemScore struct {
+	Score      int `db:"score"`
+	Quantidade int `db:"quantidade"`
+}
+
+// CountProcessosPorScore retorna a quantidade de processos criados no
+// período, agrupados pelo score atual, do maior para o menor.
+func (s *Store) CountProcessosPorScore(ctx context.Context, periodo PeriodoEstatisticas) ([]*ContagemScore, error) {
+	q := `
+	SELECT COALESCE(score, 0) AS score, COUNT(*) AS quantidade
+	FROM processos_aposentadoria
+	WHERE ($1::timestamptz IS NULL OR criado_em >= $1)
+	AND ($2::timestamptz IS NULL OR criado_em < $2)
+	GROUP BY 1
+	ORDER BY 1 DESC`
+
+	rows, err := s.db.Query(ctx, q, periodo.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ContagemScore])
+}
+
+// TempoEspera resume o tempo entre a criação dos processos e a primeira
+// atribuição a um analista, em horas.
+type TempoEspera struct {
+	Amostras int     `db:"amostras"`
+	Media    float64 `db:"media"`
+	P50      float64 `db:"p50"`
+	P90      float64 `db:"p90"`
+}
+
+// GetTempoEspera calcula o tempo de espera dos processos criados no período
+// até entrarem EM_ANALISE pela primeira vez, conforme o histórico de status.
+// Processos que ainda não foram atribuídos não são considerados.
+func (s *Store) GetTempoEspera(ctx context.Context, periodo PeriodoEstatisticas) (*TempoEspera, error) {
+	q := `
+	WITH esperas AS (
+		SELECT EXTRACT(EPOCH FROM MIN(h.alterado_em) - pa.criado_em) / 3600 AS horas
+		FROM processos_aposentadoria pa
+		JOIN historico_status_processo h ON h.processo_aposentadoria_id = pa.id
+		WHERE h.status_novo = 'EM_ANALISE'
+		AND ($1::timestamptz IS NULL OR pa.criado_em >= $1)
+		AND ($2::timestamptz IS NULL OR pa.criado_em < $2)
+		GROUP BY pa.id
+	)
+	SELECT
+		COUNT(*) AS amostras,
+		COALESCE(AVG(horas), 0)::float8 AS media,
+		COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY horas), 0)::float8 AS p50,
+		COALESCE(PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY horas), 0)::float8 AS p90
+	FROM esperas`
+
+	rows, err := s.db.Query(ctx, q, periodo.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[TempoEspera])
+}
+
+// ConclusoesSemana é a quantidade de processos concluídos em uma semana.
+type ConclusoesSemana struct {
+	Semana     time.Time `db:"semana"`
+	Quantidade int       `db:"quantidade"`
+}
+
+// ListConclusoesPorSemana retorna a quantidade de processos concluídos por
+// semana no período. A semana é identificada pela data da segunda-feira.
+func (s *Store) ListConclusoesPorSemana(ctx context.Context, periodo PeriodoEstatisticas) ([]*ConclusoesSemana, error) {
+	q := `
+	SELECT DATE_TRUNC('week', alterado_em) AS semana, COUNT(*) AS quantidade
+	FROM historico_status_processo
+	WHERE status_novo = 'CONCLUIDO'
+	AND status_anterior IS DISTINCT FROM 'CONCLUIDO'
+	AND ($1::timestamptz IS NULL OR alterado_em >= $1)
+	AND ($2::timestamptz IS NULL OR alterado_em < $2)
+	GROUP BY 1
+	ORDER BY 1`
+
+	rows, err := s.db.Query(ctx, q, periodo.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ConclusoesSemana])
+}
+
+// TaxaDiligencia relaciona os processos criados no período com aqueles que
+// foram enviados ao menos uma vez para diligência.
+type TaxaDiligencia struct {
+	Total         int `db:"total"`
+	ComDiligencia int `db:"com_diligencia"`
+}
+
+// GetTaxaDiligencia retorna a quantidade de processos criados no período e
+// quantos deles passaram por diligência.
+func (s *Store) GetTaxaDiligencia(ctx context.Context, periodo PeriodoEstatisticas) (*TaxaDiligencia, error) {
+	q := `
+	SELECT
+		COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE EXISTS (
+			SELECT 1 FROM historico_status_processo h
+			WHERE h.processo_aposentadoria_id = pa.id
+			AND h.status_novo = 'EM_DILIGENCIA'
+		)) AS com_diligencia
+	FROM processos_aposentadoria pa
+	WHERE ($1::timestamptz IS NULL OR pa.criado_em >= $1)
+	AND ($2::timestamptz IS NULL OR pa.criado_em < $2)`
+
+	rows, err := s.db.Query(ctx, q, periodo.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[TaxaDiligencia])
+}
+
+// ProdutividadeAnalista resume os processos de um analista.
+type ProdutividadeAnalista struct {
+	UsuarioID  int64  `db:"usuario_id"`
+	Nome       string `db:"nome"`
+	Abertos    int    `db:"abertos"`
+	Concluidos int    `db:"concluidos"`
+}
+
+// ListProdutividadeAnalistas retorna, para cada analista, a quantidade de
+// processos em aberto sob sua responsabilidade e a quantidade de processos
+// concluídos por ele no período. Processos em diligência são atribuídos ao
+// último analista responsável.
+func (s *Store) ListProdutividadeAnalistas(ctx context.Context, periodo PeriodoEstatisticas) ([]*ProdutividadeAnalista, error) {
+	q := `
+	SELECT
+		a.usuario_id,
+		u.nome,
+		(
+			SELECT COUNT(*) FROM processos_aposentadoria pa
+			WHERE pa.status <> 'CONCLUIDO'
+			AND (
+				pa.analista_id = a.usuario_id
+				OR (pa.analista_id IS NULL AND pa.status = 'EM_DILIGENCIA' AND pa.ultimo_analista_id = a.usuario_id)
+			)
+		) AS abertos,
+		(
+			SELECT COUNT(*) FROM historico_status_processo h
+			WHERE h.usuario_id = a.usuario_id
+			AND h.status_novo = 'CONCLUIDO'
+			AND h.status_anterior IS DISTINCT FROM 'CONCLUIDO'
+			AND ($1::timestamptz IS NULL OR h.alterado_em >= $1)
+			AND ($2::timestamptz IS NULL OR h.alterado_em < $2)
+		) AS concluidos
+	FROM analistas a
+	JOIN usuarios u ON u.id = a.usuario_id
+	ORDER BY u.nome`
+
+	rows, err := s.db.Query(ctx, q, periodo.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ProdutividadeAnalista])
+}
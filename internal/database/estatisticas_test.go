@@ -0,0 +1,152 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEstatisticas(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+
+	concluido := seedProcessoAposentadoria(t, store, "numero-1")
+	seedProcessoAposentadoria(t, store, "numero-2")
+
+	transicoes := []struct {
+		pa       *ProcessoAposentadoria
+		anterior StatusProcesso
+		novo     StatusProcesso
+	}{
+		{concluido, StatusProcessoAnalisePendente, StatusProcessoEmAnalise},
+		{concluido, StatusProcessoEmAnalise, StatusProcessoEmDiligencia},
+		{concluido, StatusProcessoRetornoDiligencia, StatusProcessoEmAnalise},
+		{concluido, StatusProcessoEmAnalise, StatusProcessoConcluido},
+		// Tramitação no SEI registrada sem mudança de status.
+		{concluido, StatusProcessoConcluido, StatusProcessoConcluido},
+	}
+	for _, tr := range transicoes {
+		err := store.SaveHistoricoStatusProcesso(t.Context(), &HistoricoStatusProcesso{
+			ProcessoAposentadoriaID: tr.pa.ID,
+			StatusAnterior:          sql.Null[StatusProcesso]{V: tr.anterior, Valid: true},
+			StatusNovo:              tr.novo,
+			UsuarioID:               sql.Null[int64]{V: usuario.ID, Valid: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	concluido.Status = StatusProcessoConcluido
+	concluido.UltimoAnalistaID = sql.Null[int64]{V: usuario.ID, Valid: true}
+	if err := store.UpdateProcessoAposentadoria(t.Context(), concluido); err != nil {
+		t.Fatal(err)
+	}
+
+	periodo := PeriodoEstatisticas{}
+
+	status, err := store.CountProcessosPorStatus(t.Context(), periodo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedStatus := []*ContagemStatus{
+		{Status: StatusProcessoAnalisePendente, Quantidade: 1},
+		{Status: StatusProcessoConcluido, Quantidade: 1},
+	}
+	if diff := cmp.Diff(expectedStatus, status); diff != "" {
+		t.Fatalf("status mismatch:\n%s", diff)
+	}
+
+	espera, err := store.GetTempoEspera(t.Context(), periodo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if espera.Amostras != 1 {
+		t.Fatalf("expected 1 amostra, got %d", espera.Amostras)
+	}
+
+	semanas, err := store.ListConclusoesPorSemana(t.Context(), periodo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(semanas) != 1 || semanas[0].Quantidade != 1 {
+		t.Fatalf("expected 1 conclusão, got %+v", semanas)
+	}
+
+	taxa, err := store.GetTaxaDiligencia(t.Context(), periodo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&TaxaDiligencia{Total: 2, ComDiligencia: 1}, taxa); diff != "" {
+		t.Fatalf("taxa mismatch:\n%s", diff)
+	}
+
+	analistas, err := store.ListProdutividadeAnalistas(t.Context(), periodo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analistas) != 1 || analistas[0].Concluidos != 1 || analistas[0].Abertos != 0 {
+		t.Fatalf("unexpected produtividade: %+v", analistas)
+	}
+
+	// Um período futuro não contém nenhum processo.
+	futuro := PeriodoEstatisticas{Inicio: sql.Null[time.Time]{V: time.Now().Add(time.Hour), Valid: true}}
+	status, err = store.CountProcessosPorStatus(t.Context(), futuro)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 0 {
+		t.Fatalf("expected no processos, got %+v", status)
+	}
+}
+
+func TestProdutividadeAnalistas_Abertos(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+	ultimo := sql.Null[int64]{V: usuario.ID, Valid: true}
+
+	// Processo devolvido pelo analista, aguardando nova atribuição.
+	devolvido := seedProcessoAposentadoria(t, store, "numero-devolvido")
+	devolvido.UltimoAnalistaID = ultimo
+	if err := store.UpdateProcessoAposentadoria(t.Context(), devolvido); err != nil {
+		t.Fatal(err)
+	}
+
+	// Processo marcado como leitura inválida pelo analista.
+	leituraInvalida := seedProcessoAposentadoria(t, store, "numero-leitura-invalida")
+	leituraInvalida.Status = StatusProcessoLeituraInvalid
+	leituraInvalida.UltimoAnalistaID = ultimo
+	if err := store.UpdateProcessoAposentadoria(t.Context(), leituraInvalida); err != nil {
+		t.Fatal(err)
+	}
+
+	analistas, err := store.ListProdutividadeAnalistas(t.Context(), PeriodoEstatisticas{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analistas) != 1 || analistas[0].Abertos != 0 {
+		t.Fatalf("expected no processos abertos, got %+v", analistas)
+	}
+
+	// Processos em diligência continuam com o último analista.
+	diligencia := seedProcessoAposentadoria(t, store, "numero-diligencia")
+	diligencia.Status = StatusProcessoEmDiligencia
+	diligencia.UltimoAnalistaID = ultimo
+	if err := store.UpdateProcessoAposentadoria(t.Context(), diligencia); err != nil {
+		t.Fatal(err)
+	}
+
+	analistas, err = store.ListProdutividadeAnalistas(t.Context(), PeriodoEstatisticas{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analistas) != 1 || analistas[0].Abertos != 1 {
+		t.Fatalf("expected 1 processo aberto, got %+v", analistas)
+	}
+}
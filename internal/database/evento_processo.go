@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Tipos dos eventos de um processo de aposentadoria.
+const (
+	// EventoTramitacaoSEI é o envio do processo para outra unidade do SEI, ou
+	// a falha nesse envio.
+	EventoTramitacaoSEI = "TRAMITACAO_SEI"
+	// EventoOficioDiligencia é a inclusão do ofício de diligência no SEI.
+	EventoOficioDiligencia = "OFICIO_DILIGENCIA"
+	// EventoPrazoDiligencia é o vencimento do prazo de resposta da diligência.
+	EventoPrazoDiligencia = "PRAZO_DILIGENCIA"
+	// EventoAtendimentoDiligencia é a verificação do atendimento dos itens da
+	// diligência anterior.
+	EventoAtendimentoDiligencia = "ATENDIMENTO_DILIGENCIA"
+	// EventoReatribuicao é a transferência do processo em análise para outro
+	// analista pela gestão.
+	EventoReatribuicao = "REATRIBUICAO"
+	// EventoFixacao é a fixação do processo para um analista, ou a sua
+	// remoção, pela gestão.
+	EventoFixacao = "FIXACAO"
+	// EventoReprocessamento é a solicitação de uma nova extração dos
+	// documentos do processo com leitura inválida, ou o seu resultado quando o
+	// processo permanece com leitura inválida.
+	EventoReprocessamento = "REPROCESSAMENTO"
+	// EventoExclusao é a remoção, pela gestão, das exclusões de analistas que
+	// devolveram o processo.
+	EventoExclusao = "EXCLUSAO_ANALISTA"
+	// EventoObservacao são as observações registradas no histórico de status
+	// antes da criação dos eventos.
+	EventoObservacao = "OBSERVACAO"
+)
+
+// EventoProcesso registra uma ocorrência de um processo de aposentadoria que
+// não altera o seu status.
+type EventoProcesso struct {
+	ID                      int64            `db:"id"`
+	ProcessoAposentadoriaID int64            `db:"processo_aposentadoria_id"`
+	Tipo                    string           `db:"tipo"`
+	Descricao               string           `db:"descricao"`
+	UsuarioID               sql.Null[int64]  `db:"usuario_id"`
+	Usuario                 sql.Null[string] `db:"usuario"`
+	CriadoEm                time.Time        `db:"criado_em"`
+}
+
+// SaveEventoProcesso registra um evento de um processo de aposentadoria.
+func (s *Store) SaveEventoProcesso(ctx context.Context, e *EventoProcesso) error {
+	q := `
+	INSERT INTO eventos_processo (processo_aposentadoria_id, tipo, descricao, usuario_id)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, criado_em`
+	args := []any{e.ProcessoAposentadoriaID, e.Tipo, e.Descricao, e.UsuarioID}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&e.ID, &e.CriadoEm)
+}
+
+// ListEventosProcesso retorna os eventos de um processo de aposentadoria, do
+// mais recente para o mais antigo, com o nome do usuário responsável.
+func (s *Store) ListEventosProcesso(ctx context.Context, paID int64) ([]*EventoProcesso, error) {
+	q := `
+	SELECT
+		e.id, e.processo_aposentadoria_id, e.tipo, e.descricao, e.usuario_id,
+		u.nome AS usuario, e.criado_em
+	FROM eventos_processo e
+	LEFT JOIN usuarios u ON u.id = e.usuario_id
+	WHERE e.processo_aposentadoria_id = $1
+	ORDER BY e.criado_em DESC, e.id DESC`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[EventoProcesso])
+}
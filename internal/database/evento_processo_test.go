@@ -0,0 +1,47 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestEventoProcesso(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario := seedUsuario(t, store)
+	pa := seedProcessoAposentadoria(t, store, "38201-000091/2024-10")
+
+	tramitacao := &EventoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		Tipo:                    EventoTramitacaoSEI,
+		Descricao:               "Processo enviado no SEI para a unidade SEPLAG/AP00",
+	}
+	if err := store.SaveEventoProcesso(t.Context(), tramitacao); err != nil {
+		t.Fatal(err)
+	}
+
+	oficio := &EventoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		Tipo:                    EventoOficioDiligencia,
+		Descricao:               "Ofício de diligência incluído no SEI",
+		UsuarioID:               sql.Null[int64]{V: usuario.ID, Valid: true},
+	}
+	if err := store.SaveEventoProcesso(t.Context(), oficio); err != nil {
+		t.Fatal(err)
+	}
+
+	ee, err := store.ListEventosProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ee) != 2 {
+		t.Fatalf("expected 2 eventos, got %d", len(ee))
+	}
+	if ee[0].ID != oficio.ID || ee[0].Usuario.V != usuario.Nome {
+		t.Fatalf("unexpected evento: %+v", ee[0])
+	}
+	if ee[1].ID != tramitacao.ID || ee[1].Usuario.Valid {
+		t.Fatalf("unexpected evento: %+v", ee[1])
+	}
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Fontes dos dados de um processo de aposentadoria.
+const (
+	// FonteIA é o valor extraído dos documentos pela IA.
+	FonteIA = "IA"
+	// FonteDatalake é o valor obtido dos dados do servidor no datalake.
+	FonteDatalake = "DATALAKE"
+	// FonteSEI é o valor obtido do andamento do processo no SEI.
+	FonteSEI = "SEI"
+	// FonteManual é o valor corrigido por um usuário.
+	FonteManual = "MANUAL"
+)
+
+// FonteCampoProcesso registra a procedência do valor atual de um dos dados
+// de um processo de aposentadoria, junto com os valores da IA e da fonte
+// externa usados na extração.
+type FonteCampoProcesso struct {
+	ProcessoAposentadoriaID int64            `db:"processo_aposentadoria_id"`
+	Campo                   string           `db:"campo"`
+	Fonte                   string           `db:"fonte"`
+	ValorIA                 sql.Null[string] `db:"valor_ia"`
+	FonteExterna            sql.Null[string] `db:"fonte_externa"`
+	ValorExterno            sql.Null[string] `db:"valor_externo"`
+	AtualizadoEm            time.Time        `db:"atualizado_em"`
+}
+
+// SaveFonteCampoProcesso registra a procedência de um campo, substituindo o
+// registro anterior do mesmo campo.
+func (s *Store) SaveFonteCampoProcesso(ctx context.Context, f *FonteCampoProcesso) error {
+	q := `
+	INSERT INTO fontes_campos_processo (
+		processo_aposentadoria_id, campo, fonte, valor_ia, fonte_externa, valor_externo
+	)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	ON CONFLICT (processo_aposentadoria_id, campo) DO UPDATE SET
+		fonte = EXCLUDED.fonte,
+		valor_ia = EXCLUDED.valor_ia,
+		fonte_externa = EXCLUDED.fonte_externa,
+		valor_externo = EXCLUDED.valor_externo,
+		atualizado_em = CURRENT_TIMESTAMP
+	RETURNING atualizado_em`
+	args := []any{
+		f.ProcessoAposentadoriaID,
+		f.Campo,
+		f.Fonte,
+		f.ValorIA,
+		f.FonteExterna,
+		f.ValorExterno,
+	}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&f.AtualizadoEm)
+}
+
+// UpdateFonteCampoProcesso altera apenas a fonte do valor atual de um campo,
+// mantendo os valores registrados na extração.
+func (s *Store) UpdateFonteCampoProcesso(ctx context.Context, paID int64, campo, fonte string) error {
+	q := `
+	INSERT INTO fontes_campos_processo (processo_aposentadoria_id, campo, fonte)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (processo_aposentadoria_id, campo) DO UPDATE SET
+		fonte = EXCLUDED.fonte,
+		atualizado_em = CURRENT_TIMESTAMP`
+
+	_, err := s.db.Exec(ctx, q, paID, campo, fonte)
+	return err
+}
+
+// ListFontesCamposProcesso retorna a procedência dos campos de um processo de
+// aposentadoria.
+func (s *Store) ListFontesCamposProcesso(ctx context.Context, paID int64) ([]*FonteCampoProcesso, error) {
+	q := `
+	SELECT
+		processo_aposentadoria_id, campo, fonte, valor_ia, fonte_externa,
+		valor_externo, atualizado_em
+	FROM fontes_campos_processo
+	WHERE processo_aposentadoria_id = $1
+	ORDER BY campo`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[FonteCampoProcesso])
+}
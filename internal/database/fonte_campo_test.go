@@ -0,0 +1,51 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestFonteCampoProcesso(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000080/2024-10")
+
+	f := &FonteCampoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		Campo:                   "data_nascimento_requerente",
+		Fonte:                   FonteDatalake,
+		ValorIA:                 sql.Null[string]{V: "1960-01-01", Valid: true},
+		FonteExterna:            sql.Null[string]{V: FonteDatalake, Valid: true},
+		ValorExterno:            sql.Null[string]{V: "1961-01-01", Valid: true},
+	}
+	if err := store.SaveFonteCampoProcesso(t.Context(), f); err != nil {
+		t.Fatal(err)
+	}
+	// Um novo registro do mesmo campo substitui o anterior.
+	if err := store.SaveFonteCampoProcesso(t.Context(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.UpdateFonteCampoProcesso(t.Context(), pa.ID, f.Campo, FonteManual); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateFonteCampoProcesso(t.Context(), pa.ID, "judicial", FonteManual); err != nil {
+		t.Fatal(err)
+	}
+
+	ff, err := store.ListFontesCamposProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ff) != 2 {
+		t.Fatalf("expected 2 fontes, got %d", len(ff))
+	}
+	got := ff[0]
+	if got.Campo != f.Campo || got.Fonte != FonteManual || got.ValorIA != f.ValorIA || got.ValorExterno != f.ValorExterno {
+		t.Fatalf("unexpected fonte: %+v", got)
+	}
+	if ff[1].Campo != "judicial" || ff[1].ValorIA.Valid {
+		t.Fatalf("unexpected fonte: %+v", ff[1])
+	}
+}
@@ -82,3 +82,30 @@ func (s *Store) ListHistoricoStatusProcesso(ctx context.Context, paID int64) ([]
 	}
 	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[HistoricoStatusProcesso])
 }
+
+// ListHistoricoDiligenciaMap retorna, para cada processo de aposentadoria
+// informado, as entradas do histórico que entram ou saem de EM_DILIGENCIA,
+// usadas no cálculo das suspensões de prazo.
+func (s *Store) ListHistoricoDiligenciaMap(ctx context.Context, paIDs []int64) (map[int64][]*HistoricoStatusProcesso, error) {
+	q := `
+	SELECT
+		id, processo_aposentadoria_id, status_anterior, status_novo, usuario_id,
+		observacao, alterado_em
+	FROM historico_status_processo
+	WHERE processo_aposentadoria_id = ANY($1)
+	AND (status_novo = 'EM_DILIGENCIA' OR status_anterior = 'EM_DILIGENCIA')`
+
+	rows, err := s.db.Query(ctx, q, paIDs)
+	if err != nil {
+		return nil, err
+	}
+	list, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[HistoricoStatusProcesso])
+	if err != nil {
+		return nil, err
+	}
+	historicoMap := make(map[int64][]*HistoricoStatusProcesso, len(paIDs))
+	for _, h := range list {
+		historicoMap[h.ProcessoAposentadoriaID] = append(historicoMap[h.ProcessoAposentadoriaID], h)
+	}
+	return historicoMap, nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -56,3 +57,43 @@ func TestHistoricoStatusProcessoLifecycle(t *testing.T) {
 		t.Fatalf("mismatch:\n%s", diff)
 	}
 }
+
+func TestListHistoricoDiligenciaMap(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000092/2024-10")
+	outro := seedProcessoAposentadoria(t, store, "38201-000093/2024-10")
+
+	transicoes := []struct {
+		paID     int64
+		anterior StatusProcesso
+		novo     StatusProcesso
+	}{
+		{pa.ID, StatusProcessoAnalisePendente, StatusProcessoEmAnalise},
+		{pa.ID, StatusProcessoEmAnalise, StatusProcessoEmDiligencia},
+		{pa.ID, StatusProcessoEmDiligencia, StatusProcessoRetornoDiligencia},
+		{outro.ID, StatusProcessoEmAnalise, StatusProcessoEmDiligencia},
+	}
+	for _, tr := range transicoes {
+		h := &HistoricoStatusProcesso{
+			ProcessoAposentadoriaID: tr.paID,
+			StatusAnterior:          sql.Null[StatusProcesso]{V: tr.anterior, Valid: true},
+			StatusNovo:              tr.novo,
+		}
+		if err := store.SaveHistoricoStatusProcesso(t.Context(), h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hm, err := store.ListHistoricoDiligenciaMap(t.Context(), []int64{pa.ID, outro.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hm[pa.ID]) != 2 {
+		t.Fatalf("expected 2 entries for processo %d, got %d", pa.ID, len(hm[pa.ID]))
+	}
+	if len(hm[outro.ID]) != 1 {
+		t.Fatalf("expected 1 entry for processo %d, got %d", outro.ID, len(hm[outro.ID]))
+	}
+}
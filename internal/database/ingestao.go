@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ResultadoIngestao representa o resultado da ingestão de um processo.
+type ResultadoIngestao string
+
+const (
+	// ResultadoIngestaoCriado indica que o processo foi cadastrado.
+	ResultadoIngestaoCriado ResultadoIngestao = "criado"
+	// ResultadoIngestaoIgnorado indica que o processo não foi cadastrado por
+	// causa do modo de simulação ou do limite por execução.
+	ResultadoIngestaoIgnorado ResultadoIngestao = "ignorado"
+	// ResultadoIngestaoFalha indica que houve erro ao cadastrar o processo.
+	ResultadoIngestaoFalha ResultadoIngestao = "falha"
+	// ResultadoIngestaoExistente indica que o processo já estava cadastrado.
+	ResultadoIngestaoExistente ResultadoIngestao = "existente"
+)
+
+// IngestaoProcessos representa uma execução da ingestão automática de
+// processos abertos a partir do DataLake.
+type IngestaoProcessos struct {
+	ID          int64               `db:"id"`
+	Unidade     string              `db:"unidade"`
+	DryRun      bool                `db:"dry_run"`
+	Encontrados int                 `db:"encontrados"`
+	Existentes  int                 `db:"existentes"`
+	Criados     int                 `db:"criados"`
+	Ignorados   int                 `db:"ignorados"`
+	Falhas      int                 `db:"falhas"`
+	IniciadaEm  time.Time           `db:"iniciada_em"`
+	ConcluidaEm sql.Null[time.Time] `db:"concluida_em"`
+	// Erro é o erro que interrompeu a execução, vazio quando concluída.
+	Erro string `db:"erro"`
+}
+
+// ItemIngestaoProcessos representa o resultado da ingestão de um processo
+// durante uma execução.
+type ItemIngestaoProcessos struct {
+	ID         int64             `db:"id"`
+	IngestaoID int64             `db:"ingestao_id"`
+	Numero     string            `db:"numero"`
+	Resultado  ResultadoIngestao `db:"resultado"`
+	Detalhe    string            `db:"detalhe"`
+}
+
+// SaveIngestaoProcessos registra o início de uma nova execução de ingestão.
+func (s *Store) SaveIngestaoProcessos(ctx context.Context, ing *IngestaoProcessos) error {
+	q := `
+	INSERT INTO ingestoes_processos (unidade, dry_run, encontrados, existentes)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, iniciada_em`
+	args := []any{ing.Unidade, ing.DryRun, ing.Encontrados, ing.Existentes}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&ing.ID, &ing.IniciadaEm)
+}
+
+// UpdateIngestaoProcessos atualiza os totalizadores, a data de conclusão e o
+// erro de uma execução de ingestão.
+func (s *Store) UpdateIngestaoProcessos(ctx context.Context, ing *IngestaoProcessos) error {
+	q := `
+	UPDATE ingestoes_processos SET
+		criados = $2,
+		ignorados = $3,
+		falhas = $4,
+		concluida_em = $5,
+		erro = $6
+	WHERE id = $1`
+	args := []any{ing.ID, ing.Criados, ing.Ignorados, ing.Falhas, ing.ConcluidaEm, ing.Erro}
+
+	tag, err := s.db.Exec(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetIngestaoProcessos retorna uma execução de ingestão pelo ID.
+func (s *Store) GetIngestaoProcessos(ctx context.Context, id int64) (*IngestaoProcessos, error) {
+	q := `
+	SELECT
+		id, unidade, dry_run, encontrados, existentes, criados,
+		ignorados, falhas, iniciada_em, concluida_em, erro
+	FROM ingestoes_processos
+	WHERE id = $1`
+
+	rows, err := s.db.Query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	ing, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[IngestaoProcessos])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return ing, nil
+}
+
+type ListIngestoesProcessosParams struct {
+	Limit  int
+	Offset int
+}
+
+// ListIngestoesProcessos retorna uma lista paginada das execuções de
+// ingestão, da mais recente para a mais antiga.
+func (s *Store) ListIngestoesProcessos(ctx context.Context, params ListIngestoesProcessosParams) ([]*IngestaoProcessos, int, error) {
+	q := `
+	SELECT
+		id, unidade, dry_run, encontrados, existentes, criados,
+		ignorados, falhas, iniciada_em, concluida_em, erro,
+		COUNT(*) OVER()
+	FROM ingestoes_processos
+	ORDER BY id DESC
+	LIMIT $1 OFFSET $2`
+
+	rows, err := s.db.Query(ctx, q, params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	totalCount := 0
+	ii := make([]*IngestaoProcessos, 0)
+	for rows.Next() {
+		var ing IngestaoProcessos
+		err := rows.Scan(
+			&ing.ID, &ing.Unidade, &ing.DryRun, &ing.Encontrados, &ing.Existentes,
+			&ing.Criados, &ing.Ignorados, &ing.Falhas, &ing.IniciadaEm,
+			&ing.ConcluidaEm, &ing.Erro, &totalCount,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		ii = append(ii, &ing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return ii, totalCount, nil
+}
+
+// SaveItemIngestaoProcessos registra o resultado da ingestão de um processo.
+func (s *Store) SaveItemIngestaoProcessos(ctx context.Context, item *ItemIngestaoProcessos) error {
+	q := `
+	INSERT INTO itens_ingestao_processos (ingestao_id, numero, resultado, detalhe)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id`
+	args := []any{item.IngestaoID, item.Numero, item.Resultado, item.Detalhe}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&item.ID)
+}
+
+// ListItensIngestaoProcessos retorna os itens registrados em uma execução de
+// ingestão, na ordem em que foram processados.
+func (s *Store) ListItensIngestaoProcessos(ctx context.Context, ingestaoID int64) ([]*ItemIngestaoProcessos, error) {
+	q := `
+	SELECT id, ingestao_id, numero, resultado, detalhe
+	FROM itens_ingestao_processos
+	WHERE ingestao_id = $1
+	ORDER BY id`
+
+	rows, err := s.db.Query(ctx, q, ingestaoID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ItemIngestaoProcessos])
+}
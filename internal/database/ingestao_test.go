@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIngestaoProcessosLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	ing := &IngestaoProcessos{
+		Unidade:     "SEPLAG/DCCTA",
+		Encontrados: 3,
+		Existentes:  1,
+	}
+	if err := store.SaveIngestaoProcessos(t.Context(), ing); err != nil {
+		t.Fatal(err)
+	}
+
+	itens := []*ItemIngestaoProcessos{
+		{IngestaoID: ing.ID, Numero: "1", Resultado: ResultadoIngestaoCriado},
+		{IngestaoID: ing.ID, Numero: "2", Resultado: ResultadoIngestaoFalha, Detalhe: "erro"},
+	}
+	for _, item := range itens {
+		if err := store.SaveItemIngestaoProcessos(t.Context(), item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ing.Criados = 1
+	ing.Falhas = 1
+	ing.ConcluidaEm = sql.Null[time.Time]{V: time.Now().UTC().Truncate(time.Microsecond), Valid: true}
+	if err := store.UpdateIngestaoProcessos(t.Context(), ing); err != nil {
+		t.Fatal(err)
+	}
+
+	ing2, err := store.GetIngestaoProcessos(t.Context(), ing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(ing, ing2); diff != "" {
+		t.Fatalf("mismatch:\n%s", diff)
+	}
+
+	itens2, err := store.ListItensIngestaoProcessos(t.Context(), ing.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(itens, itens2); diff != "" {
+		t.Fatalf("mismatch:\n%s", diff)
+	}
+
+	ii, total, err := store.ListIngestoesProcessos(t.Context(), ListIngestoesProcessosParams{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(ii) != 1 {
+		t.Fatalf("expected 1 ingestão, got %d (total %d)", len(ii), total)
+	}
+}
+
+func TestListNumerosProcessosExistentes(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	if err := store.SaveProcesso(t.Context(), &Processo{Numero: "existente"}); err != nil {
+		t.Fatal(err)
+	}
+
+	existentes, err := store.ListNumerosProcessosExistentes(t.Context(), []string{"existente", "novo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]bool{"existente": true}, existentes); diff != "" {
+		t.Fatalf("mismatch:\n%s", diff)
+	}
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DiligenciaAguardandoResposta é uma solicitação de diligência enviada cujo
+// processo ainda está EM_DILIGENCIA, com os dados necessários para o envio dos
+// lembretes de prazo à unidade de origem.
+type DiligenciaAguardandoResposta struct {
+	SolicitacaoID           int64
+	ProcessoAposentadoriaID int64
+	NumeroProcesso          string
+	UnidadeSigla            string
+	PrazoResposta           time.Time
+	VencimentoNotificadoEm  sql.Null[time.Time]
+	// LembretesEnviados são os dias, relativos ao prazo de resposta, dos
+	// lembretes já enviados para a solicitação.
+	LembretesEnviados []int
+}
+
+// ListDiligenciasAguardandoResposta retorna a solicitação enviada mais recente
+// de cada processo EM_DILIGENCIA que possua prazo de resposta, ordenadas pelo
+// prazo.
+func (s *Store) ListDiligenciasAguardandoResposta(ctx context.Context) ([]*DiligenciaAguardandoResposta, error) {
+	q := `
+	WITH ultimas AS (
+		SELECT DISTINCT ON (sd.processo_aposentadoria_id)
+			sd.id, sd.processo_aposentadoria_id, sd.prazo_resposta, sd.vencimento_notificado_em
+		FROM solicitacoes_diligencia sd
+		WHERE sd.status = 'enviada'
+		ORDER BY sd.processo_aposentadoria_id, sd.enviada_em DESC, sd.id DESC
+	)
+	SELECT
+		sd.id, sd.processo_aposentadoria_id, p.numero, p.sei_unidade_sigla,
+		sd.prazo_resposta, sd.vencimento_notificado_em,
+		COALESCE((
+			SELECT array_agg(l.dias ORDER BY l.dias)
+			FROM lembretes_diligencia l
+			WHERE l.solicitacao_diligencia_id = sd.id
+		), '{}')
+	FROM ultimas sd
+	INNER JOIN processos_aposentadoria pa ON pa.id = sd.processo_aposentadoria_id
+	INNER JOIN processos p ON p.id = pa.processo_id
+	WHERE pa.status = 'EM_DILIGENCIA'
+	  AND sd.prazo_resposta IS NOT NULL
+	ORDER BY sd.prazo_resposta, sd.id`
+
+	rows, err := s.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dd := make([]*DiligenciaAguardandoResposta, 0)
+	for rows.Next() {
+		var d DiligenciaAguardandoResposta
+		err := rows.Scan(
+			&d.SolicitacaoID, &d.ProcessoAposentadoriaID, &d.NumeroProcesso, &d.UnidadeSigla,
+			&d.PrazoResposta, &d.VencimentoNotificadoEm, &d.LembretesEnviados,
+		)
+		if err != nil {
+			return nil, err
+		}
+		dd = append(dd, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return dd, nil
+}
+
+// SaveLembreteDiligencia registra o envio do lembrete de uma solicitação de
+// diligência para o dia informado, relativo ao prazo de resposta. Retorna false
+// se o lembrete já havia sido registrado.
+func (s *Store) SaveLembreteDiligencia(ctx context.Context, solicitacaoID int64, dias int) (bool, error) {
+	q := `
+	INSERT INTO lembretes_diligencia (solicitacao_diligencia_id, dias)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING`
+
+	tag, err := s.db.Exec(ctx, q, solicitacaoID, dias)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetVencimentoNotificadoSolicitacaoDiligencia registra que o vencimento do
+// prazo de resposta de uma solicitação de diligência já foi sinalizado.
+func (s *Store) SetVencimentoNotificadoSolicitacaoDiligencia(ctx context.Context, id int64) error {
+	q := `
+	UPDATE solicitacoes_diligencia SET
+		vencimento_notificado_em = CURRENT_TIMESTAMP
+	WHERE id = $1`
+
+	tag, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
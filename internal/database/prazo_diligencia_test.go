@@ -0,0 +1,145 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiligenciasAguardandoResposta(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000020/2024-10")
+	_, analista := seedAnalista(t, store)
+
+	prazo := time.Date(2026, time.June, 10, 0, 0, 0, 0, time.UTC)
+	sd := &SolicitacaoDiligencia{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              analista.UsuarioID,
+		Status:                  StatusSolicitacaoEnviada,
+		EnviadaEm:               sql.Null[time.Time]{V: prazo.AddDate(0, 0, -30), Valid: true},
+	}
+	if err := store.SaveSolicitacaoDiligencia(t.Context(), sd); err != nil {
+		t.Fatal(err)
+	}
+	sd.PrazoResposta = sql.Null[time.Time]{V: prazo, Valid: true}
+	if err := store.UpdateSolicitacaoDiligencia(t.Context(), sd); err != nil {
+		t.Fatal(err)
+	}
+
+	// Processos fora de EM_DILIGENCIA não aguardam resposta.
+	dd, err := store.ListDiligenciasAguardandoResposta(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dd) != 0 {
+		t.Fatalf("expected no diligências, got %d", len(dd))
+	}
+
+	pa.Status = StatusProcessoEmDiligencia
+	if err := store.UpdateProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dias := range []int{-1, -7} {
+		ok, err := store.SaveLembreteDiligencia(t.Context(), sd.ID, dias)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("expected lembrete %d to be saved", dias)
+		}
+	}
+	ok, err := store.SaveLembreteDiligencia(t.Context(), sd.ID, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected duplicate lembrete to be ignored")
+	}
+
+	dd, err = store.ListDiligenciasAguardandoResposta(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dd) != 1 {
+		t.Fatalf("expected 1 diligência, got %d", len(dd))
+	}
+	got := dd[0]
+	if got.SolicitacaoID != sd.ID || got.ProcessoAposentadoriaID != pa.ID {
+		t.Fatalf("unexpected diligência: %+v", got)
+	}
+	if got.NumeroProcesso != "38201-000020/2024-10" {
+		t.Fatalf("expected numero, got %q", got.NumeroProcesso)
+	}
+	if !got.PrazoResposta.Equal(prazo) {
+		t.Fatalf("expected prazo %v, got %v", prazo, got.PrazoResposta)
+	}
+	if diff := cmp.Diff([]int{-7, -1}, got.LembretesEnviados); diff != "" {
+		t.Fatalf("lembretes mismatch:\n%s", diff)
+	}
+	if got.VencimentoNotificadoEm.Valid {
+		t.Fatal("expected vencimento not notified")
+	}
+
+	if err := store.SetVencimentoNotificadoSolicitacaoDiligencia(t.Context(), sd.ID); err != nil {
+		t.Fatal(err)
+	}
+	read, err := store.GetSolicitacaoDiligencia(t.Context(), sd.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !read.VencimentoNotificadoEm.Valid {
+		t.Fatal("expected vencimento to be notified")
+	}
+}
+
+func TestListProcessoAposentadoria_DiligenciaVencida(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	_, analista := seedAnalista(t, store)
+
+	seed := func(numero string, prazo time.Time) *ProcessoAposentadoria {
+		pa := seedProcessoAposentadoria(t, store, numero)
+		pa.Status = StatusProcessoEmDiligencia
+		if err := store.UpdateProcessoAposentadoria(t.Context(), pa); err != nil {
+			t.Fatal(err)
+		}
+
+		sd := &SolicitacaoDiligencia{
+			ProcessoAposentadoriaID: pa.ID,
+			AnalistaID:              analista.UsuarioID,
+			Status:                  StatusSolicitacaoEnviada,
+			EnviadaEm:               sql.Null[time.Time]{V: prazo.AddDate(0, 0, -30), Valid: true},
+		}
+		if err := store.SaveSolicitacaoDiligencia(t.Context(), sd); err != nil {
+			t.Fatal(err)
+		}
+		sd.PrazoResposta = sql.Null[time.Time]{V: prazo, Valid: true}
+		if err := store.UpdateSolicitacaoDiligencia(t.Context(), sd); err != nil {
+			t.Fatal(err)
+		}
+		return pa
+	}
+
+	vencido := seed("38201-000021/2024-10", time.Now().AddDate(0, 0, -3))
+	seed("38201-000022/2024-10", time.Now().AddDate(0, 0, 3))
+
+	paa, total, err := store.ListProcessoAposentadoria(t.Context(), ListProcessoAposentadoriaParams{
+		DiligenciaVencida: true,
+		Limit:             10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(paa) != 1 {
+		t.Fatalf("expected 1 processo, got %d", total)
+	}
+	if paa[0].ID != vencido.ID {
+		t.Fatalf("expected processo %d, got %d", vencido.ID, paa[0].ID)
+	}
+}
@@ -156,6 +156,26 @@ func (s *Store) GetProcessosMap(ctx context.Context, ids []uuid.UUID) (map[uuid.
 	return processoMap, nil
 }
 
+// ListNumerosProcessosExistentes retorna, dentre os números informados,
+// aqueles que já possuem processo cadastrado.
+func (s *Store) ListNumerosProcessosExistentes(ctx context.Context, numeros []string) (map[string]bool, error) {
+	q := `SELECT numero FROM processos WHERE numero = ANY($1)`
+
+	rows, err := s.db.Query(ctx, q, numeros)
+	if err != nil {
+		return nil, err
+	}
+	list, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	existentes := make(map[string]bool, len(list))
+	for _, numero := range list {
+		existentes[numero] = true
+	}
+	return existentes, nil
+}
+
 func (s *Store) GetProcesso(ctx context.Context, id uuid.UUID) (*Processo, error) {
 	q := `
 	SELECT 
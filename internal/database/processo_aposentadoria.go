@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -97,7 +98,27 @@ func (s *Store) GetProcessoAposentadoria(ctx context.Context, id int64) (*Proces
 	FROM processos_aposentadoria
 	WHERE id = $1`
 
-	rows, err := s.db.Query(ctx, q, id)
+	return s.getProcessoAposentadoria(ctx, q, id)
+}
+
+// GetProcessoAposentadoriaForUpdate retorna um processo de aposentadoria pelo
+// ID, bloqueando o registro até o fim da transação. Retorna [ErrNotFound]
+// caso não exista.
+func (s *Store) GetProcessoAposentadoriaForUpdate(ctx context.Context, id int64) (*ProcessoAposentadoria, error) {
+	q := `
+	SELECT
+		id, processo_id, data_requerimento, cpf_requerente, data_nascimento_requerente,
+		invalidez, judicial, prioridade, score, status,
+		analista_id, ultimo_analista_id, alertas, criado_em, atualizado_em
+	FROM processos_aposentadoria
+	WHERE id = $1
+	FOR UPDATE`
+
+	return s.getProcessoAposentadoria(ctx, q, id)
+}
+
+func (s *Store) getProcessoAposentadoria(ctx context.Context, q string, args ...any) (*ProcessoAposentadoria, error) {
+	rows, err := s.db.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -156,9 +177,97 @@ func (s *Store) UpdateProcessoAposentadoria(ctx context.Context, pa *ProcessoApo
 	}
 
 	err := s.db.QueryRow(ctx, q, args...).Scan(&pa.AtualizadoEm)
+	if err != nil {
+		if strings.Contains(err.Error(), "processos_limite_analista") {
+			return ErrLimiteProcessos
+		}
+		return err
+	}
+	return nil
+}
+
+// GetProcessoAposentadoriaByProcessoID retorna o processo de aposentadoria de
+// um processo SEI. Retorna [ErrNotFound] caso não seja encontrado.
+func (s *Store) GetProcessoAposentadoriaByProcessoID(ctx context.Context, processoID uuid.UUID) (*ProcessoAposentadoria, error) {
+	q := `
+	SELECT
+		id, processo_id, data_requerimento, cpf_requerente, data_nascimento_requerente,
+		invalidez, judicial, prioridade, score, status,
+		analista_id, ultimo_analista_id, alertas, criado_em, atualizado_em
+	FROM processos_aposentadoria
+	WHERE processo_id = $1`
+
+	rows, err := s.db.Query(ctx, q, processoID)
+	if err != nil {
+		return nil, err
+	}
+	pa, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[ProcessoAposentadoria])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return pa, nil
+}
+
+// UpdateDadosProcessoAposentadoria atualiza os dados do requerente extraídos
+// do processo e os alertas da extração.
+func (s *Store) UpdateDadosProcessoAposentadoria(ctx context.Context, pa *ProcessoAposentadoria) error {
+	q := `
+	UPDATE processos_aposentadoria SET
+		cpf_requerente = $2,
+		data_nascimento_requerente = $3,
+		data_requerimento = $4,
+		invalidez = $5,
+		judicial = $6,
+		alertas = $7,
+		atualizado_em = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING data_nascimento_requerente, data_requerimento, atualizado_em`
+	if pa.Alertas == nil {
+		pa.Alertas = []string{}
+	}
+	args := []any{
+		pa.ID,
+		pa.CPFRequerente,
+		pa.DataNascimentoRequerente,
+		pa.DataRequerimento,
+		pa.Invalidez,
+		pa.Judicial,
+		pa.Alertas,
+	}
+
+	err := s.db.QueryRow(ctx, q, args...).Scan(
+		&pa.DataNascimentoRequerente,
+		&pa.DataRequerimento,
+		&pa.AtualizadoEm,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AddAlertaProcessoAposentadoria adiciona um alerta ao final da lista de
+// alertas de um processo de aposentadoria.
+func (s *Store) AddAlertaProcessoAposentadoria(ctx context.Context, paID int64, alerta string) error {
+	q := `
+	UPDATE processos_aposentadoria SET
+		alertas = array_append(alertas, $2),
+		atualizado_em = CURRENT_TIMESTAMP
+	WHERE id = $1`
+
+	tag, err := s.db.Exec(ctx, q, paID, alerta)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
 
@@ -167,12 +276,17 @@ type ListProcessoAposentadoriaParams struct {
 	Status           string
 	UltimoAnalistaID sql.Null[int64]
 	StatusIn         []StatusProcesso
-	Limit            int
-	Offset           int
+	// DiligenciaVencida restringe a lista aos processos EM_DILIGENCIA cuja
+	// última diligência enviada está com o prazo de resposta vencido na data
+	// atual de Brasília (veja [DataBrasilia]).
+	DiligenciaVencida bool
+	Limit             int
+	Offset            int
 }
 
 // ListProcessoAposentadoria retorna uma lista paginada de processos de aposentadoria.
-// Permite filtrar por Numero (do processo), Status, UltimoAnalistaID e StatusIn.
+// Permite filtrar por Numero (do processo), Status, UltimoAnalistaID, StatusIn e
+// DiligenciaVencida.
 func (s *Store) ListProcessoAposentadoria(ctx context.Context, params ListProcessoAposentadoriaParams) ([]*ProcessoAposentadoria, int, error) {
 	statusIn := make([]string, 0, len(params.StatusIn))
 	for _, s := range params.StatusIn {
@@ -197,9 +311,17 @@ func (s *Store) ListProcessoAposentadoria(ctx context.Context, params ListProces
 	  AND (p.numero LIKE '%' || $2 || '%' OR $2 = '')
 	  AND ($5::bigint IS NULL OR pa.ultimo_analista_id = $5)
 	  AND (cardinality($6::text[]) = 0 OR pa.status::text = ANY($6))
+	  AND (NOT $7::boolean OR (pa.status = 'EM_DILIGENCIA' AND (
+		SELECT sd.prazo_resposta
+		FROM solicitacoes_diligencia sd
+		WHERE sd.processo_aposentadoria_id = pa.id
+		  AND sd.status = 'enviada'
+		ORDER BY sd.enviada_em DESC, sd.id DESC
+		LIMIT 1
+	  ) < $8::date))
 	ORDER BY pa.criado_em DESC
 	LIMIT $3 OFFSET $4`
-	args := []any{params.Status, params.Numero, params.Limit, params.Offset, ultimoAnalistaID, statusIn}
+	args := []any{params.Status, params.Numero, params.Limit, params.Offset, ultimoAnalistaID, statusIn, params.DiligenciaVencida, DataBrasilia(time.Now())}
 
 	rows, err := s.db.Query(ctx, q, args...)
 	if err != nil {
@@ -230,7 +352,12 @@ func (s *Store) ListProcessoAposentadoria(ctx context.Context, params ListProces
 	return paa, totalCount, nil
 }
 
-func (s *Store) GetProcessoPrioriatario(ctx context.Context, analistaID int64) (*ProcessoAposentadoria, error) {
+// GetProcessoPrioriatario retorna o próximo processo a ser atribuído ao
+// analista, entre os processos para os quais ele é elegível. Processos fixados
+// para o analista têm precedência, e processos fixados para outros analistas
+// são ignorados. Processos aguardando desde antes de limite podem ser
+// atribuídos a analistas SENIOR sem as competências exigidas.
+func (s *Store) GetProcessoPrioriatario(ctx context.Context, analistaID int64, limite time.Time) (*ProcessoAposentadoria, error) {
 	q := `
 	SELECT 
 		pa.id, pa.processo_id, pa.data_requerimento, pa.cpf_requerente,
@@ -238,9 +365,15 @@ func (s *Store) GetProcessoPrioriatario(ctx context.Context, analistaID int64) (
 		pa.score, pa.status, pa.analista_id, pa.ultimo_analista_id,
 		pa.alertas, pa.criado_em, pa.atualizado_em
 	FROM processos_aposentadoria pa
+	JOIN processos p ON p.id = pa.processo_id
+	JOIN analistas a ON a.usuario_id = $1
+	` + joinFixacaoAtiva + `
 	WHERE pa.status IN ('RETORNO_DILIGENCIA', 'ANALISE_PENDENTE')
+	  AND (pf.analista_id IS NULL OR pf.analista_id = $1)
+	  AND ` + condicaoElegivel("$2") + `
 	ORDER BY
 		CASE
+			WHEN pf.analista_id = $1 THEN 0
 			WHEN pa.status = 'RETORNO_DILIGENCIA' AND pa.ultimo_analista_id = $1 THEN 1
 			WHEN pa.status = 'RETORNO_DILIGENCIA' AND pa.ultimo_analista_id IS NULL THEN 2
 			WHEN pa.status = 'ANALISE_PENDENTE' THEN 3
@@ -249,10 +382,10 @@ func (s *Store) GetProcessoPrioriatario(ctx context.Context, analistaID int64) (
 		pa.score DESC,
 		pa.data_requerimento ASC
 	LIMIT 1
-	FOR UPDATE SKIP LOCKED`
+	FOR UPDATE OF pa SKIP LOCKED`
 
 	var pa ProcessoAposentadoria
-	err := s.db.QueryRow(ctx, q, analistaID).Scan(
+	err := s.db.QueryRow(ctx, q, analistaID, limite).Scan(
 		&pa.ID, &pa.ProcessoID, &pa.DataRequerimento, &pa.CPFRequerente,
 		&pa.DataNascimentoRequerente, &pa.Invalidez, &pa.Judicial, &pa.Prioridade,
 		&pa.Score, &pa.Status, &pa.AnalistaID, &pa.UltimoAnalistaID,
@@ -270,7 +403,9 @@ func (s *Store) GetProcessoPrioriatario(ctx context.Context, analistaID int64) (
 	return &pa, nil
 }
 
-func (s *Store) GetProcessoAtribuido(ctx context.Context, analistaID int64) (*ProcessoAposentadoria, error) {
+// ListProcessosAtribuidos retorna os processos EM_ANALISE atribuídos a um
+// analista, começando pelo requerimento mais antigo.
+func (s *Store) ListProcessosAtribuidos(ctx context.Context, analistaID int64) ([]*ProcessoAposentadoria, error) {
 	q := `
 	SELECT
 		pa.id, pa.processo_id, pa.data_requerimento, pa.cpf_requerente,
@@ -279,25 +414,28 @@ func (s *Store) GetProcessoAtribuido(ctx context.Context, analistaID int64) (*Pr
 		pa.alertas, pa.criado_em, pa.atualizado_em
 	FROM processos_aposentadoria pa
 	WHERE pa.status = 'EM_ANALISE'
-	AND analista_id = $1`
+	AND analista_id = $1
+	ORDER BY pa.data_requerimento, pa.id`
 
-	var pa ProcessoAposentadoria
-	err := s.db.QueryRow(ctx, q, analistaID).Scan(
-		&pa.ID, &pa.ProcessoID, &pa.DataRequerimento, &pa.CPFRequerente,
-		&pa.DataNascimentoRequerente, &pa.Invalidez, &pa.Judicial, &pa.Prioridade,
-		&pa.Score, &pa.Status, &pa.AnalistaID, &pa.UltimoAnalistaID,
-		&pa.Alertas, &pa.CriadoEm, &pa.AtualizadoEm,
-	)
+	rows, err := s.db.Query(ctx, q, analistaID)
 	if err != nil {
-		switch {
-		case errors.Is(err, pgx.ErrNoRows):
-			return nil, ErrNotFound
-		default:
-			return nil, err
-		}
+		return nil, err
 	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ProcessoAposentadoria])
+}
 
-	return &pa, nil
+// GetProcessoAtribuido retorna o processo EM_ANALISE atribuído a um analista
+// com o requerimento mais antigo. Retorna [ErrNotFound] se o analista não
+// possuir processos em análise.
+func (s *Store) GetProcessoAtribuido(ctx context.Context, analistaID int64) (*ProcessoAposentadoria, error) {
+	paa, err := s.ListProcessosAtribuidos(ctx, analistaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(paa) == 0 {
+		return nil, ErrNotFound
+	}
+	return paa[0], nil
 }
 
 // ListAllProcessoAposentadoria retorna todos os processos de aposentadoria.
@@ -337,6 +475,44 @@ func (s *Store) ListAllProcessoAposentadoria(ctx context.Context) ([]*ProcessoAp
 	return paa, nil
 }
 
+// ListProcessoAposentadoriaByStatus retorna todos os processos de aposentadoria
+// que se encontram no status informado.
+func (s *Store) ListProcessoAposentadoriaByStatus(ctx context.Context, status StatusProcesso) ([]*ProcessoAposentadoria, error) {
+	q := `
+	SELECT
+		id, processo_id, data_requerimento, cpf_requerente, data_nascimento_requerente,
+		invalidez, judicial, prioridade, score, status,
+		analista_id, ultimo_analista_id, alertas, criado_em, atualizado_em
+	FROM processos_aposentadoria
+	WHERE status = $1
+	ORDER BY id`
+
+	rows, err := s.db.Query(ctx, q, status)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ProcessoAposentadoria])
+}
+
+// ListProcessoAposentadoriaAbertos retorna todos os processos de aposentadoria
+// que ainda não foram concluídos.
+func (s *Store) ListProcessoAposentadoriaAbertos(ctx context.Context) ([]*ProcessoAposentadoria, error) {
+	q := `
+	SELECT
+		id, processo_id, data_requerimento, cpf_requerente, data_nascimento_requerente,
+		invalidez, judicial, prioridade, score, status,
+		analista_id, ultimo_analista_id, alertas, criado_em, atualizado_em
+	FROM processos_aposentadoria
+	WHERE status <> 'CONCLUIDO'
+	ORDER BY id`
+
+	rows, err := s.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ProcessoAposentadoria])
+}
+
 // GetNumeroProcessoAposentadoria retorna no número do processo SEI para um
 // determinado processo de aposentadoria
 func (s *Store) GetNumeroProcessoAposentadoria(ctx context.Context, paID int64) (string, error) {
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -63,3 +65,83 @@ func TestProcessoAposentadoriaLifecycle(t *testing.T) {
 		t.Fatalf("mismatch:\n%s", diff)
 	}
 }
+
+func TestListProcessoAposentadoriaByStatus(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	statuses := []StatusProcesso{
+		StatusProcessoEmDiligencia,
+		StatusProcessoAnalisePendente,
+		StatusProcessoEmDiligencia,
+	}
+	for i, status := range statuses {
+		p := &Processo{Numero: fmt.Sprintf("numero-%d", i)}
+		if err := store.SaveProcesso(t.Context(), p); err != nil {
+			t.Fatal(err)
+		}
+
+		pa := &ProcessoAposentadoria{
+			ProcessoID:               p.ID,
+			DataRequerimento:         time.Now(),
+			Status:                   status,
+			DataNascimentoRequerente: time.Date(1950, time.April, 2, 0, 0, 0, 0, time.Local),
+		}
+		if err := store.SaveProcessoAposentadoria(t.Context(), pa); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paa, err := store.ListProcessoAposentadoriaByStatus(t.Context(), StatusProcessoEmDiligencia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paa) != 2 {
+		t.Fatalf("expected 2 processos, got %d", len(paa))
+	}
+	for _, pa := range paa {
+		if pa.Status != StatusProcessoEmDiligencia {
+			t.Fatalf("unexpected status: %q", pa.Status)
+		}
+	}
+}
+
+func TestAddAlertaProcessoAposentadoria(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	p := &Processo{Numero: "alerta-1"}
+	if err := store.SaveProcesso(t.Context(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	pa := &ProcessoAposentadoria{
+		ProcessoID:               p.ID,
+		DataRequerimento:         time.Now(),
+		Status:                   StatusProcessoEmAnalise,
+		DataNascimentoRequerente: time.Date(1950, time.April, 2, 0, 0, 0, 0, time.Local),
+		Alertas:                  []string{"primeiro"},
+	}
+	if err := store.SaveProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.AddAlertaProcessoAposentadoria(t.Context(), pa.ID, "segundo"); err != nil {
+		t.Fatal(err)
+	}
+
+	pa2, err := store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"primeiro", "segundo"}, pa2.Alertas); diff != "" {
+		t.Fatalf("mismatch:\n%s", diff)
+	}
+
+	err = store.AddAlertaProcessoAposentadoria(t.Context(), pa.ID+1000, "inexistente")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
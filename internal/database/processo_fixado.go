@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ProcessoFixado é a indicação, feita por um gestor, de que um processo de
+// aposentadoria deve ser o próximo atribuído a um analista específico.
+type ProcessoFixado struct {
+	ProcessoAposentadoriaID int64           `db:"processo_aposentadoria_id"`
+	AnalistaID              int64           `db:"analista_id"`
+	FixadoPor               sql.Null[int64] `db:"fixado_por"`
+	Motivo                  string          `db:"motivo"`
+	CriadoEm                time.Time       `db:"criado_em"`
+}
+
+// SaveProcessoFixado fixa um processo de aposentadoria para um analista,
+// substituindo a fixação anterior do processo, se houver.
+func (s *Store) SaveProcessoFixado(ctx context.Context, pf *ProcessoFixado) error {
+	q := `
+	INSERT INTO processos_fixados (processo_aposentadoria_id, analista_id, fixado_por, motivo)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (processo_aposentadoria_id) DO UPDATE SET
+		analista_id = EXCLUDED.analista_id,
+		fixado_por = EXCLUDED.fixado_por,
+		motivo = EXCLUDED.motivo,
+		criado_em = CURRENT_TIMESTAMP
+	RETURNING criado_em`
+	args := []any{pf.ProcessoAposentadoriaID, pf.AnalistaID, pf.FixadoPor, pf.Motivo}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&pf.CriadoEm)
+}
+
+// GetProcessoFixado retorna a fixação de um processo de aposentadoria. Retorna
+// [ErrNotFound] se o processo não estiver fixado.
+func (s *Store) GetProcessoFixado(ctx context.Context, paID int64) (*ProcessoFixado, error) {
+	q := `
+	SELECT processo_aposentadoria_id, analista_id, fixado_por, motivo, criado_em
+	FROM processos_fixados
+	WHERE processo_aposentadoria_id = $1`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	pf, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[ProcessoFixado])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return pf, nil
+}
+
+// DeleteProcessoFixado remove a fixação de um processo de aposentadoria.
+// Retorna false se o processo não estava fixado.
+func (s *Store) DeleteProcessoFixado(ctx context.Context, paID int64) (bool, error) {
+	q := `DELETE FROM processos_fixados WHERE processo_aposentadoria_id = $1`
+
+	tag, err := s.db.Exec(ctx, q, paID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProcessoFixadoLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "38201-000030/2024-10")
+	gestor := seedUsuario(t, store)
+	_, a1 := seedAnalista(t, store)
+	_, a2 := seedAnalista(t, store)
+
+	_, err := store.GetProcessoFixado(t.Context(), pa.ID)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	pf := &ProcessoFixado{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              a1.UsuarioID,
+		FixadoPor:               sql.Null[int64]{V: gestor.ID, Valid: true},
+		Motivo:                  "Analista conhece o caso",
+	}
+	if err := store.SaveProcessoFixado(t.Context(), pf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fixar novamente substitui o analista.
+	pf.AnalistaID = a2.UsuarioID
+	if err := store.SaveProcessoFixado(t.Context(), pf); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := store.GetProcessoFixado(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.AnalistaID != a2.UsuarioID {
+		t.Fatalf("expected analista %d, got %d", a2.UsuarioID, read.AnalistaID)
+	}
+	if read.Motivo != pf.Motivo || read.FixadoPor != pf.FixadoPor {
+		t.Fatalf("unexpected fixação: %+v", read)
+	}
+
+	ok, err := store.DeleteProcessoFixado(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected fixação to be deleted")
+	}
+	ok, err = store.DeleteProcessoFixado(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected no fixação to delete")
+	}
+}
+
+func TestProcessoFixado_Atribuicao(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	a1 := seedAnalistaDisponivel(t, store, NivelPleno)
+	a2 := seedAnalistaDisponivel(t, store, NivelPleno)
+
+	comum := seedProcessoAposentadoria(t, store, "38201-000031/2024-10")
+	comum.Score = 100
+	if err := store.UpdateProcessoAposentadoria(t.Context(), comum); err != nil {
+		t.Fatal(err)
+	}
+	fixado := seedProcessoAposentadoria(t, store, "38201-000032/2024-10")
+
+	err := store.SaveProcessoFixado(t.Context(), &ProcessoFixado{
+		ProcessoAposentadoriaID: fixado.ID,
+		AnalistaID:              a2.UsuarioID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O analista com processo fixado tem precedência.
+	analistaID, err := store.GetAnalistaDisponivel(t.Context(), time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analistaID != a2.UsuarioID {
+		t.Fatalf("expected analista %d, got %d", a2.UsuarioID, analistaID)
+	}
+
+	// O processo fixado é o próximo do analista, mesmo com score menor.
+	pa, err := store.GetProcessoPrioriatario(t.Context(), a2.UsuarioID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pa.ID != fixado.ID {
+		t.Fatalf("expected processo %d, got %d", fixado.ID, pa.ID)
+	}
+
+	// Outros analistas não recebem o processo fixado.
+	comum.Status = StatusProcessoEmAnalise
+	comum.AnalistaID = sql.Null[int64]{V: a2.UsuarioID, Valid: true}
+	if err := store.UpdateProcessoAposentadoria(t.Context(), comum); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetProcessoPrioriatario(t.Context(), a1.UsuarioID, time.Now().Add(-24*time.Hour))
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestProcessoFixado_AnalistaAfastado(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	a1 := seedAnalistaDisponivel(t, store, NivelPleno)
+	a2 := seedAnalistaDisponivel(t, store, NivelPleno)
+
+	fixado := seedProcessoAposentadoria(t, store, "38201-000033/2024-10")
+	err := store.SaveProcessoFixado(t.Context(), &ProcessoFixado{
+		ProcessoAposentadoriaID: fixado.ID,
+		AnalistaID:              a2.UsuarioID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a2.Afastado = true
+	if err := store.UpdateAnalista(t.Context(), a2); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fixação para o analista afastado não impede a atribuição a outro
+	// analista.
+	analistaID, err := store.GetAnalistaDisponivel(t.Context(), time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analistaID != a1.UsuarioID {
+		t.Fatalf("expected analista %d, got %d", a1.UsuarioID, analistaID)
+	}
+	pa, err := store.GetProcessoPrioriatario(t.Context(), a1.UsuarioID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pa.ID != fixado.ID {
+		t.Fatalf("expected processo %d, got %d", fixado.ID, pa.ID)
+	}
+}
@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// VersaoRegrasScore representa uma versão do conjunto de regras de score.
+// As regras de uma versão não são alteradas após a criação; mudanças geram
+// uma nova versão.
+type VersaoRegrasScore struct {
+	ID        int64               `db:"id"`
+	Versao    int                 `db:"versao"`
+	Descricao string              `db:"descricao"`
+	Regras    json.RawMessage     `db:"regras"`
+	Ativa     bool                `db:"ativa"`
+	CriadoPor sql.Null[int64]     `db:"criado_por"`
+	CriadoEm  time.Time           `db:"criado_em"`
+	AtivadaEm sql.Null[time.Time] `db:"ativada_em"`
+}
+
+// SaveVersaoRegrasScore cria uma nova versão de regras de score, inativa, com
+// o próximo número de versão disponível.
+func (s *Store) SaveVersaoRegrasScore(ctx context.Context, v *VersaoRegrasScore) error {
+	q := `
+	INSERT INTO versoes_regras_score (versao, descricao, regras, criado_por)
+	SELECT COALESCE(MAX(versao), 0) + 1, $1, $2, $3
+	FROM versoes_regras_score
+	RETURNING id, versao, ativa, criado_em, ativada_em`
+	args := []any{v.Descricao, v.Regras, v.CriadoPor}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&v.ID, &v.Versao, &v.Ativa, &v.CriadoEm, &v.AtivadaEm)
+}
+
+// GetVersaoRegrasScore retorna uma versão de regras de score pelo ID.
+func (s *Store) GetVersaoRegrasScore(ctx context.Context, id int64) (*VersaoRegrasScore, error) {
+	q := `
+	SELECT id, versao, descricao, regras, ativa, criado_por, criado_em, ativada_em
+	FROM versoes_regras_score
+	WHERE id = $1`
+
+	return s.getVersaoRegrasScore(ctx, q, id)
+}
+
+// GetVersaoRegrasScoreAtiva retorna a versão de regras de score em vigor.
+func (s *Store) GetVersaoRegrasScoreAtiva(ctx context.Context) (*VersaoRegrasScore, error) {
+	q := `
+	SELECT id, versao, descricao, regras, ativa, criado_por, criado_em, ativada_em
+	FROM versoes_regras_score
+	WHERE ativa`
+
+	return s.getVersaoRegrasScore(ctx, q)
+}
+
+func (s *Store) getVersaoRegrasScore(ctx context.Context, q string, args ...any) (*VersaoRegrasScore, error) {
+	rows, err := s.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	v, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[VersaoRegrasScore])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// ListVersoesRegrasScore retorna todas as versões de regras de score, da mais
+// recente para a mais antiga.
+func (s *Store) ListVersoesRegrasScore(ctx context.Context) ([]*VersaoRegrasScore, error) {
+	q := `
+	SELECT id, versao, descricao, regras, ativa, criado_por, criado_em, ativada_em
+	FROM versoes_regras_score
+	ORDER BY versao DESC`
+
+	rows, err := s.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[VersaoRegrasScore])
+}
+
+// AtivarVersaoRegrasScore define a versão informada como a única ativa. Deve
+// ser executado dentro de uma transação.
+func (s *Store) AtivarVersaoRegrasScore(ctx context.Context, id int64) error {
+	_, err := s.db.Exec(ctx, `UPDATE versoes_regras_score SET ativa = FALSE WHERE ativa AND id <> $1`, id)
+	if err != nil {
+		return err
+	}
+
+	q := `
+	UPDATE versoes_regras_score SET
+		ativa = TRUE,
+		ativada_em = CURRENT_TIMESTAMP
+	WHERE id = $1`
+
+	tag, err := s.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ScoreProcessoAposentadoria registra como o score atual de um processo de
+// aposentadoria foi calculado.
+type ScoreProcessoAposentadoria struct {
+	ProcessoAposentadoriaID int64           `db:"processo_aposentadoria_id"`
+	VersaoRegrasID          int64           `db:"versao_regras_id"`
+	Score                   int             `db:"score"`
+	Detalhes                json.RawMessage `db:"detalhes"`
+	CalculadoEm             time.Time       `db:"calculado_em"`
+}
+
+// UpsertScoreProcessoAposentadoria salva o cálculo de score mais recente de
+// um processo de aposentadoria.
+func (s *Store) UpsertScoreProcessoAposentadoria(ctx context.Context, sc *ScoreProcessoAposentadoria) error {
+	q := `
+	INSERT INTO scores_processo_aposentadoria (processo_aposentadoria_id, versao_regras_id, score, detalhes)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (processo_aposentadoria_id) DO UPDATE SET
+		versao_regras_id = EXCLUDED.versao_regras_id,
+		score = EXCLUDED.score,
+		detalhes = EXCLUDED.detalhes,
+		calculado_em = CURRENT_TIMESTAMP
+	RETURNING calculado_em`
+	args := []any{sc.ProcessoAposentadoriaID, sc.VersaoRegrasID, sc.Score, sc.Detalhes}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&sc.CalculadoEm)
+}
+
+// GetScoreProcessoAposentadoria retorna o cálculo de score mais recente de um
+// processo de aposentadoria.
+func (s *Store) GetScoreProcessoAposentadoria(ctx context.Context, paID int64) (*ScoreProcessoAposentadoria, error) {
+	q := `
+	SELECT processo_aposentadoria_id, versao_regras_id, score, detalhes, calculado_em
+	FROM scores_processo_aposentadoria
+	WHERE processo_aposentadoria_id = $1`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByName[ScoreProcessoAposentadoria])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return sc, nil
+}
+
+// ListVersoesScoreProcessos retorna, para cada processo de aposentadoria com
+// score registrado, o ID da versão de regras usada no cálculo atual.
+func (s *Store) ListVersoesScoreProcessos(ctx context.Context) (map[int64]int64, error) {
+	q := `SELECT processo_aposentadoria_id, versao_regras_id FROM scores_processo_aposentadoria`
+
+	rows, err := s.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versoes := make(map[int64]int64)
+	for rows.Next() {
+		var paID, versaoID int64
+		if err := rows.Scan(&paID, &versaoID); err != nil {
+			return nil, err
+		}
+		versoes[paID] = versaoID
+	}
+	return versoes, rows.Err()
+}
+
+// HistoricoScoreProcesso registra uma mudança no score de um processo de
+// aposentadoria e o detalhamento do cálculo que a produziu.
+type HistoricoScoreProcesso struct {
+	ID                      int64           `db:"id"`
+	ProcessoAposentadoriaID int64           `db:"processo_aposentadoria_id"`
+	VersaoRegrasID          int64           `db:"versao_regras_id"`
+	ScoreAnterior           sql.Null[int]   `db:"score_anterior"`
+	ScoreNovo               int             `db:"score_novo"`
+	Detalhes                json.RawMessage `db:"detalhes"`
+	CalculadoEm             time.Time       `db:"calculado_em"`
+}
+
+// SaveHistoricoScoreProcesso registra uma mudança no score de um processo de
+// aposentadoria.
+func (s *Store) SaveHistoricoScoreProcesso(ctx context.Context, h *HistoricoScoreProcesso) error {
+	q := `
+	INSERT INTO historico_scores_processo (
+		processo_aposentadoria_id,
+		versao_regras_id,
+		score_anterior,
+		score_novo,
+		detalhes
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, calculado_em`
+	args := []any{h.ProcessoAposentadoriaID, h.VersaoRegrasID, h.ScoreAnterior, h.ScoreNovo, h.Detalhes}
+
+	return s.db.QueryRow(ctx, q, args...).Scan(&h.ID, &h.CalculadoEm)
+}
+
+// ListHistoricoScoreProcesso retorna as mudanças de score de um processo de
+// aposentadoria, da mais recente para a mais antiga.
+func (s *Store) ListHistoricoScoreProcesso(ctx context.Context, paID int64) ([]*HistoricoScoreProcesso, error) {
+	q := `
+	SELECT
+		id, processo_aposentadoria_id, versao_regras_id, score_anterior,
+		score_novo, detalhes, calculado_em
+	FROM historico_scores_processo
+	WHERE processo_aposentadoria_id = $1
+	ORDER BY id DESC`
+
+	rows, err := s.db.Query(ctx, q, paID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[HistoricoScoreProcesso])
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestVersaoRegrasScoreLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	// A migração cria a versão inicial ativa.
+	inicial, err := store.GetVersaoRegrasScoreAtiva(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inicial.Versao != 1 {
+		t.Fatalf("expected versao 1, got %d", inicial.Versao)
+	}
+
+	v := &VersaoRegrasScore{
+		Descricao: "Nova versão",
+		Regras:    json.RawMessage(`{"precedencias": [], "criterios": []}`),
+	}
+	if err := store.SaveVersaoRegrasScore(t.Context(), v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Versao != 2 || v.Ativa {
+		t.Fatalf("unexpected versao: %+v", v)
+	}
+
+	if err := store.AtivarVersaoRegrasScore(t.Context(), v.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	ativa, err := store.GetVersaoRegrasScoreAtiva(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ativa.ID != v.ID || !ativa.AtivadaEm.Valid {
+		t.Fatalf("unexpected versão ativa: %+v", ativa)
+	}
+
+	vv, err := store.ListVersoesRegrasScore(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vv) != 2 || vv[1].Ativa {
+		t.Fatalf("unexpected versões: %+v", vv)
+	}
+
+	err = store.AtivarVersaoRegrasScore(t.Context(), v.ID+1000)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpsertScoreProcessoAposentadoria(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "score-1")
+
+	versao, err := store.GetVersaoRegrasScoreAtiva(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sc := &ScoreProcessoAposentadoria{
+		ProcessoAposentadoriaID: pa.ID,
+		VersaoRegrasID:          versao.ID,
+		Score:                   3,
+		Detalhes:                json.RawMessage(`{"score": 3}`),
+	}
+	if err := store.UpsertScoreProcessoAposentadoria(t.Context(), sc); err != nil {
+		t.Fatal(err)
+	}
+
+	sc.Score = 5
+	sc.Detalhes = json.RawMessage(`{"score": 5}`)
+	if err := store.UpsertScoreProcessoAposentadoria(t.Context(), sc); err != nil {
+		t.Fatal(err)
+	}
+
+	sc2, err := store.GetScoreProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc2.Score != 5 || sc2.VersaoRegrasID != versao.ID {
+		t.Fatalf("unexpected score: %+v", sc2)
+	}
+}
+
+func TestHistoricoScoreProcesso(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	pa := seedProcessoAposentadoria(t, store, "score-2")
+
+	versao, err := store.GetVersaoRegrasScoreAtiva(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range []*HistoricoScoreProcesso{
+		{ProcessoAposentadoriaID: pa.ID, VersaoRegrasID: versao.ID, ScoreNovo: 1, Detalhes: json.RawMessage(`{}`)},
+		{ProcessoAposentadoriaID: pa.ID, VersaoRegrasID: versao.ID, ScoreAnterior: sql.Null[int]{V: 1, Valid: true}, ScoreNovo: 3, Detalhes: json.RawMessage(`{}`)},
+	} {
+		if err := store.SaveHistoricoScoreProcesso(t.Context(), h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hh, err := store.ListHistoricoScoreProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(hh))
+	}
+	if hh[0].ScoreNovo != 3 || !hh[0].ScoreAnterior.Valid || hh[0].ScoreAnterior.V != 1 {
+		t.Fatalf("unexpected entry: %+v", hh[0])
+	}
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ProdutividadePeriodo resume as ações de um analista em um período.
+type ProdutividadePeriodo struct {
+	UsuarioID         int64   `db:"usuario_id"`
+	Nome              string  `db:"nome"`
+	Unidade           string  `db:"sei_unidade_sigla"`
+	Concluidos        int     `db:"concluidos"`
+	Diligencias       int     `db:"diligencias"`
+	LeiturasInvalidas int     `db:"leituras_invalidas"`
+	Analises          int     `db:"analises"`
+	HorasEmAnalise    float64 `db:"horas_em_analise"`
+	HorasAfastado     float64 `db:"horas_afastado"`
+}
+
+// ListProdutividadePeriodo retorna, para cada analista, as ações registradas
+// no histórico de status no intervalo [inicio, fim).
+//
+// Registros do histórico que não alteram o status (como as tramitações no
+// SEI) são desconsiderados. O tempo EM_ANALISE é medido entre a entrada do
+// processo em análise e a mudança de status seguinte, atribuída ao analista
+// que a realizou. O tempo afastado considera apenas a parte dos afastamentos
+// contida no período e já transcorrida. Afastamentos programados só são
+// contados depois de iniciados, então os cancelados e os futuros não entram no
+// relatório.
+func (s *Store) ListProdutividadePeriodo(ctx context.Context, inicio, fim time.Time) ([]*ProdutividadePeriodo, error) {
+	q := `
+	WITH mudancas AS (
+		SELECT
+			h.usuario_id,
+			h.status_novo,
+			h.alterado_em,
+			LAG(h.status_novo) OVER w AS status_previo,
+			LAG(h.alterado_em) OVER w AS previo_em
+		FROM historico_status_processo h
+		WHERE h.status_anterior IS DISTINCT FROM h.status_novo
+		WINDOW w AS (PARTITION BY h.processo_aposentadoria_id ORDER BY h.alterado_em, h.id)
+	),
+	acoes AS (
+		SELECT
+			usuario_id,
+			COUNT(*) FILTER (WHERE status_novo = 'CONCLUIDO') AS concluidos,
+			COUNT(*) FILTER (WHERE status_novo = 'EM_DILIGENCIA') AS diligencias,
+			COUNT(*) FILTER (WHERE status_novo = 'LEITURA_INVALIDA') AS leituras_invalidas,
+			COUNT(*) FILTER (WHERE status_previo = 'EM_ANALISE') AS analises,
+			AVG(EXTRACT(EPOCH FROM alterado_em - previo_em) / 3600) FILTER (WHERE status_previo = 'EM_ANALISE') AS horas_em_analise
+		FROM mudancas
+		WHERE usuario_id IS NOT NULL
+		AND alterado_em >= $1 AND alterado_em < $2
+		GROUP BY usuario_id
+	),
+	afastamentos AS (
+		SELECT
+			analista_id,
+			SUM(EXTRACT(EPOCH FROM LEAST(COALESCE(fim, CURRENT_TIMESTAMP), $2, CURRENT_TIMESTAMP) - GREATEST(inicio, $1)) / 3600) AS horas
+		FROM afastamentos_analista
+		WHERE inicio < LEAST($2, CURRENT_TIMESTAMP)
+		AND COALESCE(fim, CURRENT_TIMESTAMP) > $1
+		GROUP BY analista_id
+	)
+	SELECT
+		a.usuario_id,
+		u.nome,
+		a.sei_unidade_sigla,
+		COALESCE(ac.concluidos, 0) AS concluidos,
+		COALESCE(ac.diligencias, 0) AS diligencias,
+		COALESCE(ac.leituras_invalidas, 0) AS leituras_invalidas,
+		COALESCE(ac.analises, 0) AS analises,
+		COALESCE(ac.horas_em_analise, 0)::float8 AS horas_em_analise,
+		COALESCE(af.horas, 0)::float8 AS horas_afastado
+	FROM analistas a
+	JOIN usuarios u ON u.id = a.usuario_id
+	LEFT JOIN acoes ac ON ac.usuario_id = a.usuario_id
+	LEFT JOIN afastamentos af ON af.analista_id = a.usuario_id
+	ORDER BY u.nome`
+
+	rows, err := s.db.Query(ctx, q, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[ProdutividadePeriodo])
+}
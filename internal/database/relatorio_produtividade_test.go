@@ -0,0 +1,110 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestListProdutividadePeriodo(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+	pa := seedProcessoAposentadoria(t, store, "numero-1")
+
+	transicoes := []struct {
+		anterior  StatusProcesso
+		novo      StatusProcesso
+		usuarioID sql.Null[int64]
+	}{
+		{StatusProcessoAnalisePendente, StatusProcessoEmAnalise, sql.Null[int64]{}},
+		{StatusProcessoEmAnalise, StatusProcessoEmDiligencia, sql.Null[int64]{V: usuario.ID, Valid: true}},
+		// Tramitação no SEI registrada sem mudança de status.
+		{StatusProcessoEmDiligencia, StatusProcessoEmDiligencia, sql.Null[int64]{V: usuario.ID, Valid: true}},
+		{StatusProcessoRetornoDiligencia, StatusProcessoEmAnalise, sql.Null[int64]{}},
+		{StatusProcessoEmAnalise, StatusProcessoConcluido, sql.Null[int64]{V: usuario.ID, Valid: true}},
+	}
+	for _, tr := range transicoes {
+		err := store.SaveHistoricoStatusProcesso(t.Context(), &HistoricoStatusProcesso{
+			ProcessoAposentadoriaID: pa.ID,
+			StatusAnterior:          sql.Null[StatusProcesso]{V: tr.anterior, Valid: true},
+			StatusNovo:              tr.novo,
+			UsuarioID:               tr.usuarioID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.IniciarAfastamentoAnalista(t.Context(), usuario.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	rr, err := store.ListProdutividadePeriodo(t.Context(), now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 1 {
+		t.Fatalf("expected 1 analista, got %d", len(rr))
+	}
+
+	r := rr[0]
+	if r.Concluidos != 1 || r.Diligencias != 1 || r.LeiturasInvalidas != 0 || r.Analises != 2 {
+		t.Fatalf("unexpected produtividade: %+v", r)
+	}
+	if r.HorasAfastado <= 0 {
+		t.Fatalf("expected horas afastado, got %f", r.HorasAfastado)
+	}
+
+	// Um período sem ações mantém o analista no relatório, zerado.
+	rr, err = store.ListProdutividadePeriodo(t.Context(), now.AddDate(0, -2, 0), now.AddDate(0, -1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 1 || rr[0].Concluidos != 0 || rr[0].HorasAfastado != 0 {
+		t.Fatalf("unexpected produtividade: %+v", rr)
+	}
+}
+
+func TestListProdutividadePeriodo_Afastamentos(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	usuario, _ := seedAnalista(t, store)
+
+	now := time.Now()
+	if err := store.IniciarAfastamentoAnalistaEm(t.Context(), usuario.ID, now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	// Um fim futuro não conta o período que ainda não transcorreu.
+	if err := store.EncerrarAfastamentoAnalistaEm(t.Context(), usuario.ID, now.Add(5*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	programados := []*AfastamentoProgramado{
+		{AnalistaID: usuario.ID, Inicio: now.Add(time.Hour), Fim: now.Add(3 * time.Hour)},
+		{AnalistaID: usuario.ID, Inicio: now.Add(-3 * time.Hour), Fim: now.Add(-2 * time.Hour)},
+	}
+	for _, ap := range programados {
+		if err := store.SaveAfastamentoProgramado(t.Context(), ap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	programados[1].CanceladoEm = sql.Null[time.Time]{V: now.Add(-4 * time.Hour), Valid: true}
+	if err := store.UpdateAfastamentoProgramado(t.Context(), programados[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	rr, err := store.ListProdutividadePeriodo(t.Context(), now.Add(-4*time.Hour), now.Add(10*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 1 {
+		t.Fatalf("expected 1 analista, got %d", len(rr))
+	}
+	if h := rr[0].HorasAfastado; h < 0.9 || h > 1.1 {
+		t.Fatalf("expected about 1 hora afastado, got %f", h)
+	}
+}
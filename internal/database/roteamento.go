@@ -0,0 +1,69 @@
+package database
+
+import "fmt"
+
+// competenciasAtendidas retorna a condição SQL que indica se o analista com o
+// alias informado possui todas as competências exigidas pelo processo de
+// aposentadoria "pa", cujo processo SEI é "p".
+func competenciasAtendidas(analista string) string {
+	return fmt.Sprintf(`(
+		(NOT pa.judicial OR 'JUDICIAL' = ANY(%[1]s.competencias))
+		AND (NOT pa.invalidez OR 'INVALIDEZ' = ANY(%[1]s.competencias))
+		AND (SPLIT_PART(p.sei_unidade_sigla, '/', 1) <> 'SEE' OR 'ORGAO_SEE' = ANY(%[1]s.competencias))
+	)`, analista)
+}
+
+// joinFixacaoAtiva junta ao processo de aposentadoria "pa" a sua fixação "pf".
+// Fixações para analistas afastados são desconsideradas, para que o processo
+// possa ser atribuído a outro analista durante o afastamento.
+const joinFixacaoAtiva = `LEFT JOIN processos_fixados pf ON pf.processo_aposentadoria_id = pa.id
+		AND EXISTS (
+			SELECT 1
+			FROM analistas af
+			WHERE af.usuario_id = pf.analista_id
+			AND af.afastado = FALSE
+		)`
+
+// condicaoElegivel retorna a condição SQL que indica se o analista "a" pode
+// receber o processo de aposentadoria "pa", cujo processo SEI é "p" e cuja
+// fixação é "pf". O analista é elegível se o processo estiver fixado para ele
+// ou se possuir as competências exigidas pelo processo e não tiver sido
+// excluído do processo ao devolvê-lo, desconsiderando as exclusões temporárias
+// expiradas. Quando nenhum analista elegível fica
+// livre, valem as regras de fallback, que também respeitam as exclusões:
+//
+//   - Analistas SENIOR podem receber processos que aguardam atribuição desde
+//     antes do limite, informado pelo placeholder limite. A espera conta da
+//     última mudança de status registrada no histórico ou, sem histórico, da
+//     criação do processo.
+//   - Qualquer analista pode receber processos cujas competências não são
+//     atendidas por nenhum analista em atividade.
+func condicaoElegivel(limite string) string {
+	return fmt.Sprintf(`(
+		pf.analista_id = a.usuario_id
+		OR (
+			NOT EXISTS (
+				SELECT 1
+				FROM processos_analistas_excluidos e
+				WHERE e.processo_aposentadoria_id = pa.id
+				AND e.analista_id = a.usuario_id
+				AND (e.expira_em IS NULL OR e.expira_em > CURRENT_TIMESTAMP)
+			)
+			AND (
+				%[1]s
+				OR (a.nivel = 'SENIOR' AND COALESCE((
+					SELECT MAX(h.alterado_em)
+					FROM historico_status_processo h
+					WHERE h.processo_aposentadoria_id = pa.id
+					AND h.status_anterior IS DISTINCT FROM h.status_novo
+				), pa.criado_em) < %[2]s)
+				OR NOT EXISTS (
+					SELECT 1
+					FROM analistas a2
+					WHERE a2.afastado = FALSE
+					AND %[3]s
+				)
+			)
+		)
+	)`, competenciasAtendidas("a"), limite, competenciasAtendidas("a2"))
+}
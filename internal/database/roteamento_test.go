@@ -0,0 +1,361 @@
+package database
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Cria um analista com email verificado, apto a receber processos.
+func seedAnalistaDisponivel(t *testing.T, store *Store, nivel string, competencias ...string) *Analista {
+	t.Helper()
+
+	usuario := seedUsuario(t, store, func(u *Usuario) {
+		u.EmailVerificado = true
+	})
+	analista := &Analista{
+		UsuarioID:       usuario.ID,
+		Orgao:           "SEPLAG",
+		SEIUnidadeID:    rand.Text(),
+		SEIUnidadeSigla: "SEPLAG/AP00",
+		Competencias:    competencias,
+		Nivel:           nivel,
+	}
+	if err := store.SaveAnalista(t.Context(), analista); err != nil {
+		t.Fatal(err)
+	}
+	return analista
+}
+
+func TestRoteamento_Competencias(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	limite := time.Now().Add(-24 * time.Hour)
+
+	judicial := seedProcessoAposentadoria(t, store, "38201-000040/2024-10")
+	judicial.Judicial = true
+	if err := store.UpdateProcessoAposentadoria(t.Context(), judicial); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem analista com a competência, o processo fica aberto a todos.
+	comum := seedAnalistaDisponivel(t, store, NivelPleno)
+	pa, err := store.GetProcessoPrioriatario(t.Context(), comum.UsuarioID, limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pa.ID != judicial.ID {
+		t.Fatalf("expected processo %d, got %d", judicial.ID, pa.ID)
+	}
+
+	// Com um analista competente em atividade, apenas ele é elegível.
+	especialista := seedAnalistaDisponivel(t, store, NivelPleno, CompetenciaJudicial)
+	_, err = store.GetProcessoPrioriatario(t.Context(), comum.UsuarioID, limite)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	analistaID, err := store.GetAnalistaDisponivel(t.Context(), limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analistaID != especialista.UsuarioID {
+		t.Fatalf("expected analista %d, got %d", especialista.UsuarioID, analistaID)
+	}
+
+	// Analistas SENIOR recebem processos aguardando desde antes do limite.
+	senior := seedAnalistaDisponivel(t, store, NivelSenior)
+	_, err = store.GetProcessoPrioriatario(t.Context(), senior.UsuarioID, limite)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	err = store.SaveHistoricoStatusProcesso(t.Context(), &HistoricoStatusProcesso{
+		ProcessoAposentadoriaID: judicial.ID,
+		StatusNovo:              StatusProcessoAnalisePendente,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa, err = store.GetProcessoPrioriatario(t.Context(), senior.UsuarioID, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pa.ID != judicial.ID {
+		t.Fatalf("expected processo %d, got %d", judicial.ID, pa.ID)
+	}
+}
+
+func TestRoteamento_LimiteProcessos(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	limite := time.Now().Add(-24 * time.Hour)
+	analista := seedAnalistaDisponivel(t, store, NivelSenior)
+
+	atribuir := func(numero string) error {
+		pa := seedProcessoAposentadoria(t, store, numero)
+		pa.Status = StatusProcessoEmAnalise
+		pa.AnalistaID = sql.Null[int64]{V: analista.UsuarioID, Valid: true}
+		return store.UpdateProcessoAposentadoria(t.Context(), pa)
+	}
+
+	if err := atribuir("38201-000050/2024-10"); err != nil {
+		t.Fatal(err)
+	}
+	if err := atribuir("38201-000051/2024-10"); !errors.Is(err, ErrLimiteProcessos) {
+		t.Fatalf("expected ErrLimiteProcessos, got %v", err)
+	}
+
+	// Com limite 2, o analista volta a ficar disponível.
+	seedProcessoAposentadoria(t, store, "38201-000052/2024-10")
+	if _, err := store.GetAnalistaDisponivel(t.Context(), limite); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	analista.LimiteProcessos = 2
+	if err := store.UpdateAnalista(t.Context(), analista); err != nil {
+		t.Fatal(err)
+	}
+	analistaID, err := store.GetAnalistaDisponivel(t.Context(), limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analistaID != analista.UsuarioID {
+		t.Fatalf("expected analista %d, got %d", analista.UsuarioID, analistaID)
+	}
+	if err := atribuir("38201-000053/2024-10"); err != nil {
+		t.Fatal(err)
+	}
+
+	paa, err := store.ListProcessosAtribuidos(t.Context(), analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paa) != 2 {
+		t.Fatalf("expected 2 processos, got %d", len(paa))
+	}
+
+	// A redução do limite não impede a atualização dos processos atribuídos.
+	analista.LimiteProcessos = 1
+	if err := store.UpdateAnalista(t.Context(), analista); err != nil {
+		t.Fatal(err)
+	}
+	paa[0].Score = 10
+	if err := store.UpdateProcessoAposentadoria(t.Context(), paa[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoteamento_Exclusoes(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	limite := time.Now().Add(-24 * time.Hour)
+	excluido := seedAnalistaDisponivel(t, store, NivelSenior)
+	outro := seedAnalistaDisponivel(t, store, NivelPleno)
+	pa := seedProcessoAposentadoria(t, store, "38201-000060/2024-10")
+
+	if err := store.ExcluirAnalistaProcesso(t.Context(), pa.ID, excluido.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+	// Excluir novamente não gera erro.
+	if err := store.ExcluirAnalistaProcesso(t.Context(), pa.ID, excluido.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := store.ListAnalistasExcluidos(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != excluido.UsuarioID {
+		t.Fatalf("expected [%d], got %v", excluido.UsuarioID, ids)
+	}
+
+	// O analista excluído não recebe o processo, nem pelo fallback SENIOR.
+	_, err = store.GetProcessoPrioriatario(t.Context(), excluido.UsuarioID, time.Now().Add(time.Hour))
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	analistaID, err := store.GetAnalistaDisponivel(t.Context(), limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analistaID != outro.UsuarioID {
+		t.Fatalf("expected analista %d, got %d", outro.UsuarioID, analistaID)
+	}
+
+	// A fixação pela gestão prevalece sobre a exclusão.
+	err = store.SaveProcessoFixado(t.Context(), &ProcessoFixado{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              excluido.UsuarioID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := store.GetProcessoPrioriatario(t.Context(), excluido.UsuarioID, limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, read.ID)
+	}
+}
+
+func TestRoteamento_EsperaFallback(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+
+	pa := seedProcessoAposentadoria(t, store, "38201-000061/2024-10")
+	pa.Judicial = true
+	if err := store.UpdateProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+	seedAnalistaDisponivel(t, store, NivelPleno, CompetenciaJudicial)
+	senior := seedAnalistaDisponivel(t, store, NivelSenior)
+
+	// Sem histórico, a espera conta da criação do processo.
+	_, err := store.GetProcessoPrioriatario(t.Context(), senior.UsuarioID, pa.CriadoEm)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	read, err := store.GetProcessoPrioriatario(t.Context(), senior.UsuarioID, pa.CriadoEm.Add(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, read.ID)
+	}
+
+	// Registros sem mudança de status não reiniciam a espera.
+	h := &HistoricoStatusProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		StatusAnterior:          sql.Null[StatusProcesso]{V: StatusProcessoEmAnalise, Valid: true},
+		StatusNovo:              StatusProcessoAnalisePendente,
+	}
+	if err := store.SaveHistoricoStatusProcesso(t.Context(), h); err != nil {
+		t.Fatal(err)
+	}
+	limite := h.AlteradoEm.Add(time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+	err = store.SaveHistoricoStatusProcesso(t.Context(), &HistoricoStatusProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		StatusAnterior:          sql.Null[StatusProcesso]{V: StatusProcessoAnalisePendente, Valid: true},
+		StatusNovo:              StatusProcessoAnalisePendente,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err = store.GetProcessoPrioriatario(t.Context(), senior.UsuarioID, limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, read.ID)
+	}
+
+	// A mudança de status reinicia a espera.
+	_, err = store.GetProcessoPrioriatario(t.Context(), senior.UsuarioID, h.AlteradoEm)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRoteamento_ExclusaoTemporaria(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	limite := time.Now().Add(-24 * time.Hour)
+	analista := seedAnalistaDisponivel(t, store, NivelPleno)
+	pa := seedProcessoAposentadoria(t, store, "38201-000062/2024-10")
+
+	if err := store.ExcluirAnalistaProcessoTemporariamente(t.Context(), pa.ID, analista.UsuarioID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := store.GetProcessoPrioriatario(t.Context(), analista.UsuarioID, limite)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// A exclusão temporária não aparece entre os analistas excluídos.
+	ids, err := store.ListAnalistasExcluidos(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no analistas excluídos, got %v", ids)
+	}
+
+	if err := store.DeleteExclusaoTemporaria(t.Context(), pa.ID, analista.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+	read, err := store.GetProcessoPrioriatario(t.Context(), analista.UsuarioID, limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, read.ID)
+	}
+
+	// A exclusão permanente não é removida como temporária.
+	if err := store.ExcluirAnalistaProcessoTemporariamente(t.Context(), pa.ID, analista.UsuarioID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ExcluirAnalistaProcesso(t.Context(), pa.ID, analista.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteExclusaoTemporaria(t.Context(), pa.ID, analista.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetProcessoPrioriatario(t.Context(), analista.UsuarioID, limite)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// A gestão remove as exclusões.
+	n, err := store.DeleteAnalistasExcluidos(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 exclusão removida, got %d", n)
+	}
+	read, err = store.GetProcessoPrioriatario(t.Context(), analista.UsuarioID, limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, read.ID)
+	}
+}
+
+func TestRoteamento_ExclusaoTemporariaExpirada(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t)
+	limite := time.Now().Add(-24 * time.Hour)
+	analista := seedAnalistaDisponivel(t, store, NivelPleno)
+	pa := seedProcessoAposentadoria(t, store, "38201-000063/2024-10")
+
+	// A exclusão expirada não impede a atribuição, mesmo que o job da
+	// devolução não a tenha removido.
+	if err := store.ExcluirAnalistaProcessoTemporariamente(t.Context(), pa.ID, analista.UsuarioID, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	read, err := store.GetProcessoPrioriatario(t.Context(), analista.UsuarioID, limite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.ID != pa.ID {
+		t.Fatalf("expected processo %d, got %d", pa.ID, read.ID)
+	}
+
+	n, err := store.DeleteExclusoesTemporariasExpiradas(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 exclusão expirada removida, got %d", n)
+	}
+}
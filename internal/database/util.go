@@ -1,6 +1,9 @@
 package database
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 func Ptr[T any](n sql.Null[T]) *T {
 	if n.Valid {
@@ -18,3 +21,15 @@ func Null[T any](ptr *T) sql.Null[T] {
 		Valid: true,
 	}
 }
+
+// DataBrasilia retorna a data de t no horário de Brasília, à meia-noite UTC,
+// no mesmo formato das colunas DATE lidas do banco. É a referência de "hoje"
+// para os prazos de resposta das diligências.
+func DataBrasilia(t time.Time) time.Time {
+	loc, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		loc = time.UTC
+	}
+	d := t.In(loc)
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+}
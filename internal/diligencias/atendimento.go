@@ -0,0 +1,243 @@
+package diligencias
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+// ultimaEnviada retorna a última solicitação de diligência enviada para um
+// processo de aposentadoria. Retorna [database.ErrNotFound] se o processo
+// nunca foi enviado para diligência.
+func ultimaEnviada(ctx context.Context, store *database.Store, paID int64) (*database.SolicitacaoDiligencia, error) {
+	ss, err := store.ListSolicitacoesDiligenciaByProcesso(ctx, database.ListSolicitacoesDiligenciaParams{
+		ProcessoAposentadoriaID: paID,
+		Status:                  database.StatusSolicitacaoEnviada,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ss) == 0 {
+		return nil, database.ErrNotFound
+	}
+	return ss[0], nil
+}
+
+// copiarItem cria no rascunho uma cópia de um item da diligência anterior,
+// mantendo a referência ao item de origem.
+func copiarItem(ctx context.Context, store *database.Store, rascunhoID int64, origem *database.ItemDiligencia) error {
+	return store.SaveItemDiligencia(ctx, &database.ItemDiligencia{
+		SolicitacaoDiligenciaID: rascunhoID,
+		CategoriaID:             origem.CategoriaID,
+		Tipo:                    origem.Tipo,
+		Subcategorias:           origem.Subcategorias,
+		Detalhe:                 origem.Detalhe,
+		ItemOrigemID:            sql.Null[int64]{V: origem.ID, Valid: true},
+	})
+}
+
+// copiarPendentes preenche um novo rascunho com os itens da última diligência
+// enviada para o processo que não foram marcados como atendidos.
+func copiarPendentes(ctx context.Context, store *database.Store, paID, rascunhoID int64) error {
+	anterior, err := ultimaEnviada(ctx, store, paID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	itens, err := store.ListItensDiligencia(ctx, anterior.ID)
+	if err != nil {
+		return err
+	}
+	for _, it := range itens {
+		if it.Atendido.Valid && it.Atendido.V {
+			continue
+		}
+		if err := copiarItem(ctx, store, rascunhoID, it); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validarOrigens verifica se os itens de origem informados pertencem a
+// diligências já enviadas para o processo.
+func validarOrigens(ctx context.Context, store *database.Store, paID int64, itens []NovoItem) error {
+	ss, err := store.ListSolicitacoesDiligenciaByProcesso(ctx, database.ListSolicitacoesDiligenciaParams{
+		ProcessoAposentadoriaID: paID,
+		Status:                  database.StatusSolicitacaoEnviada,
+	})
+	if err != nil {
+		return err
+	}
+
+	validos := make(map[int64]bool)
+	for _, sd := range ss {
+		ii, err := store.ListItensDiligencia(ctx, sd.ID)
+		if err != nil {
+			return err
+		}
+		for _, it := range ii {
+			validos[it.ID] = true
+		}
+	}
+
+	var v validator.Validator
+	for i, it := range itens {
+		if it.ItemOrigemID != nil {
+			v.Check(validos[*it.ItemOrigemID], fmt.Sprintf("itens[%d].item_origem_id", i), "Item de origem não pertence a uma diligência enviada")
+		}
+	}
+	if !v.Valid() {
+		return &ItensInvalidosError{FieldErrors: v.FieldErrors}
+	}
+	return nil
+}
+
+// ResultadoItem é o resultado informado pelo analista para um item da última
+// diligência enviada.
+type ResultadoItem struct {
+	ItemID     int64
+	Atendido   bool
+	Observacao string
+}
+
+type RegistrarAtendimentoParams struct {
+	ProcessoAposentadoriaID int64
+	AnalistaID              int64
+	Itens                   []ResultadoItem
+}
+
+// RegistrarAtendimento registra quais itens da última diligência enviada foram
+// atendidos pela unidade de origem. O rascunho ativo do analista, se houver, é
+// ajustado para listar apenas os itens ainda pendentes: itens atendidos são
+// removidos e itens não atendidos que não estejam no rascunho são incluídos.
+// Retorna [ErrNotAssigned] se o processo não estiver atribuído ao analista,
+// [ErrInvalidStatus] se o processo não estiver em análise,
+// [database.ErrNotFound] se o processo não tiver diligência enviada e
+// [*ItensInvalidosError] se algum item não pertencer à última diligência.
+func (s *Service) RegistrarAtendimento(ctx context.Context, params RegistrarAtendimentoParams) (*SolicitacaoDiligencia, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, params.ProcessoAposentadoriaID)
+	if err != nil {
+		return nil, err
+	}
+	if !pa.AnalistaID.Valid || pa.AnalistaID.V != params.AnalistaID {
+		return nil, ErrNotAssigned
+	}
+	if pa.Status != database.StatusProcessoEmAnalise {
+		return nil, ErrInvalidStatus
+	}
+
+	anterior, err := ultimaEnviada(ctx, store, pa.ID)
+	if err != nil {
+		return nil, err
+	}
+	itens, err := store.ListItensDiligencia(ctx, anterior.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	porID := make(map[int64]*database.ItemDiligencia, len(itens))
+	for _, it := range itens {
+		porID[it.ID] = it
+	}
+
+	var v validator.Validator
+	for i, r := range params.Itens {
+		v.Check(porID[r.ItemID] != nil, fmt.Sprintf("itens[%d].item_id", i), "Item não pertence à última diligência enviada")
+	}
+	if !v.Valid() {
+		return nil, &ItensInvalidosError{FieldErrors: v.FieldErrors}
+	}
+
+	for _, r := range params.Itens {
+		it := porID[r.ItemID]
+		it.Atendido = sql.Null[bool]{V: r.Atendido, Valid: true}
+		it.ObservacaoAtendimento = r.Observacao
+		it.AtendimentoRegistradoPor = sql.Null[int64]{V: params.AnalistaID, Valid: true}
+		if err := store.SetAtendimentoItemDiligencia(ctx, it); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sincronizarRascunho(ctx, store, pa.ID, params.AnalistaID, itens); err != nil {
+		return nil, err
+	}
+
+	var atendidos int
+	for _, it := range itens {
+		if it.Atendido.Valid && it.Atendido.V {
+			atendidos++
+		}
+	}
+	err = store.SaveEventoProcesso(ctx, &database.EventoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		Tipo:                    database.EventoAtendimentoDiligencia,
+		Descricao:               fmt.Sprintf("Atendimento da diligência verificado: %d de %d itens atendidos", atendidos, len(itens)),
+		UsuarioID:               sql.Null[int64]{V: params.AnalistaID, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return mapSolicitacao(anterior, itens), nil
+}
+
+// sincronizarRascunho ajusta o rascunho ativo do analista ao resultado da
+// verificação dos itens da diligência anterior.
+func sincronizarRascunho(ctx context.Context, store *database.Store, paID, analistaID int64, anteriores []*database.ItemDiligencia) error {
+	rascunho, err := store.GetRascunhoDiligencia(ctx, paID, analistaID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	atuais, err := store.ListItensDiligencia(ctx, rascunho.ID)
+	if err != nil {
+		return err
+	}
+	copiados := make(map[int64]*database.ItemDiligencia, len(atuais))
+	for _, it := range atuais {
+		if it.ItemOrigemID.Valid {
+			copiados[it.ItemOrigemID.V] = it
+		}
+	}
+
+	for _, it := range anteriores {
+		if !it.Atendido.Valid {
+			continue
+		}
+		copia, ok := copiados[it.ID]
+		switch {
+		case it.Atendido.V && ok:
+			if err := store.DeleteItemDiligencia(ctx, copia.ID); err != nil {
+				return err
+			}
+		case !it.Atendido.V && !ok:
+			if err := copiarItem(ctx, store, rascunho.ID, it); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package diligencias
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/validator"
+)
+
+// CategoriaDiligencia representa uma versão de uma categoria do catálogo de
+// diligências. Categorias de lista fechada exigem a seleção de subcategorias;
+// as demais exigem o preenchimento do campo aberto (detalhe).
+type CategoriaDiligencia struct {
+	ID            int64                     `json:"id"`
+	Codigo        string                    `json:"codigo"`
+	Versao        int                       `json:"versao"`
+	Nome          string                    `json:"nome"`
+	Descricao     string                    `json:"descricao"`
+	ListaFechada  bool                      `json:"lista_fechada"`
+	Ordem         int                       `json:"ordem"`
+	Ativa         bool                      `json:"ativa"`
+	Subcategorias []*SubcategoriaDiligencia `json:"subcategorias"`
+	CriadoPor     *int64                    `json:"criado_por"`
+	CriadoEm      time.Time                 `json:"criado_em"`
+}
+
+// SubcategoriaDiligencia representa um item da lista fechada de uma categoria.
+type SubcategoriaDiligencia struct {
+	Codigo string `json:"codigo"`
+	Nome   string `json:"nome"`
+}
+
+// Check valida os dados de uma nova versão de categoria.
+func (c *CategoriaDiligencia) Check(v *validator.Validator) {
+	v.Check(validator.NotBlank(c.Codigo), "codigo", "Campo obrigatório")
+	v.Check(validator.NotBlank(c.Nome), "nome", "Campo obrigatório")
+	if c.ListaFechada {
+		v.Check(len(c.Subcategorias) > 0, "subcategorias", "Categorias de lista fechada devem ter ao menos uma subcategoria")
+	} else {
+		v.Check(len(c.Subcategorias) == 0, "subcategorias", "Categorias de campo aberto não possuem subcategorias")
+	}
+
+	codigos := make([]string, len(c.Subcategorias))
+	for i, sub := range c.Subcategorias {
+		v.Check(validator.NotBlank(sub.Codigo), fmt.Sprintf("subcategorias[%d].codigo", i), "Campo obrigatório")
+		v.Check(validator.NotBlank(sub.Nome), fmt.Sprintf("subcategorias[%d].nome", i), "Campo obrigatório")
+		codigos[i] = sub.Codigo
+	}
+	v.Check(validator.Unique(codigos), "subcategorias", "Os códigos das subcategorias devem ser únicos")
+}
+
+// subcategoria retorna a subcategoria identificada pelo código ou pelo nome.
+func (c *CategoriaDiligencia) subcategoria(s string) *SubcategoriaDiligencia {
+	for _, sub := range c.Subcategorias {
+		if sub.Codigo == s || sub.Nome == s {
+			return sub
+		}
+	}
+	return nil
+}
+
+func mapCategoria(c *database.CategoriaDiligencia, subs []*database.SubcategoriaDiligencia) *CategoriaDiligencia {
+	cat := &CategoriaDiligencia{
+		ID:            c.ID,
+		Codigo:        c.Codigo,
+		Versao:        c.Versao,
+		Nome:          c.Nome,
+		Descricao:     c.Descricao,
+		ListaFechada:  c.ListaFechada,
+		Ordem:         c.Ordem,
+		Ativa:         c.Ativa,
+		Subcategorias: make([]*SubcategoriaDiligencia, 0),
+		CriadoPor:     database.Ptr(c.CriadoPor),
+		CriadoEm:      c.CriadoEm,
+	}
+	for _, sub := range subs {
+		if sub.CategoriaID == c.ID {
+			cat.Subcategorias = append(cat.Subcategorias, &SubcategoriaDiligencia{
+				Codigo: sub.Codigo,
+				Nome:   sub.Nome,
+			})
+		}
+	}
+	return cat
+}
+
+// ListCategorias retorna o catálogo de categorias de diligência com suas
+// subcategorias. Quando todas é verdadeiro, inclui versões anteriores e
+// categorias desativadas.
+func (s *Service) ListCategorias(ctx context.Context, todas bool) ([]*CategoriaDiligencia, error) {
+	return s.listCategorias(ctx, s.store, todas)
+}
+
+func (s *Service) listCategorias(ctx context.Context, store *database.Store, todas bool) ([]*CategoriaDiligencia, error) {
+	cc, err := store.ListCategoriasDiligencia(ctx, !todas)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(cc))
+	for i, c := range cc {
+		ids[i] = c.ID
+	}
+	subs, err := store.ListSubcategoriasDiligencia(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	categorias := make([]*CategoriaDiligencia, len(cc))
+	for i, c := range cc {
+		categorias[i] = mapCategoria(c, subs)
+	}
+	return categorias, nil
+}
+
+// GetCategoria retorna uma versão de categoria de diligência pelo ID.
+func (s *Service) GetCategoria(ctx context.Context, id int64) (*CategoriaDiligencia, error) {
+	c, err := s.store.GetCategoriaDiligencia(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := s.store.ListSubcategoriasDiligencia(ctx, []int64{c.ID})
+	if err != nil {
+		return nil, err
+	}
+	return mapCategoria(c, subs), nil
+}
+
+type CreateCategoriaParams struct {
+	Categoria *CategoriaDiligencia
+	UsuarioID int64
+	// Ativar indica se a nova versão deve substituir imediatamente a versão
+	// ativa do mesmo código.
+	Ativar bool
+}
+
+// CreateCategoria cria uma nova versão de uma categoria de diligência. Caso o
+// código ainda não exista, a versão criada é a primeira. A categoria deve ter
+// sido validada com [CategoriaDiligencia.Check].
+func (s *Service) CreateCategoria(ctx context.Context, params CreateCategoriaParams) (*CategoriaDiligencia, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	c := &database.CategoriaDiligencia{
+		Codigo:       params.Categoria.Codigo,
+		Nome:         params.Categoria.Nome,
+		Descricao:    params.Categoria.Descricao,
+		ListaFechada: params.Categoria.ListaFechada,
+		Ordem:        params.Categoria.Ordem,
+		CriadoPor:    sql.Null[int64]{V: params.UsuarioID, Valid: true},
+	}
+	if err := store.SaveCategoriaDiligencia(ctx, c); err != nil {
+		return nil, err
+	}
+
+	for i, sub := range params.Categoria.Subcategorias {
+		err := store.SaveSubcategoriaDiligencia(ctx, &database.SubcategoriaDiligencia{
+			CategoriaID: c.ID,
+			Codigo:      sub.Codigo,
+			Nome:        sub.Nome,
+			Ordem:       i + 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if params.Ativar {
+		if err := store.AtivarCategoriaDiligencia(ctx, c.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetCategoria(ctx, c.ID)
+}
+
+// AtivarCategoria coloca uma versão de categoria em uso, desativando as demais
+// versões do mesmo código.
+func (s *Service) AtivarCategoria(ctx context.Context, id int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.store.WithTx(tx).AtivarCategoriaDiligencia(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DesativarCategoria retira uma versão de categoria de uso. Itens já
+// registrados com a categoria não são alterados.
+func (s *Service) DesativarCategoria(ctx context.Context, id int64) error {
+	return s.store.DesativarCategoriaDiligencia(ctx, id)
+}
+
+// ItensInvalidosError é retornado quando os itens de uma diligência não
+// correspondem ao catálogo de categorias ativas. FieldErrors segue o formato
+// de [validator.Validator].
+type ItensInvalidosError struct {
+	FieldErrors map[string]string
+}
+
+func (e *ItensInvalidosError) Error() string {
+	return "diligência items do not match the active category catalog"
+}
+
+// normalizarItens valida os itens contra o catálogo ativo e os converte para
+// o formato armazenado. Categorias e subcategorias podem ser informadas pelo
+// código ou pelo nome, e são sempre gravadas pelo nome da versão em uso.
+func normalizarItens(catalogo []*CategoriaDiligencia, itens []NovoItem) ([]*database.ItemDiligencia, error) {
+	var v validator.Validator
+
+	result := make([]*database.ItemDiligencia, 0, len(itens))
+	for i, it := range itens {
+		campo := fmt.Sprintf("itens[%d]", i)
+
+		idx := slices.IndexFunc(catalogo, func(c *CategoriaDiligencia) bool {
+			return c.Codigo == it.Tipo || c.Nome == it.Tipo
+		})
+		if idx < 0 {
+			v.Check(false, campo+".tipo", "Categoria inexistente ou inativa")
+			continue
+		}
+		cat := catalogo[idx]
+
+		subs := make([]string, 0, len(it.Subcategorias))
+		if cat.ListaFechada {
+			v.Check(len(it.Subcategorias) > 0, campo+".subcategorias", "Selecione ao menos uma subcategoria")
+			for _, nome := range it.Subcategorias {
+				sub := cat.subcategoria(nome)
+				if sub == nil {
+					v.Check(false, campo+".subcategorias", fmt.Sprintf("Subcategoria inválida: %s", nome))
+					continue
+				}
+				subs = append(subs, sub.Nome)
+			}
+			v.Check(validator.Unique(subs), campo+".subcategorias", "Subcategorias repetidas")
+		} else {
+			v.Check(len(it.Subcategorias) == 0, campo+".subcategorias", "A categoria não possui subcategorias")
+			v.Check(validator.NotBlank(it.Detalhe), campo+".detalhe", "Campo obrigatório")
+		}
+
+		result = append(result, &database.ItemDiligencia{
+			CategoriaID:   sql.Null[int64]{V: cat.ID, Valid: true},
+			Tipo:          cat.Nome,
+			Subcategorias: subs,
+			Detalhe:       it.Detalhe,
+			ItemOrigemID:  database.Null(it.ItemOrigemID),
+		})
+	}
+
+	if !v.Valid() {
+		return nil, &ItensInvalidosError{FieldErrors: v.FieldErrors}
+	}
+	return result, nil
+}
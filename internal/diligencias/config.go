@@ -0,0 +1,17 @@
+package diligencias
+
+type Config struct {
+	// Unidade do SEI que recebe os processos enviados para diligência. Se
+	// vazia, o processo é enviado à unidade de origem registrada no SEI.
+	UnidadeDestino string `env:"UNIDADE_SEI_DILIGENCIA"`
+	// Tipo de documento (série) do SEI usado para o ofício de diligência. Se
+	// vazio, o ofício é gerado mas não é incluído no processo do SEI.
+	SerieOficio string `env:"SEI_SERIE_OFICIO_DILIGENCIA"`
+	// Prazo, em dias corridos a partir do envio, para que a unidade de origem
+	// atenda à diligência.
+	PrazoResposta int `env:"DILIGENCIA_PRAZO_RESPOSTA_DIAS" envDefault:"30"`
+	// Dias, relativos ao prazo de resposta, em que a unidade de origem recebe
+	// lembretes da diligência pendente. Valores negativos são anteriores ao
+	// prazo e positivos, posteriores.
+	Lembretes []int `env:"DILIGENCIA_LEMBRETES_DIAS" envDefault:"-7,-1,1,7" envSeparator:","`
+}
@@ -0,0 +1,166 @@
+package diligencias
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/mail"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/automatiza-mg/fila/internal/validator"
+	"github.com/jackc/pgx/v5"
+)
+
+// ContatoUnidade é um contato de RH de uma unidade do SEI, notificado por
+// email quando um processo originado na unidade é enviado para diligência.
+type ContatoUnidade struct {
+	ID           int64     `json:"id"`
+	UnidadeSigla string    `json:"unidade_sigla"`
+	Nome         string    `json:"nome"`
+	Email        string    `json:"email"`
+	CriadoEm     time.Time `json:"criado_em"`
+	AtualizadoEm time.Time `json:"atualizado_em"`
+}
+
+func mapContato(c *database.ContatoUnidade) *ContatoUnidade {
+	return &ContatoUnidade{
+		ID:           c.ID,
+		UnidadeSigla: c.UnidadeSigla,
+		Nome:         c.Nome,
+		Email:        c.Email,
+		CriadoEm:     c.CriadoEm,
+		AtualizadoEm: c.AtualizadoEm,
+	}
+}
+
+type ContatoParams struct {
+	UnidadeSigla string
+	Nome         string
+	Email        string
+}
+
+// Check valida os dados de um contato de unidade.
+func (p ContatoParams) Check(v *validator.Validator) {
+	v.Check(validator.NotBlank(p.UnidadeSigla), "unidade_sigla", "Campo obrigatório")
+	v.Check(validator.NotBlank(p.Email), "email", "Campo obrigatório")
+	v.Check(validator.Matches(p.Email, validator.EmailRX), "email", "Email inválido")
+}
+
+// ListContatos retorna os contatos da unidade informada, ou de todas as
+// unidades se sigla for vazia.
+func (s *Service) ListContatos(ctx context.Context, sigla string) ([]*ContatoUnidade, error) {
+	cc, err := s.store.ListContatosUnidade(ctx, sigla)
+	if err != nil {
+		return nil, err
+	}
+
+	contatos := make([]*ContatoUnidade, len(cc))
+	for i, c := range cc {
+		contatos[i] = mapContato(c)
+	}
+	return contatos, nil
+}
+
+// GetContato retorna um contato de unidade pelo ID.
+func (s *Service) GetContato(ctx context.Context, id int64) (*ContatoUnidade, error) {
+	c, err := s.store.GetContatoUnidade(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapContato(c), nil
+}
+
+// CreateContato cadastra um contato de unidade. Retorna
+// [database.ErrContatoUnidadeTaken] se o email já estiver cadastrado para a
+// unidade.
+func (s *Service) CreateContato(ctx context.Context, params ContatoParams) (*ContatoUnidade, error) {
+	c := &database.ContatoUnidade{
+		UnidadeSigla: params.UnidadeSigla,
+		Nome:         params.Nome,
+		Email:        params.Email,
+	}
+	if err := s.store.SaveContatoUnidade(ctx, c); err != nil {
+		return nil, err
+	}
+	return mapContato(c), nil
+}
+
+// UpdateContato atualiza os dados de um contato de unidade. Retorna
+// [database.ErrContatoUnidadeTaken] se o email já estiver cadastrado para a
+// unidade.
+func (s *Service) UpdateContato(ctx context.Context, id int64, params ContatoParams) (*ContatoUnidade, error) {
+	c, err := s.store.GetContatoUnidade(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c.UnidadeSigla = params.UnidadeSigla
+	c.Nome = params.Nome
+	c.Email = params.Email
+	if err := s.store.UpdateContatoUnidade(ctx, c); err != nil {
+		return nil, err
+	}
+	return mapContato(c), nil
+}
+
+// DeleteContato remove um contato de unidade.
+func (s *Service) DeleteContato(ctx context.Context, id int64) error {
+	return s.store.DeleteContatoUnidade(ctx, id)
+}
+
+// notifyDiligenciaEnviada envia aos contatos da unidade de origem do processo
+// a lista de itens da diligência e o prazo de resposta. Processos de unidades
+// sem contatos cadastrados não geram notificação.
+func (s *Service) notifyDiligenciaEnviada(ctx context.Context, tx pgx.Tx, pa *database.ProcessoAposentadoria, itens []*database.ItemDiligencia, prazo time.Time) error {
+	store := s.store.WithTx(tx)
+
+	p, err := store.GetProcesso(ctx, pa.ProcessoID)
+	if err != nil {
+		return err
+	}
+	if p.SeiUnidadeSigla == "" {
+		return nil
+	}
+
+	contatos, err := store.ListContatosUnidade(ctx, p.SeiUnidadeSigla)
+	if err != nil {
+		return err
+	}
+	if len(contatos) == 0 {
+		s.logger.Info("unidade sem contatos para notificação de diligência",
+			slog.Int64("processo_aposentadoria_id", pa.ID),
+			slog.String("unidade", p.SeiUnidadeSigla),
+		)
+		return nil
+	}
+
+	to := make([]string, len(contatos))
+	for i, c := range contatos {
+		to[i] = c.Email
+	}
+
+	params := mail.DiligenciaEmailParams{
+		NumeroProcesso: p.Numero,
+		Unidade:        p.SeiUnidadeSigla,
+		PrazoResposta:  prazo.Format("02/01/2006"),
+		Itens:          make([]mail.ItemDiligenciaEmail, len(itens)),
+	}
+	for i, it := range itens {
+		params.Itens[i] = mail.ItemDiligenciaEmail{
+			Tipo:          it.Tipo,
+			Subcategorias: it.Subcategorias,
+			Detalhe:       it.Detalhe,
+		}
+	}
+
+	email, err := mail.NewDiligenciaEmail(to, params)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.queue.InsertTx(ctx, tx, tasks.SendEmailArgs{
+		Email: email,
+	}, nil)
+	return err
+}
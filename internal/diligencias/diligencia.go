@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -21,30 +24,64 @@ type SolicitacaoDiligencia struct {
 	Itens                   []*ItemDiligencia `json:"itens"`
 	CriadoEm                time.Time         `json:"criado_em"`
 	EnviadaEm               *time.Time        `json:"enviada_em"`
+	PrazoResposta           *time.Time        `json:"prazo_resposta"`
+	Oficio                  string            `json:"oficio,omitempty"`
+	DocumentoSEI            *string           `json:"documento_sei"`
 }
 
 // ItemDiligencia representa uma diligência individual dentro de uma solicitação.
 type ItemDiligencia struct {
 	ID            int64    `json:"id"`
+	CategoriaID   *int64   `json:"categoria_id"`
 	Tipo          string   `json:"tipo"`
 	Subcategorias []string `json:"subcategorias"`
 	Detalhe       string   `json:"detalhe"`
+	// ItemOrigemID identifica o item da diligência anterior reaproveitado
+	// neste item.
+	ItemOrigemID *int64 `json:"item_origem_id"`
+	// Atendimento é o resultado da verificação do item no retorno da
+	// diligência. É nulo enquanto o item não for verificado.
+	Atendimento *AtendimentoItem `json:"atendimento"`
 }
 
-// NovoItem representa os dados de entrada para criação de um item de diligência.
+// AtendimentoItem é o resultado registrado pelo analista para um item de
+// diligência enviado, após o retorno do processo.
+type AtendimentoItem struct {
+	Atendido      bool      `json:"atendido"`
+	Observacao    string    `json:"observacao"`
+	RegistradoPor *int64    `json:"registrado_por"`
+	RegistradoEm  time.Time `json:"registrado_em"`
+}
+
+// NovoItem representa os dados de entrada para criação de um item de
+// diligência. Tipo e Subcategorias aceitam o código ou o nome cadastrado no
+// catálogo de categorias. ItemOrigemID, quando informado, deve referenciar um
+// item de uma diligência já enviada para o processo.
 type NovoItem struct {
 	Tipo          string
 	Subcategorias []string
 	Detalhe       string
+	ItemOrigemID  *int64
 }
 
 func mapItem(it *database.ItemDiligencia) *ItemDiligencia {
-	return &ItemDiligencia{
+	item := &ItemDiligencia{
 		ID:            it.ID,
+		CategoriaID:   database.Ptr(it.CategoriaID),
 		Tipo:          it.Tipo,
 		Subcategorias: it.Subcategorias,
 		Detalhe:       it.Detalhe,
+		ItemOrigemID:  database.Ptr(it.ItemOrigemID),
+	}
+	if it.Atendido.Valid {
+		item.Atendimento = &AtendimentoItem{
+			Atendido:      it.Atendido.V,
+			Observacao:    it.ObservacaoAtendimento,
+			RegistradoPor: database.Ptr(it.AtendimentoRegistradoPor),
+			RegistradoEm:  it.AtendimentoRegistradoEm.V,
+		}
 	}
+	return item
 }
 
 func mapSolicitacao(sd *database.SolicitacaoDiligencia, itens []*database.ItemDiligencia) *SolicitacaoDiligencia {
@@ -60,12 +97,16 @@ func mapSolicitacao(sd *database.SolicitacaoDiligencia, itens []*database.ItemDi
 		Itens:                   items,
 		CriadoEm:                sd.CriadoEm,
 		EnviadaEm:               database.Ptr(sd.EnviadaEm),
+		PrazoResposta:           database.Ptr(sd.PrazoResposta),
+		Oficio:                  sd.Oficio,
+		DocumentoSEI:            database.Ptr(sd.SEIDocumentoNumero),
 	}
 }
 
 // GetOrCreateRascunho retorna o rascunho ativo de diligência para o analista
-// em um processo de aposentadoria. Cria um novo rascunho caso não exista.
-// Retorna [ErrNotAssigned] se o processo não estiver atribuído ao analista e
+// em um processo de aposentadoria. Cria um novo rascunho caso não exista,
+// preenchido com os itens da última diligência enviada que não foram marcados
+// como atendidos. Retorna [ErrNotAssigned] se o processo não estiver atribuído ao analista e
 // [ErrInvalidStatus] se o processo não estiver em análise.
 func (s *Service) GetOrCreateRascunho(ctx context.Context, paID, analistaID int64) (*SolicitacaoDiligencia, error) {
 	tx, err := s.pool.Begin(ctx)
@@ -105,6 +146,8 @@ func (s *Service) GetOrCreateRascunho(ctx context.Context, paID, analistaID int6
 			} else {
 				return nil, err
 			}
+		} else if err := copiarPendentes(ctx, store, paID, sd.ID); err != nil {
+			return nil, err
 		}
 	default:
 		return nil, err
@@ -148,8 +191,10 @@ type SalvarRascunhoParams struct {
 // SalvarRascunho substitui o conjunto de itens de um rascunho pelos itens
 // informados. Itens anteriores são descartados. Retorna [ErrNotAssigned] se a
 // solicitação não pertencer ao analista informado, [ErrAlreadySent] se a
-// solicitação já tiver sido enviada e [ErrInvalidStatus] se o processo não
-// estiver em análise.
+// solicitação já tiver sido enviada, [ErrInvalidStatus] se o processo não
+// estiver em análise e [*ItensInvalidosError] se algum item não corresponder
+// ao catálogo de categorias ativas ou referenciar um item de origem que não
+// pertença a uma diligência enviada para o processo.
 func (s *Service) SalvarRascunho(ctx context.Context, params SalvarRascunhoParams) (*SolicitacaoDiligencia, error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -181,20 +226,23 @@ func (s *Service) SalvarRascunho(ctx context.Context, params SalvarRascunhoParam
 		return nil, ErrNotAssigned
 	}
 
+	catalogo, err := s.listCategorias(ctx, store, false)
+	if err != nil {
+		return nil, err
+	}
+	novos, err := normalizarItens(catalogo, params.Itens)
+	if err != nil {
+		return nil, err
+	}
+	if err := validarOrigens(ctx, store, pa.ID, params.Itens); err != nil {
+		return nil, err
+	}
+
 	if err := store.DeleteItensDiligencia(ctx, sd.ID); err != nil {
 		return nil, err
 	}
-	for _, it := range params.Itens {
-		subs := it.Subcategorias
-		if subs == nil {
-			subs = []string{}
-		}
-		item := &database.ItemDiligencia{
-			SolicitacaoDiligenciaID: sd.ID,
-			Tipo:                    it.Tipo,
-			Subcategorias:           subs,
-			Detalhe:                 it.Detalhe,
-		}
+	for _, item := range novos {
+		item.SolicitacaoDiligenciaID = sd.ID
 		if err := store.SaveItemDiligencia(ctx, item); err != nil {
 			return nil, err
 		}
@@ -231,7 +279,8 @@ func (s *Service) DescartarRascunho(ctx context.Context, solicitacaoID, analista
 }
 
 // EnviarDiligencia finaliza um rascunho, marcando-o como enviado, alterando o
-// status do processo para EM_DILIGENCIA e desatribuindo o analista. Retorna
+// status do processo para EM_DILIGENCIA e desatribuindo o analista. O prazo de
+// resposta da unidade de origem é contado a partir do envio. Retorna
 // [ErrNotAssigned] se a solicitação ou o processo não pertencer ao analista,
 // [ErrAlreadySent] se a solicitação já tiver sido enviada, [ErrInvalidStatus]
 // se o processo não estiver em análise e [ErrDraftEmpty] se o rascunho não
@@ -276,12 +325,27 @@ func (s *Service) EnviarDiligencia(ctx context.Context, solicitacaoID, analistaI
 	}
 
 	now := time.Now().UTC()
+	prazo := prazoResposta(now, s.cfg.PrazoResposta)
+
+	oficio, err := s.gerarOficio(ctx, store, pa, analistaID, itens, prazo)
+	if err != nil {
+		return nil, err
+	}
+
 	sd.Status = database.StatusSolicitacaoEnviada
 	sd.EnviadaEm = sql.Null[time.Time]{V: now, Valid: true}
+	sd.Oficio = oficio
+	sd.PrazoResposta = sql.Null[time.Time]{V: prazo, Valid: true}
 	if err := store.UpdateSolicitacaoDiligencia(ctx, sd); err != nil {
 		return nil, err
 	}
 
+	// Os documentos de uma diligência anterior são registrados novamente na
+	// tramitação deste envio.
+	if err := store.DeleteDocumentosSEIDiligencia(ctx, pa.ID); err != nil {
+		return nil, err
+	}
+
 	statusAnterior := pa.Status
 	pa.UltimoAnalistaID = pa.AnalistaID
 	pa.AnalistaID = sql.Null[int64]{}
@@ -300,6 +364,19 @@ func (s *Service) EnviarDiligencia(ctx context.Context, solicitacaoID, analistaI
 		return nil, err
 	}
 
+	if err := s.enviarProcessoDiligencia(ctx, tx, pa, sd.ID, analistaID); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifyDiligenciaEnviada(ctx, tx, pa, itens, prazo); err != nil {
+		return nil, err
+	}
+
+	// O analista fica livre para receber o próximo processo da fila.
+	if _, err := s.queue.InsertTx(ctx, tx, tasks.AtribuirProcessosArgs{}, nil); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
@@ -307,6 +384,96 @@ func (s *Service) EnviarDiligencia(ctx context.Context, solicitacaoID, analistaI
 	return mapSolicitacao(sd, itens), nil
 }
 
+// prazoResposta retorna a data limite para a resposta de uma diligência
+// enviada em enviadaEm, contada em dias corridos no horário de Brasília.
+func prazoResposta(enviadaEm time.Time, dias int) time.Time {
+	return database.DataBrasilia(enviadaEm).AddDate(0, 0, dias)
+}
+
+// gerarOficio gera o ofício de diligência com os itens da solicitação.
+func (s *Service) gerarOficio(ctx context.Context, store *database.Store, pa *database.ProcessoAposentadoria, analistaID int64, itens []*database.ItemDiligencia, prazo time.Time) (string, error) {
+	p, err := store.GetProcesso(ctx, pa.ProcessoID)
+	if err != nil {
+		return "", err
+	}
+	analista, err := store.GetAnalista(ctx, analistaID)
+	if err != nil {
+		return "", err
+	}
+	usuario, err := store.GetUsuario(ctx, analistaID)
+	if err != nil {
+		return "", err
+	}
+
+	params := OficioParams{
+		NumeroProcesso:  p.Numero,
+		UnidadeDestino:  p.SeiUnidadeSigla,
+		Analista:        usuario.Nome,
+		UnidadeAnalista: analista.SEIUnidadeSigla,
+		Data:            dataPorExtenso(time.Now()),
+		PrazoResposta:   prazo.Format("02/01/2006"),
+		Itens:           make([]*ItemDiligencia, len(itens)),
+	}
+	for i, it := range itens {
+		params.Itens[i] = mapItem(it)
+	}
+	return RenderOficio(params)
+}
+
+// enviarProcessoDiligencia agenda a tramitação do processo no SEI, da unidade
+// do analista para a unidade configurada para diligências. Quando não há
+// unidade configurada, o processo é devolvido à unidade de origem. Se houver
+// tipo de documento configurado para o ofício, a tramitação é agendada apenas
+// após a inclusão do ofício no processo.
+func (s *Service) enviarProcessoDiligencia(ctx context.Context, tx pgx.Tx, pa *database.ProcessoAposentadoria, solicitacaoID, analistaID int64) error {
+	store := s.store.WithTx(tx)
+
+	analista, err := store.GetAnalista(ctx, analistaID)
+	if err != nil {
+		return err
+	}
+
+	destino := s.cfg.UnidadeDestino
+	if destino == "" {
+		p, err := store.GetProcesso(ctx, pa.ProcessoID)
+		if err != nil {
+			return err
+		}
+		destino = p.SeiUnidadeID
+	}
+
+	var tramitacao *tasks.EnviarProcessoSEIArgs
+	if destino == "" {
+		s.logger.Warn("processo sem unidade de destino para diligência",
+			slog.Int64("processo_aposentadoria_id", pa.ID),
+		)
+	} else {
+		tramitacao = &tasks.EnviarProcessoSEIArgs{
+			ProcessoAposentadoriaID: pa.ID,
+			UnidadeOrigem:           analista.SEIUnidadeID,
+			UnidadeDestino:          destino,
+			Motivo:                  "envio para diligência",
+			RegistrarDocumentos:     true,
+		}
+	}
+
+	if s.cfg.SerieOficio != "" {
+		_, err = s.queue.InsertTx(ctx, tx, tasks.IncluirOficioDiligenciaArgs{
+			SolicitacaoDiligenciaID: solicitacaoID,
+			Unidade:                 analista.SEIUnidadeID,
+			IdSerie:                 s.cfg.SerieOficio,
+			Tramitacao:              tramitacao,
+		}, nil)
+		return err
+	}
+
+	if tramitacao == nil {
+		return nil
+	}
+	_, err = s.queue.InsertTx(ctx, tx, *tramitacao, nil)
+	return err
+}
+
 // GetSolicitacaoDiligencia retorna uma solicitação de diligência pelo ID,
 // incluindo seus itens.
 func (s *Service) GetSolicitacaoDiligencia(ctx context.Context, id int64) (*SolicitacaoDiligencia, error) {
@@ -0,0 +1,136 @@
+package diligencias
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/relatorios"
+)
+
+// Agrupamentos e intervalos aceitos por [Service.Estatisticas].
+const (
+	AgrupamentoCategoria    = string(database.AgrupamentoCategoria)
+	AgrupamentoSubcategoria = string(database.AgrupamentoSubcategoria)
+	AgrupamentoUnidade      = string(database.AgrupamentoUnidade)
+	AgrupamentoOrgao        = string(database.AgrupamentoOrgao)
+
+	IntervaloSemana = "semana"
+	IntervaloMes    = "mes"
+)
+
+// TotalDiligencia é a quantidade de diligências enviadas para uma chave de
+// agrupamento em todo o período.
+type TotalDiligencia struct {
+	Chave        string `json:"chave"`
+	Solicitacoes int    `json:"solicitacoes"`
+	Itens        int    `json:"itens"`
+}
+
+// TendenciaDiligencia é a quantidade de diligências enviadas para uma chave de
+// agrupamento em um intervalo (semana ou mês).
+type TendenciaDiligencia struct {
+	Periodo      time.Time `json:"periodo"`
+	Chave        string    `json:"chave"`
+	Solicitacoes int       `json:"solicitacoes"`
+	Itens        int       `json:"itens"`
+}
+
+// Estatisticas agrega as diligências enviadas para subsidiar ações de
+// capacitação das unidades de RH.
+type Estatisticas struct {
+	Agrupamento string                 `json:"agrupamento"`
+	Intervalo   string                 `json:"intervalo"`
+	Totais      []*TotalDiligencia     `json:"totais"`
+	Tendencia   []*TendenciaDiligencia `json:"tendencia"`
+}
+
+// EstatisticasParams são os filtros de [Service.Estatisticas]. Inicio e Fim
+// são opcionais, e Fim não pertence ao período.
+type EstatisticasParams struct {
+	Agrupamento string
+	Intervalo   string
+	Inicio      *time.Time
+	Fim         *time.Time
+}
+
+// Estatisticas retorna as diligências enviadas no período agrupadas por
+// categoria, subcategoria, unidade ou órgão de origem do processo. Os totais
+// são ordenados do mais frequente para o menos frequente.
+func (s *Service) Estatisticas(ctx context.Context, params EstatisticasParams) (*Estatisticas, error) {
+	intervalo := database.IntervaloMes
+	if params.Intervalo == IntervaloSemana {
+		intervalo = database.IntervaloSemana
+	}
+
+	rows, err := s.store.ListEstatisticasDiligencia(ctx, database.EstatisticasDiligenciaParams{
+		Agrupamento: database.AgrupamentoDiligencia(params.Agrupamento),
+		Intervalo:   intervalo,
+		Inicio:      database.Null(params.Inicio),
+		Fim:         database.Null(params.Fim),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	est := &Estatisticas{
+		Agrupamento: params.Agrupamento,
+		Intervalo:   params.Intervalo,
+		Totais:      make([]*TotalDiligencia, 0),
+		Tendencia:   make([]*TendenciaDiligencia, len(rows)),
+	}
+
+	// Cada solicitação pertence a um único intervalo, então os totais podem
+	// ser obtidos somando os intervalos.
+	totais := make(map[string]*TotalDiligencia)
+	for i, r := range rows {
+		est.Tendencia[i] = &TendenciaDiligencia{
+			Periodo:      r.Periodo,
+			Chave:        r.Chave,
+			Solicitacoes: r.Solicitacoes,
+			Itens:        r.Itens,
+		}
+
+		t, ok := totais[r.Chave]
+		if !ok {
+			t = &TotalDiligencia{Chave: r.Chave}
+			totais[r.Chave] = t
+			est.Totais = append(est.Totais, t)
+		}
+		t.Solicitacoes += r.Solicitacoes
+		t.Itens += r.Itens
+	}
+
+	slices.SortStableFunc(est.Totais, func(a, b *TotalDiligencia) int {
+		if c := cmp.Compare(b.Itens, a.Itens); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Chave, b.Chave)
+	})
+
+	return est, nil
+}
+
+var cabecalhoEstatisticas = []string{"Período", "Chave", "Solicitações", "Itens"}
+
+func (e *Estatisticas) linhas() [][]any {
+	linhas := make([][]any, len(e.Tendencia))
+	for i, t := range e.Tendencia {
+		linhas[i] = []any{t.Periodo.Format(time.DateOnly), t.Chave, t.Solicitacoes, t.Itens}
+	}
+	return linhas
+}
+
+// WriteCSV escreve a tendência das diligências em formato CSV, uma linha por
+// intervalo e chave de agrupamento.
+func (e *Estatisticas) WriteCSV(w io.Writer) error {
+	return relatorios.WriteCSV(w, cabecalhoEstatisticas, e.linhas())
+}
+
+// WriteXLSX escreve a tendência das diligências em uma planilha XLSX.
+func (e *Estatisticas) WriteXLSX(w io.Writer) error {
+	return relatorios.WriteXLSX(w, "Diligências", cabecalhoEstatisticas, e.linhas())
+}
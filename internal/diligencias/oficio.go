@@ -0,0 +1,47 @@
+package diligencias
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+var (
+	//go:embed templates
+	fs embed.FS
+
+	oficioTmpl = template.Must(template.ParseFS(fs, "templates/oficio.tmpl"))
+)
+
+// OficioParams são os dados usados na geração do ofício de diligência.
+type OficioParams struct {
+	NumeroProcesso  string
+	UnidadeDestino  string
+	Analista        string
+	UnidadeAnalista string
+	Data            string
+	PrazoResposta   string
+	Itens           []*ItemDiligencia
+}
+
+// RenderOficio gera o HTML do ofício de diligência, no formato aceito pelo
+// SEI para documentos gerados.
+func RenderOficio(params OficioParams) (string, error) {
+	var buf bytes.Buffer
+	if err := oficioTmpl.Execute(&buf, params); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var meses = [...]string{
+	"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+	"julho", "agosto", "setembro", "outubro", "novembro", "dezembro",
+}
+
+// dataPorExtenso formata uma data como "2 de janeiro de 2006".
+func dataPorExtenso(t time.Time) string {
+	return fmt.Sprintf("%d de %s de %d", t.Day(), meses[t.Month()-1], t.Year())
+}
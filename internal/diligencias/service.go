@@ -3,11 +3,15 @@
 package diligencias
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 
 	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
 )
 
 // ErrNotAssigned é retornado quando o processo não está atribuído ao analista.
@@ -25,18 +29,27 @@ var ErrAlreadySent = errors.New("diligência has already been sent and cannot be
 // rascunho sem itens.
 var ErrDraftEmpty = errors.New("rascunho has no items to send")
 
+// TaskInserter define a interface para inserção de tarefas na fila.
+type TaskInserter interface {
+	InsertTx(ctx context.Context, tx pgx.Tx, args river.JobArgs, opts *river.InsertOpts) (*rivertype.JobInsertResult, error)
+}
+
 // Service gerencia solicitações de diligência em processos de aposentadoria.
 type Service struct {
 	pool   *pgxpool.Pool
 	store  *database.Store
 	logger *slog.Logger
+	queue  TaskInserter
+	cfg    *Config
 }
 
 // New cria uma nova instância de [Service].
-func New(pool *pgxpool.Pool, logger *slog.Logger) *Service {
+func New(pool *pgxpool.Pool, logger *slog.Logger, queue TaskInserter, cfg *Config) *Service {
 	return &Service{
 		pool:   pool,
 		store:  database.New(pool),
 		logger: logger.With(slog.String("service", "diligencias")),
+		queue:  queue,
+		cfg:    cfg,
 	}
 }
@@ -1,17 +1,25 @@
 package diligencias
 
 import (
+	"context"
 	"crypto/rand"
 	"database/sql"
 	"errors"
 	"io"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/automatiza-mg/fila/internal/postgres"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
 )
 
 var ti *postgres.TestInstance
@@ -22,10 +30,23 @@ func TestMain(m *testing.M) {
 	m.Run()
 }
 
+type fakeTaskInserter struct {
+	mu   sync.Mutex
+	jobs []river.JobArgs
+}
+
+func (q *fakeTaskInserter) InsertTx(ctx context.Context, tx pgx.Tx, args river.JobArgs, opts *river.InsertOpts) (*rivertype.JobInsertResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, args)
+	return nil, nil
+}
+
 type testEnv struct {
 	pool     *pgxpool.Pool
 	store    *database.Store
 	service  *Service
+	queue    *fakeTaskInserter
 	pa       *database.ProcessoAposentadoria
 	analista *database.Analista
 }
@@ -36,7 +57,8 @@ func newTestEnv(t *testing.T) *testEnv {
 	pool := ti.NewDatabase(t)
 	store := database.New(pool)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
-	svc := New(pool, logger)
+	queue := &fakeTaskInserter{}
+	svc := New(pool, logger, queue, &Config{})
 
 	pa, analista := seedProcessoEmAnalise(t, store)
 
@@ -44,6 +66,7 @@ func newTestEnv(t *testing.T) *testEnv {
 		pool:     pool,
 		store:    store,
 		service:  svc,
+		queue:    queue,
 		pa:       pa,
 		analista: analista,
 	}
@@ -71,7 +94,7 @@ func seedProcessoEmAnalise(t *testing.T, store *database.Store) (*database.Proce
 		t.Fatal(err)
 	}
 
-	p := &database.Processo{Numero: rand.Text()}
+	p := &database.Processo{Numero: rand.Text(), SeiUnidadeID: rand.Text()}
 	if err := store.SaveProcesso(t.Context(), p); err != nil {
 		t.Fatal(err)
 	}
@@ -237,7 +260,7 @@ func TestSalvarRascunho_ForbidsSentSolicitacao(t *testing.T) {
 	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
 		SolicitacaoID: sd.ID,
 		AnalistaID:    env.analista.UsuarioID,
-		Itens:         []NovoItem{{Tipo: "X", Detalhe: "y"}},
+		Itens:         []NovoItem{{Tipo: "alteracao_dados", Detalhe: "y"}},
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -250,7 +273,7 @@ func TestSalvarRascunho_ForbidsSentSolicitacao(t *testing.T) {
 	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
 		SolicitacaoID: sd.ID,
 		AnalistaID:    env.analista.UsuarioID,
-		Itens:         []NovoItem{{Tipo: "Z"}},
+		Itens:         []NovoItem{{Tipo: "alteracao_dados", Detalhe: "z"}},
 	})
 	if !errors.Is(err, ErrAlreadySent) {
 		t.Fatalf("want ErrAlreadySent, got %v", err)
@@ -271,7 +294,7 @@ func TestSalvarRascunho_NotAssigned(t *testing.T) {
 	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
 		SolicitacaoID: sd.ID,
 		AnalistaID:    outro.UsuarioID,
-		Itens:         []NovoItem{{Tipo: "X"}},
+		Itens:         []NovoItem{{Tipo: "alteracao_dados", Detalhe: "x"}},
 	})
 	if !errors.Is(err, ErrNotAssigned) {
 		t.Fatalf("want ErrNotAssigned, got %v", err)
@@ -311,7 +334,7 @@ func TestDescartarRascunho_ForbidsSent(t *testing.T) {
 	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
 		SolicitacaoID: sd.ID,
 		AnalistaID:    env.analista.UsuarioID,
-		Itens:         []NovoItem{{Tipo: "X"}},
+		Itens:         []NovoItem{{Tipo: "alteracao_dados", Detalhe: "x"}},
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -398,6 +421,23 @@ func TestEnviarDiligencia_Success(t *testing.T) {
 	if !h.Observacao.Valid || h.Observacao.V != "Diligência solicitada" {
 		t.Fatalf("unexpected observacao: %+v", h.Observacao)
 	}
+	if len(env.queue.jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(env.queue.jobs))
+	}
+	args, ok := env.queue.jobs[0].(tasks.EnviarProcessoSEIArgs)
+	if !ok {
+		t.Fatalf("unexpected job: %T", env.queue.jobs[0])
+	}
+	if _, ok := env.queue.jobs[1].(tasks.AtribuirProcessosArgs); !ok {
+		t.Fatalf("expected atribuição job, got %T", env.queue.jobs[1])
+	}
+	p, err := env.store.GetProcesso(t.Context(), pa.ProcessoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.UnidadeOrigem != env.analista.SEIUnidadeID || args.UnidadeDestino != p.SeiUnidadeID {
+		t.Fatalf("unexpected unidades: %+v", args)
+	}
 }
 
 func TestEnviarDiligencia_DraftEmpty(t *testing.T) {
@@ -434,7 +474,7 @@ func TestEnviarDiligencia_NotAssigned(t *testing.T) {
 	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
 		SolicitacaoID: sd.ID,
 		AnalistaID:    env.analista.UsuarioID,
-		Itens:         []NovoItem{{Tipo: "X"}},
+		Itens:         []NovoItem{{Tipo: "alteracao_dados", Detalhe: "x"}},
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -474,7 +514,7 @@ func TestListSolicitacoesEnviadas(t *testing.T) {
 	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
 		SolicitacaoID: rascunho.ID,
 		AnalistaID:    env.analista.UsuarioID,
-		Itens:         []NovoItem{{Tipo: "Algo", Detalhe: "x"}},
+		Itens:         []NovoItem{{Tipo: "alteracao_dados", Detalhe: "x"}},
 	})
 	if err != nil {
 		t.Fatal(err)
@@ -498,3 +538,514 @@ func TestListSolicitacoesEnviadas(t *testing.T) {
 		t.Fatalf("expected 1 item in result, got %d", len(result[0].Itens))
 	}
 }
+
+func TestEstatisticas(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens: []NovoItem{
+			{Tipo: "Documentos Obrigatórios Ausentes", Subcategorias: []string{"FIPA - Dados Cadastrais"}},
+			{Tipo: "Documentos Obrigatórios Ausentes", Subcategorias: []string{"Aposentadoria Voluntária: Requerimento de Aposentadoria"}},
+			{Tipo: "Alteração de Dados Após o Envio", Detalhe: "Algum detalhe"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.EnviarDiligencia(t.Context(), sd.ID, env.analista.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+
+	est, err := env.service.Estatisticas(t.Context(), EstatisticasParams{
+		Agrupamento: AgrupamentoCategoria,
+		Intervalo:   IntervaloMes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(est.Tendencia) != 2 {
+		t.Fatalf("expected 2 tendencia entries, got %d", len(est.Tendencia))
+	}
+	if len(est.Totais) != 2 {
+		t.Fatalf("expected 2 totais, got %d", len(est.Totais))
+	}
+	if est.Totais[0].Chave != "Documentos Obrigatórios Ausentes" || est.Totais[0].Itens != 2 || est.Totais[0].Solicitacoes != 1 {
+		t.Fatalf("unexpected first total: %+v", est.Totais[0])
+	}
+
+	var buf strings.Builder
+	if err := est.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Fatalf("expected 3 CSV lines, got %d:\n%s", lines, buf.String())
+	}
+}
+
+func TestSalvarRascunho_ValidaCatalogo(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens: []NovoItem{
+			{Tipo: "inexistente", Detalhe: "x"},
+			{Tipo: "documentos_ausentes", Subcategorias: []string{"outro"}},
+			{Tipo: "divergencia_sisap"},
+		},
+	})
+	var invalidos *ItensInvalidosError
+	if !errors.As(err, &invalidos) {
+		t.Fatalf("want ItensInvalidosError, got %v", err)
+	}
+	for _, campo := range []string{"itens[0].tipo", "itens[1].subcategorias", "itens[2].detalhe"} {
+		if _, ok := invalidos.FieldErrors[campo]; !ok {
+			t.Errorf("expected error for %s, got %v", campo, invalidos.FieldErrors)
+		}
+	}
+
+	got, err := env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens: []NovoItem{
+			{Tipo: "baixa_nitidez", Subcategorias: []string{"requerimento_voluntaria"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	it := got.Itens[0]
+	if it.Tipo != "Documento com Baixa Nitidez" || it.CategoriaID == nil {
+		t.Fatalf("unexpected item: %+v", it)
+	}
+	if len(it.Subcategorias) != 1 || it.Subcategorias[0] != "Aposentadoria Voluntária: Requerimento de Aposentadoria" {
+		t.Fatalf("unexpected subcategorias: %v", it.Subcategorias)
+	}
+}
+
+// TestSalvarRascunho_NomesInterface garante que os nomes de categorias e
+// subcategorias enviados pela interface são aceitos pelo catálogo.
+func TestSalvarRascunho_NomesInterface(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itens := []NovoItem{
+		{Tipo: "Documentos Obrigatórios Ausentes", Subcategorias: []string{
+			"Dois relatórios de conferência extraídos da Fipa Eletrônica/SISAP: Dados cadastrais e Dados Funcionais",
+			"Aposentadoria Voluntária: Requerimento de Aposentadoria",
+			"Aposentadoria por incapacidade permanente: Laudo Médico Oficial",
+			"Aposentadoria Compulsória: Cópia autenticada da certidão de nascimento ou casamento",
+			"FIPA - Tempo Averbado",
+			"FIPA - Dados Cadastrais",
+			"Declaração do efetivo exercício expedido pelo órgão que recebeu o servidor na situação de adjunção ou disposição",
+		}},
+		{Tipo: "Documentos com Informações Incompletas/Faltantes", Detalhe: "Requerimento sem assinatura"},
+		{Tipo: "Documentos com Erros ou Incompatíveis com o Processo Analisado", Detalhe: "x"},
+		{Tipo: "Divergências de Informações entre Processo e SISAP", Detalhe: "x"},
+		{Tipo: "Alteração de Dados Após o Envio", Detalhe: "x"},
+		{Tipo: "Documento com Baixa Nitidez", Subcategorias: []string{
+			"Cópia da certidão de nascimento ou casamento ou carteira de identidade ou outro documento público que comprove o nome completo e a idade do(a) servidor(a)",
+		}},
+		{Tipo: "Inconsistência na Análise do Órgão de Origem", Detalhe: "x"},
+	}
+	got, err := env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens:         itens,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Itens) != len(itens) {
+		t.Fatalf("expected %d itens, got %d", len(itens), len(got.Itens))
+	}
+	for i, it := range got.Itens {
+		if it.Tipo != itens[i].Tipo || it.CategoriaID == nil {
+			t.Errorf("unexpected item %d: %+v", i, it)
+		}
+	}
+}
+
+func TestCreateCategoria_NovaVersao(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	cat, err := env.service.CreateCategoria(t.Context(), CreateCategoriaParams{
+		Categoria: &CategoriaDiligencia{
+			Codigo:       "baixa_nitidez",
+			Nome:         "Documento com Baixa Nitidez",
+			ListaFechada: true,
+			Subcategorias: []*SubcategoriaDiligencia{
+				{Codigo: "laudo", Nome: "Laudo Médico Oficial"},
+			},
+		},
+		UsuarioID: env.analista.UsuarioID,
+		Ativar:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cat.Versao != 2 || !cat.Ativa || len(cat.Subcategorias) != 1 {
+		t.Fatalf("unexpected categoria: %+v", cat)
+	}
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Subcategorias da versão anterior deixam de ser aceitas.
+	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens:         []NovoItem{{Tipo: "baixa_nitidez", Subcategorias: []string{"requerimento_voluntaria"}}},
+	})
+	var invalidos *ItensInvalidosError
+	if !errors.As(err, &invalidos) {
+		t.Fatalf("want ItensInvalidosError, got %v", err)
+	}
+
+	got, err := env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens:         []NovoItem{{Tipo: "baixa_nitidez", Subcategorias: []string{"laudo"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.Itens[0].CategoriaID != cat.ID {
+		t.Fatalf("expected categoria %d, got %d", cat.ID, *got.Itens[0].CategoriaID)
+	}
+}
+
+func TestEnviarDiligencia_Oficio(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+	env.service.cfg = &Config{SerieOficio: "42"}
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens: []NovoItem{
+			{Tipo: "documentos_ausentes", Subcategorias: []string{"fipa_dados_cadastrais"}},
+			{Tipo: "alteracao_dados", Detalhe: "Benefício <concedido> após o envio"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent, err := env.service.EnviarDiligencia(t.Context(), sd.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"Documentos Obrigatórios Ausentes",
+		"FIPA - Dados Cadastrais",
+		"Benefício &lt;concedido&gt; após o envio",
+	} {
+		if !strings.Contains(sent.Oficio, s) {
+			t.Errorf("expected ofício to contain %q", s)
+		}
+	}
+	if sent.DocumentoSEI != nil {
+		t.Fatalf("expected no documento before the job runs, got %q", *sent.DocumentoSEI)
+	}
+
+	if len(env.queue.jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(env.queue.jobs))
+	}
+	args, ok := env.queue.jobs[0].(tasks.IncluirOficioDiligenciaArgs)
+	if !ok {
+		t.Fatalf("unexpected job: %T", env.queue.jobs[0])
+	}
+	if _, ok := env.queue.jobs[1].(tasks.AtribuirProcessosArgs); !ok {
+		t.Fatalf("expected atribuição job, got %T", env.queue.jobs[1])
+	}
+	if args.SolicitacaoDiligenciaID != sd.ID || args.IdSerie != "42" || args.Unidade != env.analista.SEIUnidadeID {
+		t.Fatalf("unexpected args: %+v", args)
+	}
+	if args.Tramitacao == nil || args.Tramitacao.ProcessoAposentadoriaID != env.pa.ID {
+		t.Fatalf("expected tramitação after the ofício, got %+v", args.Tramitacao)
+	}
+}
+
+func TestEnviarDiligencia_NotificaContatos(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	_, err := env.pool.Exec(t.Context(), `UPDATE processos SET sei_unidade_sigla = 'SEE/RH' WHERE id = $1`, env.pa.ProcessoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, email := range []string{"rh1@example.com", "rh2@example.com"} {
+		_, err := env.service.CreateContato(t.Context(), ContatoParams{UnidadeSigla: "SEE/RH", Email: email})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := env.service.CreateContato(t.Context(), ContatoParams{UnidadeSigla: "SEF/RH", Email: "rh3@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens: []NovoItem{
+			{Tipo: "documentos_ausentes", Subcategorias: []string{"fipa_dados_cadastrais"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.EnviarDiligencia(t.Context(), sd.ID, env.analista.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+
+	var email *tasks.SendEmailArgs
+	for _, job := range env.queue.jobs {
+		if args, ok := job.(tasks.SendEmailArgs); ok {
+			email = &args
+		}
+	}
+	if email == nil {
+		t.Fatal("expected email job")
+	}
+	if len(email.Email.To) != 2 || email.Email.To[0] != "rh1@example.com" {
+		t.Fatalf("unexpected destinatários: %v", email.Email.To)
+	}
+	if !strings.Contains(email.Email.Text, "FIPA - Dados Cadastrais") {
+		t.Fatalf("expected item in email, got:\n%s", email.Email.Text)
+	}
+}
+
+func TestEnviarDiligencia_PrazoResposta(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+	env.service.cfg = &Config{PrazoResposta: 30}
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens: []NovoItem{
+			{Tipo: "documentos_ausentes", Subcategorias: []string{"fipa_dados_cadastrais"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent, err := env.service.EnviarDiligencia(t.Context(), sd.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := prazoResposta(*sent.EnviadaEm, 30)
+	if sent.PrazoResposta == nil || !sent.PrazoResposta.Equal(expected) {
+		t.Fatalf("expected prazo %v, got %v", expected, sent.PrazoResposta)
+	}
+	if !strings.Contains(sent.Oficio, expected.Format("02/01/2006")) {
+		t.Fatal("expected prazo in ofício")
+	}
+
+	read, err := env.store.GetSolicitacaoDiligencia(t.Context(), sd.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !read.PrazoResposta.Valid || !read.PrazoResposta.V.Equal(expected) {
+		t.Fatalf("expected stored prazo %v, got %+v", expected, read.PrazoResposta)
+	}
+}
+
+func TestPrazoResposta(t *testing.T) {
+	// 02h UTC ainda é o dia anterior no horário de Brasília.
+	enviadaEm := time.Date(2026, time.March, 10, 2, 0, 0, 0, time.UTC)
+	expected := time.Date(2026, time.April, 8, 0, 0, 0, 0, time.UTC)
+	if got := prazoResposta(enviadaEm, 30); !got.Equal(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// enviarERetornar envia uma diligência com os itens informados e devolve o
+// processo ao analista, simulando o retorno da diligência.
+func enviarERetornar(t *testing.T, env *testEnv, itens []NovoItem) *SolicitacaoDiligencia {
+	t.Helper()
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.SalvarRascunho(t.Context(), SalvarRascunhoParams{
+		SolicitacaoID: sd.ID,
+		AnalistaID:    env.analista.UsuarioID,
+		Itens:         itens,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sent, err := env.service.EnviarDiligencia(t.Context(), sd.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pa, err := env.store.GetProcessoAposentadoria(t.Context(), env.pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa.Status = database.StatusProcessoEmAnalise
+	pa.AnalistaID = sql.Null[int64]{V: env.analista.UsuarioID, Valid: true}
+	if err := env.store.UpdateProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+
+	return sent
+}
+
+func TestGetOrCreateRascunho_CopiaDiligenciaAnterior(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	anterior := enviarERetornar(t, env, []NovoItem{
+		{Tipo: "documentos_ausentes", Subcategorias: []string{"fipa_dados_cadastrais"}},
+		{Tipo: "alteracao_dados", Detalhe: "Benefício concedido após o envio"},
+	})
+
+	sd, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sd.Itens) != 2 {
+		t.Fatalf("expected 2 itens, got %d", len(sd.Itens))
+	}
+	for i, it := range sd.Itens {
+		if it.ItemOrigemID == nil || *it.ItemOrigemID != anterior.Itens[i].ID {
+			t.Fatalf("expected item %d to reference %d, got %v", i, anterior.Itens[i].ID, it.ItemOrigemID)
+		}
+		if it.Tipo != anterior.Itens[i].Tipo || it.Detalhe != anterior.Itens[i].Detalhe {
+			t.Fatalf("unexpected item copy: %+v", it)
+		}
+	}
+}
+
+func TestRegistrarAtendimento(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	anterior := enviarERetornar(t, env, []NovoItem{
+		{Tipo: "documentos_ausentes", Subcategorias: []string{"fipa_dados_cadastrais"}},
+		{Tipo: "alteracao_dados", Detalhe: "Benefício concedido após o envio"},
+	})
+	atendido, pendente := anterior.Itens[0], anterior.Itens[1]
+
+	rascunho, err := env.service.GetOrCreateRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rascunho.Itens) != 2 {
+		t.Fatalf("expected 2 itens, got %d", len(rascunho.Itens))
+	}
+
+	sd, err := env.service.RegistrarAtendimento(t.Context(), RegistrarAtendimentoParams{
+		ProcessoAposentadoriaID: env.pa.ID,
+		AnalistaID:              env.analista.UsuarioID,
+		Itens: []ResultadoItem{
+			{ItemID: atendido.ID, Atendido: true},
+			{ItemID: pendente.ID, Atendido: false, Observacao: "Comprovação não enviada"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sd.ID != anterior.ID {
+		t.Fatalf("expected solicitação %d, got %d", anterior.ID, sd.ID)
+	}
+	a := sd.Itens[0].Atendimento
+	if a == nil || !a.Atendido || a.RegistradoPor == nil || *a.RegistradoPor != env.analista.UsuarioID {
+		t.Fatalf("unexpected atendimento: %+v", a)
+	}
+	p := sd.Itens[1].Atendimento
+	if p == nil || p.Atendido || p.Observacao != "Comprovação não enviada" {
+		t.Fatalf("unexpected atendimento: %+v", p)
+	}
+
+	// O rascunho mantém apenas o item não atendido.
+	rascunho, err = env.service.GetRascunho(t.Context(), env.pa.ID, env.analista.UsuarioID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rascunho.Itens) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(rascunho.Itens))
+	}
+	if rascunho.Itens[0].ItemOrigemID == nil || *rascunho.Itens[0].ItemOrigemID != pendente.ID {
+		t.Fatalf("expected pending item in rascunho, got %+v", rascunho.Itens[0])
+	}
+
+	ee, err := env.store.ListEventosProcesso(t.Context(), env.pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := slices.IndexFunc(ee, func(e *database.EventoProcesso) bool {
+		return e.Tipo == database.EventoAtendimentoDiligencia &&
+			e.Descricao == "Atendimento da diligência verificado: 1 de 2 itens atendidos"
+	})
+	if idx < 0 {
+		t.Fatal("expected evento for the atendimento")
+	}
+}
+
+func TestRegistrarAtendimento_ItemInvalido(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	enviarERetornar(t, env, []NovoItem{
+		{Tipo: "alteracao_dados", Detalhe: "Benefício concedido após o envio"},
+	})
+
+	_, err := env.service.RegistrarAtendimento(t.Context(), RegistrarAtendimentoParams{
+		ProcessoAposentadoriaID: env.pa.ID,
+		AnalistaID:              env.analista.UsuarioID,
+		Itens:                   []ResultadoItem{{ItemID: 999999, Atendido: true}},
+	})
+	var invalidos *ItensInvalidosError
+	if !errors.As(err, &invalidos) {
+		t.Fatalf("expected ItensInvalidosError, got %v", err)
+	}
+
+	_, err = env.service.RegistrarAtendimento(t.Context(), RegistrarAtendimentoParams{
+		ProcessoAposentadoriaID: env.pa.ID,
+		AnalistaID:              env.analista.UsuarioID + 1,
+	})
+	if !errors.Is(err, ErrNotAssigned) {
+		t.Fatalf("expected ErrNotAssigned, got %v", err)
+	}
+}
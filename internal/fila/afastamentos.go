@@ -0,0 +1,175 @@
+package fila
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+// AplicarAfastamentos inicia os afastamentos programados cujo início já foi
+// alcançado e encerra os que chegaram ao fim, retornando a quantidade de
+// afastamentos iniciados e encerrados.
+func (s *Service) AplicarAfastamentos(ctx context.Context) (iniciados, encerrados int, err error) {
+	for {
+		ok, err := s.iniciarAfastamentoProgramado(ctx)
+		if err != nil {
+			return iniciados, encerrados, err
+		}
+		if !ok {
+			break
+		}
+		iniciados++
+	}
+
+	for {
+		ok, err := s.encerrarAfastamentoProgramado(ctx)
+		if err != nil {
+			return iniciados, encerrados, err
+		}
+		if !ok {
+			break
+		}
+		encerrados++
+	}
+
+	return iniciados, encerrados, nil
+}
+
+// iniciarAfastamentoProgramado marca como afastado o analista de um
+// afastamento programado cujo início já foi alcançado, devolvendo para a fila
+// os processos que ele tinha em análise. Retorna false se não houver
+// afastamento a iniciar.
+func (s *Service) iniciarAfastamentoProgramado(ctx context.Context) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+	agora := time.Now()
+
+	ap, err := store.GetAfastamentoProgramadoAIniciar(ctx, agora)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro ao obter afastamento programado: %w", err)
+	}
+
+	analista, err := store.GetAnalista(ctx, ap.AnalistaID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao obter dados do analista: %w", err)
+	}
+	nome, err := store.GetNomeAnalista(ctx, analista.UsuarioID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao obter nome do analista: %w", err)
+	}
+
+	analista.Afastado = true
+	if err := store.UpdateAnalista(ctx, analista); err != nil {
+		return false, fmt.Errorf("erro ao afastar analista: %w", err)
+	}
+	// O afastamento é registrado a partir do início programado, mesmo que o
+	// job execute depois, para que o relatório de produtividade reflita o
+	// período informado.
+	if err := store.IniciarAfastamentoAnalistaEm(ctx, analista.UsuarioID, ap.Inicio); err != nil {
+		return false, fmt.Errorf("erro ao registrar afastamento: %w", err)
+	}
+
+	paa, err := store.ListProcessosAtribuidos(ctx, analista.UsuarioID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao listar processos do analista: %w", err)
+	}
+	observacao := fmt.Sprintf("Processo devolvido à fila pelo afastamento programado do analista %s", nome)
+	if ap.Motivo != "" {
+		observacao += ": " + ap.Motivo
+	}
+	for _, pa := range paa {
+		if err := s.devolverProcesso(ctx, tx, store, pa, nil, observacao); err != nil {
+			return false, fmt.Errorf("erro ao devolver processo à fila: %w", err)
+		}
+	}
+
+	ap.IniciadoEm = sql.Null[time.Time]{V: agora, Valid: true}
+	if err := store.UpdateAfastamentoProgramado(ctx, ap); err != nil {
+		return false, fmt.Errorf("erro ao atualizar afastamento programado: %w", err)
+	}
+
+	if len(paa) > 0 {
+		if err := s.agendarAtribuicao(ctx, tx); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return true, nil
+}
+
+// encerrarAfastamentoProgramado encerra um afastamento programado cujo fim já
+// foi alcançado, fazendo o analista retornar caso não haja outro afastamento
+// programado em curso nem um afastamento manual em aberto. Retorna false se não houver afastamento a encerrar.
+func (s *Service) encerrarAfastamentoProgramado(ctx context.Context) (bool, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+	agora := time.Now()
+
+	ap, err := store.GetAfastamentoProgramadoAEncerrar(ctx, agora)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro ao obter afastamento programado: %w", err)
+	}
+
+	ap.EncerradoEm = sql.Null[time.Time]{V: agora, Valid: true}
+	if err := store.UpdateAfastamentoProgramado(ctx, ap); err != nil {
+		return false, fmt.Errorf("erro ao atualizar afastamento programado: %w", err)
+	}
+
+	// Um afastamento programado imediatamente seguinte ou um afastamento
+	// manual em aberto mantêm o analista afastado.
+	emCurso, err := store.HasAfastamentoProgramadoEmCurso(ctx, ap.AnalistaID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar afastamentos em curso: %w", err)
+	}
+	manual, err := store.HasAfastamentoManualAberto(ctx, ap.AnalistaID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar afastamento manual: %w", err)
+	}
+	if !emCurso && !manual {
+		analista, err := store.GetAnalista(ctx, ap.AnalistaID)
+		if err != nil {
+			return false, fmt.Errorf("erro ao obter dados do analista: %w", err)
+		}
+
+		analista.Afastado = false
+		if err := store.UpdateAnalista(ctx, analista); err != nil {
+			return false, fmt.Errorf("erro ao retornar analista: %w", err)
+		}
+		if err := store.EncerrarAfastamentoAnalistaEm(ctx, analista.UsuarioID, ap.Fim); err != nil {
+			return false, fmt.Errorf("erro ao encerrar afastamento: %w", err)
+		}
+		if err := s.agendarAtribuicao(ctx, tx); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return true, nil
+}
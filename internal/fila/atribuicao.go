@@ -10,32 +10,82 @@ import (
 
 	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/automatiza-mg/fila/internal/logging"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/jackc/pgx/v5"
 )
 
-// Atribui um processo de aposentadoria a um analista disponível.
-func (s *Service) assignProcessoAposentadoria(ctx context.Context) error {
+// ExpiracaoExclusaoTemporaria é o tempo após o qual a exclusão temporária do
+// analista que devolveu um processo deixa de valer, caso a atribuição
+// agendada pela devolução não a remova.
+const ExpiracaoExclusaoTemporaria = time.Hour
+
+// AtribuirProcessos atribui processos aos analistas disponíveis até que não
+// haja mais analistas livres ou processos aguardando análise, retornando a
+// quantidade de processos atribuídos. As exclusões temporárias expiradas são
+// removidas antes da atribuição.
+func (s *Service) AtribuirProcessos(ctx context.Context) (int, error) {
+	if _, err := s.store.DeleteExclusoesTemporariasExpiradas(ctx); err != nil {
+		return 0, err
+	}
+
+	limite := time.Now().Add(-s.cfg.EsperaFallback)
+
+	var n int
+	for {
+		ok, err := s.assignProcessoAposentadoria(ctx, limite)
+		if err != nil {
+			return n, err
+		}
+		if !ok {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// agendarAtribuicao agenda a atribuição de processos na transação informada,
+// para que seja executada assim que a transação for confirmada.
+func (s *Service) agendarAtribuicao(ctx context.Context, tx pgx.Tx) error {
+	_, err := s.queue.InsertTx(ctx, tx, tasks.AtribuirProcessosArgs{}, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao agendar atribuição de processos: %w", err)
+	}
+	return nil
+}
+
+// LiberarExclusaoTemporaria remove a exclusão temporária do analista que
+// devolveu o processo, que volta a poder recebê-lo.
+func (s *Service) LiberarExclusaoTemporaria(ctx context.Context, paID, analistaID int64) error {
+	return s.store.DeleteExclusaoTemporaria(ctx, paID, analistaID)
+}
+
+// Atribui um processo de aposentadoria a um analista disponível e elegível.
+// Processos aguardando desde antes de limite podem ser atribuídos pelas regras
+// de fallback. Retorna false se não houver analista disponível ou processo a
+// ser atribuído.
+func (s *Service) assignProcessoAposentadoria(ctx context.Context, limite time.Time) (bool, error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("falha ao iniciar transação: %w", err)
+		return false, fmt.Errorf("falha ao iniciar transação: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
 	store := s.store.WithTx(tx)
 
-	analistaID, err := store.GetAnalistaDisponivel(ctx)
+	analistaID, err := store.GetAnalistaDisponivel(ctx, limite)
 	if err != nil {
 		if errors.Is(err, database.ErrNotFound) {
-			return nil
+			return false, nil
 		}
-		return fmt.Errorf("erro ao obter analista disponível: %w", err)
+		return false, fmt.Errorf("erro ao obter analista disponível: %w", err)
 	}
 
-	processo, err := store.GetProcessoPrioriatario(ctx, analistaID)
+	processo, err := store.GetProcessoPrioriatario(ctx, analistaID, limite)
 	if err != nil {
 		if errors.Is(err, database.ErrNotFound) {
-			return nil
+			return false, nil
 		}
-		return fmt.Errorf("erro ao obter processo prioritário: %w", err)
+		return false, fmt.Errorf("erro ao obter processo prioritário: %w", err)
 	}
 
 	statusAnterior := processo.Status
@@ -44,37 +94,59 @@ func (s *Service) assignProcessoAposentadoria(ctx context.Context) error {
 	processo.Status = database.StatusProcessoEmAnalise
 
 	if err := store.UpdateProcessoAposentadoria(ctx, processo); err != nil {
-		return fmt.Errorf("erro ao atualizar processo: %w", err)
+		return false, fmt.Errorf("erro ao atualizar processo: %w", err)
+	}
+
+	// A fixação do processo, se houver, é consumida pela atribuição.
+	fixado, err := store.DeleteProcessoFixado(ctx, processo.ID)
+	if err != nil {
+		return false, fmt.Errorf("erro ao remover fixação do processo: %w", err)
+	}
+	observacao := "Processo atribuído para análise"
+	if fixado {
+		observacao = "Processo fixado atribuído para análise"
 	}
 
 	err = s.saveHistorico(ctx, store, saveHistoricoParams{
 		ProcessoAposentadoriaID: processo.ID,
 		StatusAnterior:          &statusAnterior,
 		StatusNovo:              database.StatusProcessoEmAnalise,
-		Observacao:              "Processo atribuído para análise",
+		Observacao:              observacao,
 	})
 	if err != nil {
-		return fmt.Errorf("erro ao salvar histórico: %w", err)
+		return false, fmt.Errorf("erro ao salvar histórico: %w", err)
 	}
 
 	analista, err := store.GetAnalista(ctx, analistaID)
 	if err != nil {
-		return fmt.Errorf("erro ao obter dados do analista: %w", err)
+		return false, fmt.Errorf("erro ao obter dados do analista: %w", err)
 	}
 
 	analista.UltimaAtribuicaoEm = sql.Null[time.Time]{Valid: true, V: time.Now()}
 	if err := store.UpdateAnalista(ctx, analista); err != nil {
-		return fmt.Errorf("erro ao atualizar timestamp do analista: %w", err)
+		return false, fmt.Errorf("erro ao atualizar timestamp do analista: %w", err)
+	}
+
+	_, err = s.queue.InsertTx(ctx, tx, tasks.EnviarProcessoSEIArgs{
+		ProcessoAposentadoriaID: processo.ID,
+		UnidadeOrigem:           s.cfg.UnidadeDCCTA,
+		UnidadeDestino:          analista.SEIUnidadeID,
+		Motivo:                  fmt.Sprintf("atribuição ao analista da unidade %s", analista.SEIUnidadeSigla),
+	}, nil)
+	if err != nil {
+		return false, fmt.Errorf("erro ao agendar envio do processo no SEI: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("erro ao confirmar transação: %w", err)
+		return false, fmt.Errorf("erro ao confirmar transação: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 // StartAtribuicaoWorker inicia uma goroutine que atribui processos a analistas periodicamente.
+// A atribuição é disparada pelos eventos da fila e a execução periódica serve
+// apenas como garantia caso algum evento não tenha agendado a atribuição.
 // A goroutine será cancelada quando o contexto for fechado.
 func (s *Service) StartAtribuicaoWorker(ctx context.Context, interval time.Duration) {
 	go func() {
@@ -89,7 +161,7 @@ func (s *Service) StartAtribuicaoWorker(ctx context.Context, interval time.Durat
 				logger.Debug("Encerrando worker de atribuição de processos")
 				return
 			case <-ticker.C:
-				if err := s.assignProcessoAposentadoria(ctx); err != nil {
+				if _, err := s.AtribuirProcessos(ctx); err != nil {
 					logger.Error("Erro ao atribuir processo",
 						slog.String("error", err.Error()),
 					)
@@ -0,0 +1,28 @@
+package fila
+
+import (
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/prazos"
+)
+
+type Config struct {
+	// Unidade do SEI responsável pela fila, de onde os processos são
+	// enviados aos analistas no momento da atribuição.
+	UnidadeDCCTA string `env:"UNIDADE_SEPLAG_DCCTA,notEmpty"`
+	// Unidade do SEI que recebe os processos concluídos. Se vazia, os
+	// processos não são tramitados na conclusão.
+	UnidadeConclusao string `env:"UNIDADE_SEI_CONCLUSAO"`
+	// Intervalo da atribuição periódica de processos. A atribuição é
+	// disparada pelos eventos da fila, e a execução periódica apenas cobre
+	// eventos que não tenham agendado a atribuição.
+	AtribuicaoIntervalo time.Duration `env:"FILA_ATRIBUICAO_INTERVALO" envDefault:"5m"`
+	// Tempo de espera após o qual um processo sem analista elegível livre
+	// pode ser atribuído a analistas SENIOR sem as competências exigidas.
+	EsperaFallback time.Duration `env:"FILA_ESPERA_FALLBACK" envDefault:"24h"`
+	// Intervalo da verificação dos afastamentos programados dos analistas,
+	// que define o atraso máximo para o início e o fim de cada afastamento.
+	AfastamentosIntervalo time.Duration `env:"FILA_AFASTAMENTOS_INTERVALO" envDefault:"15m"`
+	// Prazos legais dos processos de aposentadoria.
+	Prazos prazos.Config
+}
@@ -0,0 +1,315 @@
+package fila
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/aposentadoria"
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/automatiza-mg/fila/internal/validator"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+// ErrReprocessamentoEmAndamento é o erro retornado ao solicitar o
+// reprocessamento de um processo cujo download ainda não terminou.
+var ErrReprocessamentoEmAndamento = errors.New("reprocessamento já em andamento")
+
+// DadosIncompletosError é retornado quando a correção de um processo com
+// leitura inválida não preenche os dados obrigatórios do requerente.
+// FieldErrors segue o formato de [validator.Validator].
+type DadosIncompletosError struct {
+	FieldErrors map[string]string
+}
+
+func (e *DadosIncompletosError) Error() string {
+	return "required requerente data is missing"
+}
+
+// AlteracaoCampo é a alteração de um dos dados do requerente de um processo de
+// aposentadoria.
+type AlteracaoCampo struct {
+	Campo         string    `json:"campo"`
+	ValorAnterior string    `json:"valor_anterior"`
+	ValorNovo     string    `json:"valor_novo"`
+	Origem        string    `json:"origem"`
+	Motivo        string    `json:"motivo"`
+	UsuarioID     *int64    `json:"usuario_id"`
+	Usuario       *string   `json:"usuario"`
+	CriadoEm      time.Time `json:"criado_em"`
+}
+
+// ListAlteracoesCampos retorna a trilha de auditoria das alterações dos dados
+// do requerente de um processo de aposentadoria.
+func (s *Service) ListAlteracoesCampos(ctx context.Context, paID int64) ([]*AlteracaoCampo, error) {
+	aa, err := s.store.ListAlteracoesCamposProcesso(ctx, paID)
+	if err != nil {
+		return nil, err
+	}
+
+	alteracoes := make([]*AlteracaoCampo, len(aa))
+	for i, a := range aa {
+		alteracoes[i] = &AlteracaoCampo{
+			Campo:         a.Campo,
+			ValorAnterior: a.ValorAnterior,
+			ValorNovo:     a.ValorNovo,
+			Origem:        a.Origem,
+			Motivo:        a.Motivo,
+			UsuarioID:     database.Ptr(a.UsuarioID),
+			Usuario:       database.Ptr(a.Usuario),
+			CriadoEm:      a.CriadoEm,
+		}
+	}
+
+	return alteracoes, nil
+}
+
+// ReprocessarProcesso solicita uma nova extração dos documentos de um processo
+// com leitura inválida. Os documentos são baixados novamente e, ao fim da
+// análise de IA, o processo volta para a fila com os dados atualizados.
+// Retorna [ErrInvalidStatus] se o processo não estiver com leitura inválida e
+// [ErrReprocessamentoEmAndamento] se os documentos já estiverem sendo baixados
+// novamente.
+func (s *Service) ReprocessarProcesso(ctx context.Context, paID, gestorID int64, motivo string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, paID)
+	if err != nil {
+		return err
+	}
+	if pa.Status != database.StatusProcessoLeituraInvalid {
+		return ErrInvalidStatus
+	}
+
+	descricao := "Reprocessamento solicitado"
+	if motivo != "" {
+		descricao += ": " + motivo
+	}
+	if err := s.saveEvento(ctx, store, pa.ID, database.EventoReprocessamento, gestorID, descricao); err != nil {
+		return err
+	}
+
+	// O download é único por processo enquanto não terminar, para que
+	// solicitações repetidas não dupliquem a extração.
+	res, err := s.queue.InsertTx(ctx, tx, tasks.DownloadProcessoArgs{
+		ProcessoID:    pa.ProcessoID,
+		SolicitadoPor: gestorID,
+	}, &river.InsertOpts{
+		UniqueOpts: river.UniqueOpts{
+			ByArgs: true,
+			ByState: []rivertype.JobState{
+				rivertype.JobStateAvailable,
+				rivertype.JobStatePending,
+				rivertype.JobStateRetryable,
+				rivertype.JobStateRunning,
+				rivertype.JobStateScheduled,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	if res.UniqueSkippedAsDuplicate {
+		return ErrReprocessamentoEmAndamento
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CorrecaoDados são as correções dos dados do requerente de um processo de
+// aposentadoria. Os campos não informados mantêm o valor atual do processo.
+type CorrecaoDados struct {
+	CPF              *string
+	DataNascimento   *time.Time
+	DataRequerimento *time.Time
+	Invalidez        *bool
+	Judicial         *bool
+}
+
+// aplicar retorna os dados do processo com as correções informadas.
+func (c CorrecaoDados) aplicar(pa *database.ProcessoAposentadoria) aposentadoria.DadosRequerente {
+	dados := aposentadoria.DadosRequerenteProcesso(pa)
+	if c.CPF != nil {
+		dados.CPF = strings.NewReplacer(".", "", "-", "").Replace(*c.CPF)
+	}
+	if c.DataNascimento != nil {
+		dados.DataNascimento = *c.DataNascimento
+	}
+	if c.DataRequerimento != nil {
+		dados.DataRequerimento = *c.DataRequerimento
+	}
+	if c.Invalidez != nil {
+		dados.Invalidez = *c.Invalidez
+	}
+	if c.Judicial != nil {
+		dados.Judicial = *c.Judicial
+	}
+	return dados
+}
+
+// validarObrigatorios verifica se os dados usados no cálculo do score estão
+// preenchidos, retornando [*DadosIncompletosError] caso contrário.
+func validarObrigatorios(dados aposentadoria.DadosRequerente) error {
+	var v validator.Validator
+	v.Check(dados.CPF != "", "cpf_requerente", "Campo obrigatório")
+	v.Check(!dados.DataNascimento.IsZero(), "data_nascimento_requerente", "Campo obrigatório")
+	v.Check(!dados.DataRequerimento.IsZero(), "data_requerimento", "Campo obrigatório")
+	if !v.Valid() {
+		return &DadosIncompletosError{FieldErrors: v.FieldErrors}
+	}
+	return nil
+}
+
+// observacaoCorrecao descreve no histórico os campos alterados por uma
+// correção manual.
+func observacaoCorrecao(alteracoes []*database.AlteracaoCampoProcesso, motivo string) string {
+	campos := make([]string, len(alteracoes))
+	for i, a := range alteracoes {
+		campos[i] = a.Campo
+	}
+	observacao := "Dados corrigidos manualmente"
+	if len(campos) > 0 {
+		observacao += " (" + strings.Join(campos, ", ") + ")"
+	}
+	if motivo != "" {
+		observacao += ": " + motivo
+	}
+	return observacao
+}
+
+type CorrigirProcessoParams struct {
+	ProcessoID int64
+	GestorID   int64
+	Motivo     string
+	Dados      CorrecaoDados
+}
+
+// CorrigirLeituraInvalida corrige manualmente os dados do requerente de um
+// processo com leitura inválida, devolvendo-o para a fila como
+// ANALISE_PENDENTE com o score recalculado. Cada campo alterado é registrado
+// na trilha de auditoria. Retorna [ErrInvalidStatus] se o processo não estiver
+// com leitura inválida e [*DadosIncompletosError] se o CPF, a data de
+// nascimento ou a data de requerimento continuarem sem valor.
+func (s *Service) CorrigirLeituraInvalida(ctx context.Context, params CorrigirProcessoParams) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoriaForUpdate(ctx, params.ProcessoID)
+	if err != nil {
+		return err
+	}
+	if pa.Status != database.StatusProcessoLeituraInvalid {
+		return ErrInvalidStatus
+	}
+
+	dados := params.Dados.aplicar(pa)
+	if err := validarObrigatorios(dados); err != nil {
+		return err
+	}
+
+	usuarioID := sql.Null[int64]{V: params.GestorID, Valid: true}
+	alteracoes, err := aposentadoria.AtualizarDados(ctx, store, pa, dados, database.OrigemAlteracaoManual, params.Motivo, usuarioID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.saveHistorico(ctx, store, saveHistoricoParams{
+		ProcessoAposentadoriaID: pa.ID,
+		StatusAnterior:          &pa.Status,
+		StatusNovo:              database.StatusProcessoAnalisePendente,
+		UsuarioID:               &params.GestorID,
+		Observacao:              observacaoCorrecao(alteracoes, params.Motivo),
+	}); err != nil {
+		return err
+	}
+
+	pa.Status = database.StatusProcessoAnalisePendente
+	if err := s.recalcularScore(ctx, store, pa); err != nil {
+		return err
+	}
+
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type CorrigirDadosParams struct {
+	ProcessoID int64
+	UsuarioID  int64
+	// Analista indica que a correção é feita pelo analista, que só pode
+	// corrigir os processos atribuídos a ele.
+	Analista bool
+	Motivo   string
+	Dados    CorrecaoDados
+}
+
+// CorrigirDados corrige os dados do requerente de um processo de
+// aposentadoria sem alterar o seu status. Cada campo alterado é registrado,
+// com o motivo, apenas na trilha de auditoria, e o score é recalculado.
+// Retorna [ErrNotAssigned] caso o analista não seja o responsável pelo
+// processo e [ErrInvalidStatus] caso o processo já esteja concluído ou com
+// leitura inválida.
+func (s *Service) CorrigirDados(ctx context.Context, params CorrigirDadosParams) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoriaForUpdate(ctx, params.ProcessoID)
+	if err != nil {
+		return err
+	}
+	if params.Analista && (!pa.AnalistaID.Valid || pa.AnalistaID.V != params.UsuarioID) {
+		return ErrNotAssigned
+	}
+	// Processos com leitura inválida são corrigidos por
+	// [Service.CorrigirLeituraInvalida], que os devolve para a fila.
+	if pa.Status == database.StatusProcessoConcluido || pa.Status == database.StatusProcessoLeituraInvalid {
+		return ErrInvalidStatus
+	}
+
+	usuarioID := sql.Null[int64]{V: params.UsuarioID, Valid: true}
+	alteracoes, err := aposentadoria.AtualizarDados(ctx, store, pa, params.Dados.aplicar(pa), database.OrigemAlteracaoManual, params.Motivo, usuarioID)
+	if err != nil {
+		return err
+	}
+	if len(alteracoes) == 0 {
+		return nil
+	}
+
+	if err := s.recalcularScore(ctx, store, pa); err != nil {
+		return err
+	}
+
+	// Invalidez e judicialização definem as competências exigidas do
+	// analista, então processos na fila podem passar a ser elegíveis para
+	// outros analistas.
+	if pa.Status == database.StatusProcessoAnalisePendente || pa.Status == database.StatusProcessoRetornoDiligencia {
+		if err := s.agendarAtribuicao(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
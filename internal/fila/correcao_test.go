@@ -0,0 +1,433 @@
+package fila
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/tasks"
+)
+
+func TestReprocessarProcesso(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoLeituraInvalid)
+
+	if err := env.service.ReprocessarProcesso(t.Context(), pa.ID, gestor.ID, "documentos ilegíveis"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(env.queue.jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(env.queue.jobs))
+	}
+	args, ok := env.queue.jobs[0].(tasks.DownloadProcessoArgs)
+	if !ok {
+		t.Fatalf("expected DownloadProcessoArgs, got %T", env.queue.jobs[0])
+	}
+	if args.ProcessoID != pa.ProcessoID || args.SolicitadoPor != gestor.ID {
+		t.Fatalf("unexpected args: %+v", args)
+	}
+	if opts := env.queue.opts[0]; opts == nil || !opts.UniqueOpts.ByArgs || len(opts.UniqueOpts.ByState) == 0 {
+		t.Fatalf("expected unique insert, got %+v", opts)
+	}
+
+	// O processo continua com leitura inválida até o fim da nova análise, e a
+	// solicitação é registrada nos eventos, não no histórico.
+	got, err := env.store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != database.StatusProcessoLeituraInvalid {
+		t.Fatalf("expected status LEITURA_INVALIDA, got %s", got.Status)
+	}
+	hh, err := env.store.ListHistoricoStatusProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 0 {
+		t.Fatalf("expected no histórico, got %d", len(hh))
+	}
+	ee, err := env.store.ListEventosProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ee) != 1 || ee[0].Tipo != database.EventoReprocessamento || ee[0].UsuarioID.V != gestor.ID {
+		t.Fatalf("unexpected eventos: %+v", ee)
+	}
+	if ee[0].Descricao != "Reprocessamento solicitado: documentos ilegíveis" {
+		t.Fatalf("unexpected descrição: %q", ee[0].Descricao)
+	}
+}
+
+func TestReprocessarProcesso_EmAndamento(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoLeituraInvalid)
+
+	env.queue.duplicado = true
+	err := env.service.ReprocessarProcesso(t.Context(), pa.ID, gestor.ID, "")
+	if !errors.Is(err, ErrReprocessamentoEmAndamento) {
+		t.Fatalf("expected ErrReprocessamentoEmAndamento, got %v", err)
+	}
+
+	// A solicitação repetida não é registrada.
+	ee, err := env.store.ListEventosProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ee) != 0 {
+		t.Fatalf("expected no eventos, got %+v", ee)
+	}
+}
+
+func TestReprocessarProcesso_InvalidStatus(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoAnalisePendente)
+
+	err := env.service.ReprocessarProcesso(t.Context(), pa.ID, gestor.ID, "")
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected ErrInvalidStatus, got %v", err)
+	}
+	if len(env.queue.jobs) != 0 {
+		t.Fatalf("expected no jobs, got %d", len(env.queue.jobs))
+	}
+}
+
+func TestCorrigirLeituraInvalida(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoLeituraInvalid)
+
+	cpf := "987.654.321-00"
+	invalidez := true
+	err := env.service.CorrigirLeituraInvalida(t.Context(), CorrigirProcessoParams{
+		ProcessoID: pa.ID,
+		GestorID:   gestor.ID,
+		Motivo:     "CPF conferido no requerimento",
+		Dados: CorrecaoDados{
+			CPF:       &cpf,
+			Invalidez: &invalidez,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := env.store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != database.StatusProcessoAnalisePendente {
+		t.Fatalf("expected status ANALISE_PENDENTE, got %s", got.Status)
+	}
+	if got.CPFRequerente != "98765432100" || !got.Invalidez {
+		t.Fatalf("unexpected dados: %+v", got)
+	}
+
+	// O score é recalculado com a versão ativa das regras.
+	score, err := env.store.GetScoreProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Score != got.Score {
+		t.Fatalf("expected score %d, got %d", got.Score, score.Score)
+	}
+
+	alteracoes, err := env.service.ListAlteracoesCampos(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alteracoes) != 2 {
+		t.Fatalf("expected 2 alterações, got %d", len(alteracoes))
+	}
+	for _, a := range alteracoes {
+		if a.Origem != database.OrigemAlteracaoManual || a.Usuario == nil || *a.Usuario != gestor.Nome {
+			t.Fatalf("unexpected alteração: %+v", a)
+		}
+	}
+
+	hh, err := env.store.ListHistoricoStatusProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 1 || hh[0].StatusAnterior.V != database.StatusProcessoLeituraInvalid || hh[0].StatusNovo != database.StatusProcessoAnalisePendente {
+		t.Fatalf("unexpected histórico: %+v", hh)
+	}
+
+	if len(env.queue.jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(env.queue.jobs))
+	}
+	if _, ok := env.queue.jobs[0].(tasks.AtribuirProcessosArgs); !ok {
+		t.Fatalf("expected AtribuirProcessosArgs, got %T", env.queue.jobs[0])
+	}
+}
+
+func TestCorrigirLeituraInvalida_InvalidStatus(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoAnalisePendente)
+
+	cpf := "98765432100"
+	err := env.service.CorrigirLeituraInvalida(t.Context(), CorrigirProcessoParams{
+		ProcessoID: pa.ID,
+		GestorID:   gestor.ID,
+		Dados:      CorrecaoDados{CPF: &cpf},
+	})
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Fatalf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+func TestCorrigirLeituraInvalida_DadosIncompletos(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoLeituraInvalid)
+
+	// A extração não encontrou o CPF nem a data de nascimento.
+	_, err := env.pool.Exec(t.Context(), `
+	UPDATE processos_aposentadoria SET
+		cpf_requerente = '',
+		data_nascimento_requerente = '0001-01-01'
+	WHERE id = $1`, pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpf := "98765432100"
+	err = env.service.CorrigirLeituraInvalida(t.Context(), CorrigirProcessoParams{
+		ProcessoID: pa.ID,
+		GestorID:   gestor.ID,
+		Dados:      CorrecaoDados{CPF: &cpf},
+	})
+	var incompletos *DadosIncompletosError
+	if !errors.As(err, &incompletos) {
+		t.Fatalf("expected DadosIncompletosError, got %v", err)
+	}
+	if _, ok := incompletos.FieldErrors["data_nascimento_requerente"]; !ok || len(incompletos.FieldErrors) != 1 {
+		t.Fatalf("expected only data_nascimento_requerente error, got %+v", incompletos.FieldErrors)
+	}
+
+	got, err := env.store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != database.StatusProcessoLeituraInvalid || got.CPFRequerente != "" {
+		t.Fatalf("expected processo to be unchanged, got %+v", got)
+	}
+}
+
+// Cria um processo em análise com o analista informado.
+func seedProcessoEmAnalise(t *testing.T, store *database.Store, analista *database.Analista) *database.ProcessoAposentadoria {
+	t.Helper()
+
+	pa := seedProcessoAposentadoria(t, store, database.StatusProcessoEmAnalise)
+	pa.AnalistaID = sql.Null[int64]{V: analista.UsuarioID, Valid: true}
+	if err := store.UpdateProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+	return pa
+}
+
+func TestCorrigirDados(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	analista := seedAnalista(t, env.store)
+	pa := seedProcessoEmAnalise(t, env.store, analista)
+
+	invalidez := true
+	judicial := false
+	err := env.service.CorrigirDados(t.Context(), CorrigirDadosParams{
+		ProcessoID: pa.ID,
+		UsuarioID:  analista.UsuarioID,
+		Analista:   true,
+		Motivo:     "Laudo médico anexado",
+		Dados: CorrecaoDados{
+			Invalidez: &invalidez,
+			// O valor atual não gera alteração.
+			Judicial: &judicial,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := env.store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != database.StatusProcessoEmAnalise || !got.Invalidez {
+		t.Fatalf("unexpected processo: %+v", got)
+	}
+
+	// O score é recalculado com os dados corrigidos.
+	score, err := env.store.GetScoreProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Score != got.Score {
+		t.Fatalf("expected score %d, got %d", got.Score, score.Score)
+	}
+
+	// A correção fica apenas na trilha de auditoria.
+	alteracoes, err := env.service.ListAlteracoesCampos(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alteracoes) != 1 {
+		t.Fatalf("expected 1 alteração, got %d", len(alteracoes))
+	}
+	a := alteracoes[0]
+	if a.Campo != "invalidez" || a.ValorAnterior != "false" || a.ValorNovo != "true" {
+		t.Fatalf("unexpected alteração: %+v", a)
+	}
+	if a.Origem != database.OrigemAlteracaoManual || a.Motivo != "Laudo médico anexado" || a.UsuarioID == nil || *a.UsuarioID != analista.UsuarioID {
+		t.Fatalf("unexpected alteração: %+v", a)
+	}
+	hh, err := env.store.ListHistoricoStatusProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 0 {
+		t.Fatalf("expected no histórico, got %d", len(hh))
+	}
+
+	// Processos em análise não voltam para a atribuição.
+	if len(env.queue.jobs) != 0 {
+		t.Fatalf("expected no jobs, got %d", len(env.queue.jobs))
+	}
+}
+
+func TestCorrigirDados_SemAlteracao(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoAnalisePendente)
+
+	cpf := pa.CPFRequerente
+	err := env.service.CorrigirDados(t.Context(), CorrigirDadosParams{
+		ProcessoID: pa.ID,
+		UsuarioID:  gestor.ID,
+		Dados:      CorrecaoDados{CPF: &cpf},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alteracoes, err := env.service.ListAlteracoesCampos(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alteracoes) != 0 {
+		t.Fatalf("expected no alterações, got %d", len(alteracoes))
+	}
+	if _, err := env.store.GetScoreProcessoAposentadoria(t.Context(), pa.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Fatalf("expected score not recalculated, got %v", err)
+	}
+	if len(env.queue.jobs) != 0 {
+		t.Fatalf("expected no jobs, got %d", len(env.queue.jobs))
+	}
+}
+
+func TestCorrigirDados_Fila(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	pa := seedProcessoAposentadoria(t, env.store, database.StatusProcessoAnalisePendente)
+
+	judicial := true
+	err := env.service.CorrigirDados(t.Context(), CorrigirDadosParams{
+		ProcessoID: pa.ID,
+		UsuarioID:  gestor.ID,
+		Dados:      CorrecaoDados{Judicial: &judicial},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// As competências exigidas mudaram, então a atribuição é agendada.
+	if len(env.queue.jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(env.queue.jobs))
+	}
+	if _, ok := env.queue.jobs[0].(tasks.AtribuirProcessosArgs); !ok {
+		t.Fatalf("expected AtribuirProcessosArgs, got %T", env.queue.jobs[0])
+	}
+}
+
+func TestCorrigirDados_Erros(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	analista := seedAnalista(t, env.store)
+	outro := seedAnalista(t, env.store)
+	gestor := seedUsuario(t, env.store, "GESTOR")
+	emAnalise := seedProcessoEmAnalise(t, env.store, outro)
+	concluido := seedProcessoAposentadoria(t, env.store, database.StatusProcessoConcluido)
+	leituraInvalida := seedProcessoAposentadoria(t, env.store, database.StatusProcessoLeituraInvalid)
+
+	cpf := "98765432100"
+	tests := []struct {
+		name   string
+		params CorrigirDadosParams
+		want   error
+	}{
+		{
+			name: "processo de outro analista",
+			params: CorrigirDadosParams{
+				ProcessoID: emAnalise.ID,
+				UsuarioID:  analista.UsuarioID,
+				Analista:   true,
+			},
+			want: ErrNotAssigned,
+		},
+		{
+			name: "processo concluído",
+			params: CorrigirDadosParams{
+				ProcessoID: concluido.ID,
+				UsuarioID:  gestor.ID,
+			},
+			want: ErrInvalidStatus,
+		},
+		{
+			name: "processo com leitura inválida",
+			params: CorrigirDadosParams{
+				ProcessoID: leituraInvalida.ID,
+				UsuarioID:  gestor.ID,
+			},
+			want: ErrInvalidStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Dados = CorrecaoDados{CPF: &cpf}
+			err := env.service.CorrigirDados(t.Context(), tt.params)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+
+			alteracoes, err := env.service.ListAlteracoesCampos(t.Context(), tt.params.ProcessoID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(alteracoes) != 0 {
+				t.Fatalf("expected no alterações, got %d", len(alteracoes))
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package fila
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+// estatisticasTTL é o tempo que as estatísticas da fila permanecem em cache.
+const estatisticasTTL = 5 * time.Minute
+
+// Estatisticas é a visão agregada da fila de processos de aposentadoria.
+type Estatisticas struct {
+	PorStatus   []ContagemStatus        `json:"por_status"`
+	PorScore    []ContagemScore         `json:"por_score"`
+	TempoEspera TempoEspera             `json:"tempo_espera"`
+	Conclusoes  []ConclusoesSemana      `json:"conclusoes_semana"`
+	Diligencias TaxaDiligencia          `json:"diligencias"`
+	Analistas   []ProdutividadeAnalista `json:"analistas"`
+	GeradoEm    time.Time               `json:"gerado_em"`
+}
+
+type ContagemStatus struct {
+	Status     string `json:"status"`
+	Quantidade int    `json:"quantidade"`
+}
+
+type ContagemScore struct {
+	Score      int `json:"score"`
+	Quantidade int `json:"quantidade"`
+}
+
+// TempoEspera resume, em horas, o tempo entre a criação dos processos e a
+// primeira atribuição a um analista.
+type TempoEspera struct {
+	Amostras   int     `json:"amostras"`
+	MediaHoras float64 `json:"media_horas"`
+	P50Horas   float64 `json:"p50_horas"`
+	P90Horas   float64 `json:"p90_horas"`
+}
+
+type ConclusoesSemana struct {
+	Semana     time.Time `json:"semana"`
+	Quantidade int       `json:"quantidade"`
+}
+
+// TaxaDiligencia indica a fração dos processos que passaram por diligência.
+type TaxaDiligencia struct {
+	Total         int     `json:"total"`
+	ComDiligencia int     `json:"com_diligencia"`
+	Taxa          float64 `json:"taxa"`
+}
+
+type ProdutividadeAnalista struct {
+	UsuarioID  int64  `json:"usuario_id"`
+	Nome       string `json:"nome"`
+	Abertos    int    `json:"abertos"`
+	Concluidos int    `json:"concluidos"`
+}
+
+type EstatisticasParams struct {
+	Inicio *time.Time
+	Fim    *time.Time
+}
+
+func (p EstatisticasParams) cacheKey() string {
+	format := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("fila:estatisticas:%s:%s", format(p.Inicio), format(p.Fim))
+}
+
+// Estatisticas retorna a visão agregada da fila no período informado. As
+// contagens por status, score, tempo de espera e diligências consideram os
+// processos criados no período; as conclusões consideram a data de conclusão.
+// O resultado é mantido em cache por alguns minutos.
+func (s *Service) Estatisticas(ctx context.Context, params EstatisticasParams) (*Estatisticas, error) {
+	b, err := s.cache.Remember(ctx, params.cacheKey(), estatisticasTTL, func() ([]byte, error) {
+		est, err := s.calcularEstatisticas(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(est)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var est Estatisticas
+	if err := json.Unmarshal(b, &est); err != nil {
+		return nil, err
+	}
+	return &est, nil
+}
+
+func (s *Service) calcularEstatisticas(ctx context.Context, params EstatisticasParams) (*Estatisticas, error) {
+	periodo := database.PeriodoEstatisticas{
+		Inicio: database.Null(params.Inicio),
+		Fim:    database.Null(params.Fim),
+	}
+
+	porStatus, err := s.store.CountProcessosPorStatus(ctx, periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	porScore, err := s.store.CountProcessosPorScore(ctx, periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	espera, err := s.store.GetTempoEspera(ctx, periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	conclusoes, err := s.store.ListConclusoesPorSemana(ctx, periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	diligencias, err := s.store.GetTaxaDiligencia(ctx, periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	analistas, err := s.store.ListProdutividadeAnalistas(ctx, periodo)
+	if err != nil {
+		return nil, err
+	}
+
+	est := &Estatisticas{
+		PorStatus:  make([]ContagemStatus, len(porStatus)),
+		PorScore:   make([]ContagemScore, len(porScore)),
+		Conclusoes: make([]ConclusoesSemana, len(conclusoes)),
+		Analistas:  make([]ProdutividadeAnalista, len(analistas)),
+		TempoEspera: TempoEspera{
+			Amostras:   espera.Amostras,
+			MediaHoras: espera.Media,
+			P50Horas:   espera.P50,
+			P90Horas:   espera.P90,
+		},
+		Diligencias: TaxaDiligencia{
+			Total:         diligencias.Total,
+			ComDiligencia: diligencias.ComDiligencia,
+		},
+		GeradoEm: time.Now(),
+	}
+	if diligencias.Total > 0 {
+		est.Diligencias.Taxa = float64(diligencias.ComDiligencia) / float64(diligencias.Total)
+	}
+	for i, c := range porStatus {
+		est.PorStatus[i] = ContagemStatus{Status: string(c.Status), Quantidade: c.Quantidade}
+	}
+	for i, c := range porScore {
+		est.PorScore[i] = ContagemScore{Score: c.Score, Quantidade: c.Quantidade}
+	}
+	for i, c := range conclusoes {
+		est.Conclusoes[i] = ConclusoesSemana{Semana: c.Semana, Quantidade: c.Quantidade}
+	}
+	for i, a := range analistas {
+		est.Analistas[i] = ProdutividadeAnalista{
+			UsuarioID:  a.UsuarioID,
+			Nome:       a.Nome,
+			Abertos:    a.Abertos,
+			Concluidos: a.Concluidos,
+		}
+	}
+
+	return est, nil
+}
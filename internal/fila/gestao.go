@@ -0,0 +1,369 @@
+package fila
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrAnalistaIndisponivel é o erro retornado quando o analista informado está
+// afastado ou já atingiu o seu limite de processos em análise.
+var ErrAnalistaIndisponivel = errors.New("analista afastado ou no limite de processos em análise")
+
+// ProcessoFixado é a indicação de que um processo deve ser o próximo atribuído
+// a um analista.
+type ProcessoFixado struct {
+	AnalistaID int64     `json:"analista_id"`
+	Analista   string    `json:"analista"`
+	FixadoPor  *int64    `json:"fixado_por"`
+	Motivo     string    `json:"motivo"`
+	CriadoEm   time.Time `json:"criado_em"`
+}
+
+// getAnalistaAtivo retorna o analista informado, verificando se ele pode
+// receber processos. Retorna [database.ErrNotFound] se o usuário não for
+// analista e [ErrAnalistaIndisponivel] se estiver afastado.
+func getAnalistaAtivo(ctx context.Context, store *database.Store, analistaID int64) (*database.Analista, string, error) {
+	analista, err := store.GetAnalista(ctx, analistaID)
+	if err != nil {
+		return nil, "", err
+	}
+	if analista.Afastado {
+		return nil, "", ErrAnalistaIndisponivel
+	}
+	nome, err := store.GetNomeAnalista(ctx, analistaID)
+	if err != nil {
+		return nil, "", err
+	}
+	return analista, nome, nil
+}
+
+type ReatribuirProcessoParams struct {
+	ProcessoID int64
+	AnalistaID int64
+	GestorID   int64
+	Motivo     string
+}
+
+// ReatribuirProcesso transfere um processo EM_ANALISE para outro analista,
+// tramitando o processo no SEI entre as unidades dos analistas. Retorna
+// [ErrInvalidStatus] se o processo não estiver em análise,
+// [database.ErrNotFound] se o processo ou o analista não existirem e
+// [ErrAnalistaIndisponivel] se o analista estiver afastado ou já tiver atingido
+// o seu limite de processos em análise.
+func (s *Service) ReatribuirProcesso(ctx context.Context, params ReatribuirProcessoParams) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, params.ProcessoID)
+	if err != nil {
+		return err
+	}
+	if pa.Status != database.StatusProcessoEmAnalise || !pa.AnalistaID.Valid {
+		return ErrInvalidStatus
+	}
+	if pa.AnalistaID.V == params.AnalistaID {
+		return nil
+	}
+
+	destino, nome, err := getAnalistaAtivo(ctx, store, params.AnalistaID)
+	if err != nil {
+		return err
+	}
+	origem, err := store.GetAnalista(ctx, pa.AnalistaID.V)
+	if err != nil {
+		return err
+	}
+	nomeOrigem, err := store.GetNomeAnalista(ctx, origem.UsuarioID)
+	if err != nil {
+		return err
+	}
+
+	pa.UltimoAnalistaID = pa.AnalistaID
+	pa.AnalistaID = sql.Null[int64]{V: destino.UsuarioID, Valid: true}
+	if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
+		if errors.Is(err, database.ErrLimiteProcessos) {
+			return ErrAnalistaIndisponivel
+		}
+		return err
+	}
+
+	obs := fmt.Sprintf("Processo reatribuído de %s para %s: %s", nomeOrigem, nome, params.Motivo)
+	if err := s.saveEvento(ctx, store, pa.ID, database.EventoReatribuicao, params.GestorID, obs); err != nil {
+		return err
+	}
+
+	destino.UltimaAtribuicaoEm = sql.Null[time.Time]{V: time.Now(), Valid: true}
+	if err := store.UpdateAnalista(ctx, destino); err != nil {
+		return err
+	}
+
+	if origem.SEIUnidadeID != destino.SEIUnidadeID {
+		_, err = s.queue.InsertTx(ctx, tx, tasks.EnviarProcessoSEIArgs{
+			ProcessoAposentadoriaID: pa.ID,
+			UnidadeOrigem:           origem.SEIUnidadeID,
+			UnidadeDestino:          destino.SEIUnidadeID,
+			Motivo:                  fmt.Sprintf("reatribuição ao analista da unidade %s", destino.SEIUnidadeSigla),
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type DesatribuirProcessoParams struct {
+	ProcessoID int64
+	GestorID   int64
+	Motivo     string
+}
+
+// DesatribuirProcesso devolve um processo EM_ANALISE para a fila, com status
+// ANALISE_PENDENTE, tramitando o processo no SEI de volta para a unidade da
+// fila. Retorna [ErrInvalidStatus] se o processo não estiver em análise.
+func (s *Service) DesatribuirProcesso(ctx context.Context, params DesatribuirProcessoParams) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, params.ProcessoID)
+	if err != nil {
+		return err
+	}
+	if pa.Status != database.StatusProcessoEmAnalise || !pa.AnalistaID.Valid {
+		return ErrInvalidStatus
+	}
+
+	observacao := "Processo devolvido à fila pela gestão: " + params.Motivo
+	if err := s.devolverProcesso(ctx, tx, store, pa, &params.GestorID, observacao); err != nil {
+		return err
+	}
+
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// devolverProcesso devolve um processo EM_ANALISE para a fila, com status
+// ANALISE_PENDENTE, registrando o histórico e agendando a tramitação do
+// processo no SEI de volta para a unidade da fila. A atribuição não é
+// agendada, cabendo ao chamador fazê-lo.
+func (s *Service) devolverProcesso(ctx context.Context, tx pgx.Tx, store *database.Store, pa *database.ProcessoAposentadoria, usuarioID *int64, observacao string) error {
+	analista, err := store.GetAnalista(ctx, pa.AnalistaID.V)
+	if err != nil {
+		return err
+	}
+
+	if err := s.saveHistorico(ctx, store, saveHistoricoParams{
+		ProcessoAposentadoriaID: pa.ID,
+		StatusAnterior:          &pa.Status,
+		StatusNovo:              database.StatusProcessoAnalisePendente,
+		UsuarioID:               usuarioID,
+		Observacao:              observacao,
+	}); err != nil {
+		return err
+	}
+
+	pa.Status = database.StatusProcessoAnalisePendente
+	pa.UltimoAnalistaID = pa.AnalistaID
+	pa.AnalistaID = sql.Null[int64]{}
+	if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
+		return err
+	}
+
+	if s.cfg.UnidadeDCCTA != "" && s.cfg.UnidadeDCCTA != analista.SEIUnidadeID {
+		_, err = s.queue.InsertTx(ctx, tx, tasks.EnviarProcessoSEIArgs{
+			ProcessoAposentadoriaID: pa.ID,
+			UnidadeOrigem:           analista.SEIUnidadeID,
+			UnidadeDestino:          s.cfg.UnidadeDCCTA,
+			Motivo:                  "devolução à fila",
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type FixarProcessoParams struct {
+	ProcessoID int64
+	AnalistaID int64
+	GestorID   int64
+	Motivo     string
+}
+
+// FixarProcesso indica que um processo aguardando análise deve ser o próximo
+// atribuído ao analista informado. Enquanto fixado, o processo não é atribuído
+// a outros analistas. Retorna [ErrInvalidStatus] se o processo não estiver
+// ANALISE_PENDENTE ou RETORNO_DILIGENCIA, [database.ErrNotFound] se o processo
+// ou o analista não existirem e [ErrAnalistaIndisponivel] se o analista
+// estiver afastado.
+func (s *Service) FixarProcesso(ctx context.Context, params FixarProcessoParams) (*ProcessoFixado, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, params.ProcessoID)
+	if err != nil {
+		return nil, err
+	}
+	if pa.Status != database.StatusProcessoAnalisePendente && pa.Status != database.StatusProcessoRetornoDiligencia {
+		return nil, ErrInvalidStatus
+	}
+
+	analista, nome, err := getAnalistaAtivo(ctx, store, params.AnalistaID)
+	if err != nil {
+		return nil, err
+	}
+
+	pf := &database.ProcessoFixado{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              analista.UsuarioID,
+		FixadoPor:               sql.Null[int64]{V: params.GestorID, Valid: true},
+		Motivo:                  params.Motivo,
+	}
+	if err := store.SaveProcessoFixado(ctx, pf); err != nil {
+		return nil, err
+	}
+
+	obs := fmt.Sprintf("Processo fixado para o analista %s: %s", nome, params.Motivo)
+	if err := s.saveEvento(ctx, store, pa.ID, database.EventoFixacao, params.GestorID, obs); err != nil {
+		return nil, err
+	}
+
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return mapProcessoFixado(pf, nome), nil
+}
+
+// DesafixarProcesso remove a fixação de um processo, que volta a ser atribuído
+// pela ordem normal da fila. Retorna [database.ErrNotFound] se o processo não
+// estiver fixado.
+func (s *Service) DesafixarProcesso(ctx context.Context, paID, gestorID int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, paID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := store.DeleteProcessoFixado(ctx, pa.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	if err := s.saveEvento(ctx, store, pa.ID, database.EventoFixacao, gestorID, "Fixação do processo removida"); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// LimparExclusoes remove as exclusões do processo, que volta a poder ser
+// atribuído aos analistas que o devolveram. Retorna [database.ErrNotFound] se
+// o processo não possuir analistas excluídos.
+func (s *Service) LimparExclusoes(ctx context.Context, paID, gestorID int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, paID)
+	if err != nil {
+		return err
+	}
+
+	n, err := store.DeleteAnalistasExcluidos(ctx, pa.ID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return database.ErrNotFound
+	}
+
+	descricao := fmt.Sprintf("Exclusões de %d analista(s) removidas", n)
+	if err := s.saveEvento(ctx, store, pa.ID, database.EventoExclusao, gestorID, descricao); err != nil {
+		return err
+	}
+
+	if pa.Status == database.StatusProcessoAnalisePendente || pa.Status == database.StatusProcessoRetornoDiligencia {
+		if err := s.agendarAtribuicao(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// getProcessoFixado retorna a fixação de um processo, ou nil se o processo não
+// estiver fixado.
+func (s *Service) getProcessoFixado(ctx context.Context, paID int64) (*ProcessoFixado, error) {
+	pf, err := s.store.GetProcessoFixado(ctx, paID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	nome, err := s.store.GetNomeAnalista(ctx, pf.AnalistaID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	return mapProcessoFixado(pf, nome), nil
+}
+
+func mapProcessoFixado(pf *database.ProcessoFixado, analista string) *ProcessoFixado {
+	return &ProcessoFixado{
+		AnalistaID: pf.AnalistaID,
+		Analista:   analista,
+		FixadoPor:  database.Ptr(pf.FixadoPor),
+		Motivo:     pf.Motivo,
+		CriadoEm:   pf.CriadoEm,
+	}
+}
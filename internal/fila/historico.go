@@ -58,6 +58,17 @@ func (s *Service) saveHistorico(ctx context.Context, store *database.Store, para
 	})
 }
 
+// saveEvento registra um evento de um processo de aposentadoria que não altera
+// o seu status.
+func (s *Service) saveEvento(ctx context.Context, store *database.Store, paID int64, tipo string, usuarioID int64, descricao string) error {
+	return store.SaveEventoProcesso(ctx, &database.EventoProcesso{
+		ProcessoAposentadoriaID: paID,
+		Tipo:                    tipo,
+		Descricao:               descricao,
+		UsuarioID:               sql.Null[int64]{V: usuarioID, Valid: true},
+	})
+}
+
 // ListHistorico retorna o histórico completo de mudanças de status de um processo de aposentadoria.
 func (s *Service) ListHistorico(ctx context.Context, paID int64) ([]*HistoricoStatusProcesso, error) {
 	hh, err := s.store.ListHistoricoStatusProcesso(ctx, paID)
@@ -79,3 +90,32 @@ func (s *Service) ListHistorico(ctx context.Context, paID int64) ([]*HistoricoSt
 
 	return historico, nil
 }
+
+type EventoProcesso struct {
+	Tipo      string    `json:"tipo"`
+	Descricao string    `json:"descricao"`
+	UsuarioID *int64    `json:"usuario_id"`
+	Usuario   *string   `json:"usuario"`
+	CriadoEm  time.Time `json:"criado_em"`
+}
+
+// ListEventos retorna os eventos de um processo de aposentadoria que não
+// alteram o seu status, como as tramitações no SEI.
+func (s *Service) ListEventos(ctx context.Context, paID int64) ([]*EventoProcesso, error) {
+	ee, err := s.store.ListEventosProcesso(ctx, paID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventos := make([]*EventoProcesso, len(ee))
+	for i, e := range ee {
+		eventos[i] = &EventoProcesso{
+			Tipo:      e.Tipo,
+			Descricao: e.Descricao,
+			UsuarioID: database.Ptr(e.UsuarioID),
+			Usuario:   database.Ptr(e.Usuario),
+			CriadoEm:  e.CriadoEm,
+		}
+	}
+	return eventos, nil
+}
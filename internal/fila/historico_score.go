@@ -0,0 +1,64 @@
+package fila
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/aposentadoria"
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+// HistoricoScore representa uma mudança no score de um processo de
+// aposentadoria, com o detalhamento do cálculo que a produziu.
+type HistoricoScore struct {
+	ScoreAnterior *int                          `json:"score_anterior"`
+	ScoreNovo     int                           `json:"score_novo"`
+	Detalhes      *aposentadoria.ResultadoScore `json:"detalhes"`
+	CalculadoEm   time.Time                     `json:"calculado_em"`
+}
+
+// ListHistoricoScore retorna as mudanças de score de um processo de
+// aposentadoria, da mais recente para a mais antiga.
+func (s *Service) ListHistoricoScore(ctx context.Context, paID int64) ([]*HistoricoScore, error) {
+	hh, err := s.store.ListHistoricoScoreProcesso(ctx, paID)
+	if err != nil {
+		return nil, err
+	}
+
+	historico := make([]*HistoricoScore, len(hh))
+	for i, h := range hh {
+		var detalhes aposentadoria.ResultadoScore
+		if err := json.Unmarshal(h.Detalhes, &detalhes); err != nil {
+			return nil, err
+		}
+
+		historico[i] = &HistoricoScore{
+			ScoreAnterior: database.Ptr(h.ScoreAnterior),
+			ScoreNovo:     h.ScoreNovo,
+			Detalhes:      &detalhes,
+			CalculadoEm:   h.CalculadoEm,
+		}
+	}
+	return historico, nil
+}
+
+// getScoreDetalhado retorna o detalhamento do score atual de um processo.
+// Retorna nil para processos cujo score ainda não foi calculado com as regras
+// versionadas.
+func (s *Service) getScoreDetalhado(ctx context.Context, paID int64) (*aposentadoria.ResultadoScore, error) {
+	sc, err := s.store.GetScoreProcessoAposentadoria(ctx, paID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var detalhes aposentadoria.ResultadoScore
+	if err := json.Unmarshal(sc.Detalhes, &detalhes); err != nil {
+		return nil, err
+	}
+	return &detalhes, nil
+}
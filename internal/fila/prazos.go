@@ -0,0 +1,40 @@
+package fila
+
+import (
+	"context"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/prazos"
+)
+
+// calcularPrazo calcula o prazo legal de um processo de aposentadoria,
+// considerando as suspensões registradas no histórico de status.
+func (s *Service) calcularPrazo(ctx context.Context, pa *database.ProcessoAposentadoria) (*prazos.Prazo, error) {
+	pp, err := s.calcularPrazos(ctx, []*database.ProcessoAposentadoria{pa})
+	if err != nil {
+		return nil, err
+	}
+	return pp[pa.ID], nil
+}
+
+// calcularPrazos calcula os prazos legais de uma lista de processos de
+// aposentadoria, carregando as suspensões de todos em uma única consulta.
+func (s *Service) calcularPrazos(ctx context.Context, paa []*database.ProcessoAposentadoria) (map[int64]*prazos.Prazo, error) {
+	ids := make([]int64, len(paa))
+	for i, pa := range paa {
+		ids[i] = pa.ID
+	}
+
+	historico, err := s.store.ListHistoricoDiligenciaMap(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pp := make(map[int64]*prazos.Prazo, len(paa))
+	for _, pa := range paa {
+		pp[pa.ID] = prazos.Calcular(&s.cfg.Prazos, pa, prazos.Pausas(historico[pa.ID]), now)
+	}
+	return pp, nil
+}
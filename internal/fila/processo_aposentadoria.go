@@ -3,11 +3,17 @@ package fila
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/automatiza-mg/fila/internal/aposentadoria"
 	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/llm"
 	"github.com/automatiza-mg/fila/internal/pagination"
+	"github.com/automatiza-mg/fila/internal/prazos"
+	"github.com/automatiza-mg/fila/internal/tasks"
 	"github.com/google/uuid"
 )
 
@@ -37,6 +43,116 @@ type ProcessoAposentadoria struct {
 	Alertas                  []string  `json:"alertas"`
 	CriadoEm                 time.Time `json:"criado_em"`
 	AtualizadoEm             time.Time `json:"atualizado_em"`
+
+	Prazo *prazos.Prazo `json:"prazo"`
+
+	// ScoreDetalhado é preenchido apenas nas consultas de um único processo.
+	ScoreDetalhado *aposentadoria.ResultadoScore `json:"score_detalhado,omitempty"`
+	// Fixacao é preenchido apenas nas consultas de um único processo, quando o
+	// processo estiver fixado para um analista.
+	Fixacao *ProcessoFixado `json:"fixacao,omitempty"`
+	// AnalistasExcluidos é preenchido apenas nas consultas de um único
+	// processo, com os analistas que devolveram o processo e não devem
+	// voltar a recebê-lo.
+	AnalistasExcluidos []int64 `json:"analistas_excluidos,omitempty"`
+	// Fontes é preenchido apenas nas consultas de um único processo, com a
+	// procedência de cada dado do requerente.
+	Fontes map[string]*FonteCampo `json:"fontes,omitempty"`
+	// Discrepancias é preenchido apenas nas consultas de um único processo,
+	// com as diferenças entre a IA e as fontes externas.
+	Discrepancias []aposentadoria.Discrepancia `json:"discrepancias,omitempty"`
+	// Citacoes é preenchido apenas nas consultas de um único processo, com os
+	// trechos dos documentos que comprovam os dados extraídos pela IA.
+	Citacoes []*Citacao `json:"citacoes,omitempty"`
+}
+
+// Citacao é o trecho de um documento do processo que comprova um dado
+// extraído pela IA. O tipo e o link do documento são nulos quando o número
+// citado não corresponde a um documento do processo. A citação não indica a
+// página do trecho no preview, que reúne todos os documentos em um único
+// arquivo.
+type Citacao struct {
+	Campo         string  `json:"campo"`
+	Documento     string  `json:"documento"`
+	DocumentoTipo *string `json:"documento_tipo"`
+	LinkAcesso    *string `json:"link_acesso"`
+	Trecho        string  `json:"trecho"`
+}
+
+// getCitacoes retorna as citações registradas na análise de IA do processo.
+// As citações dos campos corrigidos manualmente são descartadas, pois não
+// comprovam o valor atual.
+func (s *Service) getCitacoes(ctx context.Context, p *database.Processo, fontes map[string]*FonteCampo) ([]*Citacao, error) {
+	if len(p.MetadadosIA) == 0 {
+		return nil, nil
+	}
+
+	var analise llm.AnaliseAposentadoria
+	if err := json.Unmarshal(p.MetadadosIA, &analise); err != nil {
+		return nil, err
+	}
+	if len(analise.Citacoes) == 0 {
+		return nil, nil
+	}
+
+	dd, err := s.store.ListDocumentos(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]*database.Documento, len(dd))
+	for _, d := range dd {
+		docs[d.Numero] = d
+	}
+
+	citacoes := make([]*Citacao, 0, len(analise.Citacoes))
+	for _, c := range analise.Citacoes {
+		if f, ok := fontes[c.Campo]; ok && f.Fonte == database.FonteManual {
+			continue
+		}
+		citacao := &Citacao{
+			Campo:     c.Campo,
+			Documento: c.Documento,
+			Trecho:    c.Trecho,
+		}
+		if d, ok := docs[c.Documento]; ok {
+			citacao.DocumentoTipo = &d.Tipo
+			citacao.LinkAcesso = &d.LinkAcesso
+		}
+		citacoes = append(citacoes, citacao)
+	}
+	return citacoes, nil
+}
+
+// FonteCampo é a procedência do valor atual de um dado do requerente, com os
+// valores da IA e da fonte externa consultada na extração.
+type FonteCampo struct {
+	Fonte        string  `json:"fonte"`
+	ValorIA      *string `json:"valor_ia"`
+	FonteExterna *string `json:"fonte_externa"`
+	ValorExterno *string `json:"valor_externo"`
+}
+
+// getFontes retorna a procedência dos dados do requerente de um processo e as
+// discrepâncias entre a IA e as fontes externas.
+func (s *Service) getFontes(ctx context.Context, paID int64) (map[string]*FonteCampo, []aposentadoria.Discrepancia, error) {
+	ff, err := s.store.ListFontesCamposProcesso(ctx, paID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ff) == 0 {
+		return nil, nil, nil
+	}
+
+	fontes := make(map[string]*FonteCampo, len(ff))
+	for _, f := range ff {
+		fontes[f.Campo] = &FonteCampo{
+			Fonte:        f.Fonte,
+			ValorIA:      database.Ptr(f.ValorIA),
+			FonteExterna: database.Ptr(f.FonteExterna),
+			ValorExterno: database.Ptr(f.ValorExterno),
+		}
+	}
+	return fontes, aposentadoria.Discrepancias(ff), nil
 }
 
 func mapProcesso(pa *database.ProcessoAposentadoria, p *database.Processo, analista *string) *ProcessoAposentadoria {
@@ -82,7 +198,33 @@ func (s *Service) GetProcessoAposentadoria(ctx context.Context, id int64) (*Proc
 		analista = &nome
 	}
 
-	return mapProcesso(pa, p, analista), nil
+	result := mapProcesso(pa, p, analista)
+	result.Prazo, err = s.calcularPrazo(ctx, pa)
+	if err != nil {
+		return nil, err
+	}
+	result.ScoreDetalhado, err = s.getScoreDetalhado(ctx, pa.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Fixacao, err = s.getProcessoFixado(ctx, pa.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.AnalistasExcluidos, err = s.store.ListAnalistasExcluidos(ctx, pa.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Fontes, result.Discrepancias, err = s.getFontes(ctx, pa.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Citacoes, err = s.getCitacoes(ctx, p, result.Fontes)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // GetProcessoAposentadoriaByNumero retorna um processo de aposentadoria pelo
@@ -107,14 +249,23 @@ func (s *Service) GetProcessoAposentadoriaByNumero(ctx context.Context, numero s
 		analista = &nome
 	}
 
-	return mapProcesso(pa, p, analista), nil
+	result := mapProcesso(pa, p, analista)
+	result.Prazo, err = s.calcularPrazo(ctx, pa)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 type ListProcessoAposentadoriaParams struct {
 	Numero string
 	Status string
-	Page   int
-	Limit  int
+	// DiligenciaVencida restringe a lista aos processos com o prazo de
+	// resposta da diligência vencido.
+	DiligenciaVencida bool
+	Page              int
+	Limit             int
 }
 
 // ListProcesso retorna a lista paginada dos processos de aposentadoria com seus numeros.
@@ -122,10 +273,11 @@ func (s *Service) ListProcesso(ctx context.Context, params ListProcessoAposentad
 	offset := pagination.Offset(params.Page, params.Limit)
 
 	paa, totalCount, err := s.store.ListProcessoAposentadoria(ctx, database.ListProcessoAposentadoriaParams{
-		Numero: params.Numero,
-		Status: params.Status,
-		Limit:  params.Limit,
-		Offset: offset,
+		Numero:            params.Numero,
+		Status:            params.Status,
+		DiligenciaVencida: params.DiligenciaVencida,
+		Limit:             params.Limit,
+		Offset:            offset,
 	})
 	if err != nil {
 		return nil, err
@@ -136,6 +288,11 @@ func (s *Service) ListProcesso(ctx context.Context, params ListProcessoAposentad
 		return pagination.NewResult(processos, params.Page, totalCount, params.Limit), nil
 	}
 
+	prazosMap, err := s.calcularPrazos(ctx, paa)
+	if err != nil {
+		return nil, err
+	}
+
 	// Busca os números dos processos.
 	for _, pa := range paa {
 		p, err := s.store.GetProcesso(ctx, pa.ProcessoID)
@@ -152,7 +309,10 @@ func (s *Service) ListProcesso(ctx context.Context, params ListProcessoAposentad
 			analista = &nome
 		}
 
-		processos = append(processos, mapProcesso(pa, p, analista))
+		result := mapProcesso(pa, p, analista)
+		result.Prazo = prazosMap[pa.ID]
+
+		processos = append(processos, result)
 	}
 
 	return pagination.NewResult(processos, params.Page, totalCount, params.Limit), nil
@@ -183,6 +343,11 @@ func (s *Service) ListHistoricoAnalista(ctx context.Context, analistaID int64, n
 		return pagination.NewResult(processos, page, totalCount, limit), nil
 	}
 
+	prazosMap, err := s.calcularPrazos(ctx, paa)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, pa := range paa {
 		p, err := s.store.GetProcesso(ctx, pa.ProcessoID)
 		if err != nil {
@@ -198,7 +363,10 @@ func (s *Service) ListHistoricoAnalista(ctx context.Context, analistaID int64, n
 			analista = &nome
 		}
 
-		processos = append(processos, mapProcesso(pa, p, analista))
+		result := mapProcesso(pa, p, analista)
+		result.Prazo = prazosMap[pa.ID]
+
+		processos = append(processos, result)
 	}
 
 	return pagination.NewResult(processos, page, totalCount, limit), nil
@@ -253,6 +421,83 @@ func (s *Service) MarcarLeituraInvalida(ctx context.Context, params MarcarLeitur
 		return err
 	}
 
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+type DevolverProcessoParams struct {
+	AnalistaID int64
+	ProcessoID int64
+	Motivo     string
+	// ExcluirAnalista impede que o processo volte a ser atribuído ao analista
+	// que o devolveu.
+	ExcluirAnalista bool
+}
+
+// DevolverProcesso devolve à fila, com status ANALISE_PENDENTE, um processo que
+// o analista não pode analisar, registrando a devolução para o relatório da
+// gestão. Retorna [ErrNotAssigned] caso o processo não esteja atribuído ao
+// analista informado e [ErrInvalidStatus] caso o processo não esteja em análise.
+func (s *Service) DevolverProcesso(ctx context.Context, params DevolverProcessoParams) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, params.ProcessoID)
+	if err != nil {
+		return err
+	}
+
+	if !pa.AnalistaID.Valid || pa.AnalistaID.V != params.AnalistaID {
+		return ErrNotAssigned
+	}
+
+	if pa.Status != database.StatusProcessoEmAnalise {
+		return ErrInvalidStatus
+	}
+
+	observacao := "Processo devolvido à fila pelo analista: " + params.Motivo
+	if err := s.devolverProcesso(ctx, tx, store, pa, &params.AnalistaID, observacao); err != nil {
+		return err
+	}
+
+	err = store.SaveDevolucaoProcesso(ctx, &database.DevolucaoProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		AnalistaID:              params.AnalistaID,
+		Motivo:                  params.Motivo,
+		ExcluirAnalista:         params.ExcluirAnalista,
+	})
+	if err != nil {
+		return err
+	}
+	// Sem a exclusão permanente, o analista fica excluído apenas da
+	// atribuição agendada pela devolução, que remove a exclusão temporária ao
+	// terminar. A exclusão expira caso o job seja descartado.
+	var args tasks.AtribuirProcessosArgs
+	if params.ExcluirAnalista {
+		err = store.ExcluirAnalistaProcesso(ctx, pa.ID, params.AnalistaID)
+	} else {
+		err = store.ExcluirAnalistaProcessoTemporariamente(ctx, pa.ID, params.AnalistaID, time.Now().Add(ExpiracaoExclusaoTemporaria))
+		args.Devolucao = &tasks.DevolucaoAtribuicao{
+			ProcessoAposentadoriaID: pa.ID,
+			AnalistaID:              params.AnalistaID,
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.queue.InsertTx(ctx, tx, args, nil); err != nil {
+		return fmt.Errorf("erro ao agendar atribuição de processos: %w", err)
+	}
+
 	return tx.Commit(ctx)
 }
 
@@ -298,11 +543,67 @@ func (s *Service) RegistrarPublicacao(ctx context.Context, paID, analistaID int6
 		return err
 	}
 
+	if s.cfg.UnidadeConclusao != "" {
+		analista, err := store.GetAnalista(ctx, analistaID)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.queue.InsertTx(ctx, tx, tasks.EnviarProcessoSEIArgs{
+			ProcessoAposentadoriaID: pa.ID,
+			UnidadeOrigem:           analista.SEIUnidadeID,
+			UnidadeDestino:          s.cfg.UnidadeConclusao,
+			Motivo:                  "conclusão com registro de publicação",
+		}, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.agendarAtribuicao(ctx, tx); err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
 
-// GetProcessoAtribuido retorna o processo de aposentadoria atribuído a um analista.
-// Retorna [database.ErrNotFound] se o analista não tiver um processo EM_ANALISE.
+// ListProcessosAtribuidos retorna os processos de aposentadoria EM_ANALISE
+// atribuídos a um analista, começando pelo requerimento mais antigo.
+func (s *Service) ListProcessosAtribuidos(ctx context.Context, analistaID int64) ([]*ProcessoAposentadoria, error) {
+	paa, err := s.store.ListProcessosAtribuidos(ctx, analistaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(paa) == 0 {
+		return []*ProcessoAposentadoria{}, nil
+	}
+
+	nome, err := s.store.GetNomeAnalista(ctx, analistaID)
+	if err != nil {
+		return nil, err
+	}
+
+	prazosMap, err := s.calcularPrazos(ctx, paa)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*ProcessoAposentadoria, len(paa))
+	for i, pa := range paa {
+		p, err := s.store.GetProcesso(ctx, pa.ProcessoID)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = mapProcesso(pa, p, &nome)
+		result[i].Prazo = prazosMap[pa.ID]
+	}
+
+	return result, nil
+}
+
+// GetProcessoAtribuido retorna o processo de aposentadoria atribuído a um
+// analista com o requerimento mais antigo. Retorna [database.ErrNotFound] se o
+// analista não tiver processos EM_ANALISE.
 func (s *Service) GetProcessoAtribuido(ctx context.Context, analistaID int64) (*ProcessoAposentadoria, error) {
 	pa, err := s.store.GetProcessoAtribuido(ctx, analistaID)
 	if err != nil {
@@ -323,5 +624,15 @@ func (s *Service) GetProcessoAtribuido(ctx context.Context, analistaID int64) (*
 		analista = &nome
 	}
 
-	return mapProcesso(pa, p, analista), nil
+	result := mapProcesso(pa, p, analista)
+	result.Prazo, err = s.calcularPrazo(ctx, pa)
+	if err != nil {
+		return nil, err
+	}
+	result.ScoreDetalhado, err = s.getScoreDetalhado(ctx, pa.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
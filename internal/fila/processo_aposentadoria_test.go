@@ -0,0 +1,140 @@
+package fila
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/llm"
+	"github.com/automatiza-mg/fila/internal/tasks"
+)
+
+func TestGetCitacoes(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	p := &database.Processo{Numero: "1500.01.0000001/2024-01", SeiUnidadeID: "1"}
+	if err := env.store.SaveProcesso(t.Context(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	arq := &database.Arquivo{
+		Hash:            "citacoes-hash",
+		ChaveStorage:    "processos/citacoes-hash.pdf",
+		ContentType:     "application/pdf",
+		FormatoConteudo: "plain",
+	}
+	if err := env.store.SaveArquivo(t.Context(), arq); err != nil {
+		t.Fatal(err)
+	}
+	doc := &database.Documento{
+		Numero:       "81234567",
+		ProcessoID:   p.ID,
+		Tipo:         "Requerimento",
+		LinkAcesso:   "https://sei.example/81234567",
+		ArquivoHash:  arq.Hash,
+		MetadadosAPI: []byte("{}"),
+	}
+	if err := env.store.SaveDocumento(t.Context(), doc); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	p.MetadadosIA, err = json.Marshal(llm.AnaliseAposentadoria{
+		Aposentadoria: true,
+		Citacoes: []llm.Citacao{
+			{Campo: "cpf_requerente", Documento: "81234567", Trecho: "CPF 123.456.789-01"},
+			{Campo: "data_nascimento_requerente", Documento: "99999999", Trecho: "nascido em 10/05/1960"},
+			{Campo: "judicial", Documento: "81234567", Trecho: "por determinação judicial"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fontes := map[string]*FonteCampo{
+		"cpf_requerente": {Fonte: database.FonteIA},
+		"judicial":       {Fonte: database.FonteManual},
+	}
+	citacoes, err := env.service.getCitacoes(t.Context(), p, fontes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(citacoes) != 2 {
+		t.Fatalf("expected 2 citações, got %d", len(citacoes))
+	}
+
+	encontrada := citacoes[0]
+	if encontrada.Campo != "cpf_requerente" || encontrada.DocumentoTipo == nil || *encontrada.DocumentoTipo != doc.Tipo ||
+		encontrada.LinkAcesso == nil || *encontrada.LinkAcesso != doc.LinkAcesso {
+		t.Fatalf("unexpected citação: %+v", encontrada)
+	}
+
+	desconhecida := citacoes[1]
+	if desconhecida.Documento != "99999999" || desconhecida.DocumentoTipo != nil || desconhecida.LinkAcesso != nil {
+		t.Fatalf("expected citação without documento, got %+v", desconhecida)
+	}
+}
+
+func TestDevolverProcesso_AtribuicaoDescartada(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv(t)
+
+	analista := seedAnalista(t, env.store)
+	if _, err := env.pool.Exec(t.Context(), `UPDATE usuarios SET email_verificado = TRUE WHERE id = $1`, analista.UsuarioID); err != nil {
+		t.Fatal(err)
+	}
+	pa := seedProcessoEmAnalise(t, env.store, analista)
+
+	err := env.service.DevolverProcesso(t.Context(), DevolverProcessoParams{
+		AnalistaID: analista.UsuarioID,
+		ProcessoID: pa.ID,
+		Motivo:     "Impedimento",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var devolucao *tasks.DevolucaoAtribuicao
+	for _, job := range env.queue.jobs {
+		if args, ok := job.(tasks.AtribuirProcessosArgs); ok {
+			devolucao = args.Devolucao
+		}
+	}
+	if devolucao == nil {
+		t.Fatalf("expected atribuição da devolução, got %+v", env.queue.jobs)
+	}
+
+	// O job da devolução é descartado sem remover a exclusão, que expira.
+	_, err = env.pool.Exec(t.Context(), `
+	UPDATE processos_analistas_excluidos
+	SET expira_em = CURRENT_TIMESTAMP - INTERVAL '1 minute'
+	WHERE processo_aposentadoria_id = $1`, pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := env.service.AtribuirProcessos(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 atribuição, got %d", n)
+	}
+
+	got, err := env.store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AnalistaID.V != analista.UsuarioID {
+		t.Fatalf("expected processo assigned to analista %d, got %+v", analista.UsuarioID, got.AnalistaID)
+	}
+
+	var exclusoes int
+	err = env.pool.QueryRow(t.Context(), `SELECT COUNT(*) FROM processos_analistas_excluidos WHERE processo_aposentadoria_id = $1`, pa.ID).Scan(&exclusoes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exclusoes != 0 {
+		t.Fatalf("expected expired exclusão to be removed, got %d", exclusoes)
+	}
+}
@@ -2,8 +2,12 @@ package fila
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
 
+	"github.com/automatiza-mg/fila/internal/aposentadoria"
+	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/automatiza-mg/fila/internal/tasks"
 )
 
@@ -23,3 +27,22 @@ func (s *Service) EnqueueRecalcularScores(ctx context.Context) error {
 
 	return tx.Commit(ctx)
 }
+
+// recalcularScore recalcula o score de um processo com a versão ativa das
+// regras, salvando o processo e o detalhamento do cálculo.
+func (s *Service) recalcularScore(ctx context.Context, store *database.Store, pa *database.ProcessoAposentadoria) error {
+	versao, err := aposentadoria.LoadVersaoAtiva(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	anterior := sql.Null[int]{V: pa.Score, Valid: true}
+
+	res := versao.Calcular(pa, time.Now())
+	pa.Score = res.Score
+	if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
+		return err
+	}
+
+	return versao.Registrar(ctx, store, pa.ID, anterior, res)
+}
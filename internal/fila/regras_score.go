@@ -0,0 +1,154 @@
+package fila
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/aposentadoria"
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/tasks"
+	"github.com/jackc/pgx/v5"
+)
+
+// VersaoRegrasScore representa uma versão das regras de cálculo de score.
+type VersaoRegrasScore struct {
+	ID        int64                      `json:"id"`
+	Versao    int                        `json:"versao"`
+	Descricao string                     `json:"descricao"`
+	Regras    *aposentadoria.RegrasScore `json:"regras"`
+	Ativa     bool                       `json:"ativa"`
+	CriadoPor *int64                     `json:"criado_por"`
+	CriadoEm  time.Time                  `json:"criado_em"`
+	AtivadaEm *time.Time                 `json:"ativada_em"`
+}
+
+func mapVersaoRegrasScore(v *database.VersaoRegrasScore) (*VersaoRegrasScore, error) {
+	regras, err := aposentadoria.ParseRegras(v.Regras)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VersaoRegrasScore{
+		ID:        v.ID,
+		Versao:    v.Versao,
+		Descricao: v.Descricao,
+		Regras:    regras,
+		Ativa:     v.Ativa,
+		CriadoPor: database.Ptr(v.CriadoPor),
+		CriadoEm:  v.CriadoEm,
+		AtivadaEm: database.Ptr(v.AtivadaEm),
+	}, nil
+}
+
+// ListVersoesRegrasScore retorna todas as versões de regras de score, da mais
+// recente para a mais antiga.
+func (s *Service) ListVersoesRegrasScore(ctx context.Context) ([]*VersaoRegrasScore, error) {
+	vv, err := s.store.ListVersoesRegrasScore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versoes := make([]*VersaoRegrasScore, len(vv))
+	for i, v := range vv {
+		versoes[i], err = mapVersaoRegrasScore(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return versoes, nil
+}
+
+// GetVersaoRegrasScore retorna uma versão de regras de score pelo ID.
+func (s *Service) GetVersaoRegrasScore(ctx context.Context, id int64) (*VersaoRegrasScore, error) {
+	v, err := s.store.GetVersaoRegrasScore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapVersaoRegrasScore(v)
+}
+
+// GetVersaoRegrasScoreAtiva retorna a versão de regras de score em vigor.
+func (s *Service) GetVersaoRegrasScoreAtiva(ctx context.Context) (*VersaoRegrasScore, error) {
+	v, err := s.store.GetVersaoRegrasScoreAtiva(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mapVersaoRegrasScore(v)
+}
+
+type CreateVersaoRegrasScoreParams struct {
+	Descricao string
+	Regras    *aposentadoria.RegrasScore
+	UsuarioID int64
+	// Ativar indica se a nova versão deve entrar em vigor imediatamente.
+	Ativar bool
+}
+
+// CreateVersaoRegrasScore cria uma nova versão das regras de score. As regras
+// devem ter sido validadas com [aposentadoria.RegrasScore.Check].
+func (s *Service) CreateVersaoRegrasScore(ctx context.Context, params CreateVersaoRegrasScoreParams) (*VersaoRegrasScore, error) {
+	regras, err := json.Marshal(params.Regras)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	store := s.store.WithTx(tx)
+
+	v := &database.VersaoRegrasScore{
+		Descricao: params.Descricao,
+		Regras:    regras,
+		CriadoPor: sql.Null[int64]{V: params.UsuarioID, Valid: true},
+	}
+	if err := store.SaveVersaoRegrasScore(ctx, v); err != nil {
+		return nil, err
+	}
+
+	if params.Ativar {
+		if err := s.ativarVersaoRegrasScore(ctx, tx, v.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.GetVersaoRegrasScore(ctx, v.ID)
+}
+
+// AtivarVersaoRegrasScore coloca uma versão das regras de score em vigor e
+// enfileira o recálculo dos scores de todos os processos.
+func (s *Service) AtivarVersaoRegrasScore(ctx context.Context, id int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.ativarVersaoRegrasScore(ctx, tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *Service) ativarVersaoRegrasScore(ctx context.Context, tx pgx.Tx, id int64) error {
+	if err := s.store.WithTx(tx).AtivarVersaoRegrasScore(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := s.queue.InsertTx(ctx, tx, tasks.RecalcularScoresArgs{VersaoRegrasID: id}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
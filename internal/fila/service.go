@@ -6,8 +6,8 @@ import (
 	"errors"
 
 	"github.com/automatiza-mg/fila/internal/auth"
+	"github.com/automatiza-mg/fila/internal/cache"
 	"github.com/automatiza-mg/fila/internal/database"
-	"github.com/automatiza-mg/fila/internal/sei"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
@@ -21,25 +21,23 @@ type TaskInserter interface {
 	InsertTx(ctx context.Context, tx pgx.Tx, args river.JobArgs, opts *river.InsertOpts) (*rivertype.JobInsertResult, error)
 }
 
-type SEIService interface {
-	EnviarProcesso(ctx context.Context, protocolo string, unidadeOrigem string, unidadesDestino []string) (*sei.EnviarProcessoResponse, error)
-}
-
 // Service gerencia a fila de processos de aposentadoria.
 type Service struct {
 	pool  *pgxpool.Pool
 	store *database.Store
 	queue TaskInserter
-	sei   SEIService
+	cache cache.Cache
+	cfg   *Config
 }
 
 // New cria uma nova instância de [Service].
-func New(pool *pgxpool.Pool, queue TaskInserter, sei SEIService) *Service {
+func New(pool *pgxpool.Pool, queue TaskInserter, cache cache.Cache, cfg *Config) *Service {
 	return &Service{
 		pool:  pool,
 		store: database.New(pool),
 		queue: queue,
-		sei:   sei,
+		cache: cache,
+		cfg:   cfg,
 	}
 }
 
@@ -91,26 +89,31 @@ func (s *Service) Cleanup(ctx context.Context, tx pgx.Tx, trigger auth.CleanupTr
 		return err
 	}
 
-	pa, err := store.GetProcessoAtribuido(ctx, analista.UsuarioID)
-	if errors.Is(err, database.ErrNotFound) {
-		return nil
-	}
+	paa, err := store.ListProcessosAtribuidos(ctx, analista.UsuarioID)
 	if err != nil {
 		return err
 	}
-
-	if err := s.saveHistorico(ctx, store, saveHistoricoParams{
-		ProcessoAposentadoriaID: pa.ID,
-		StatusAnterior:          &pa.Status,
-		StatusNovo:              database.StatusProcessoAnalisePendente,
-		Observacao:              "Processo desatribuído em razão de alteração do usuário",
-	}); err != nil {
-		return err
+	if len(paa) == 0 {
+		return nil
 	}
 
-	pa.Status = database.StatusProcessoAnalisePendente
-	pa.AnalistaID = sql.Null[int64]{}
-	pa.UltimoAnalistaID = sql.Null[int64]{}
+	for _, pa := range paa {
+		if err := s.saveHistorico(ctx, store, saveHistoricoParams{
+			ProcessoAposentadoriaID: pa.ID,
+			StatusAnterior:          &pa.Status,
+			StatusNovo:              database.StatusProcessoAnalisePendente,
+			Observacao:              "Processo desatribuído em razão de alteração do usuário",
+		}); err != nil {
+			return err
+		}
+
+		pa.Status = database.StatusProcessoAnalisePendente
+		pa.AnalistaID = sql.Null[int64]{}
+		pa.UltimoAnalistaID = sql.Null[int64]{}
+		if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
+			return err
+		}
+	}
 
-	return store.UpdateProcessoAposentadoria(ctx, pa)
+	return s.agendarAtribuicao(ctx, tx)
 }
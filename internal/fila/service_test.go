@@ -0,0 +1,116 @@
+package fila
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/cache"
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+	"github.com/riverqueue/river/rivertype"
+)
+
+var ti *postgres.TestInstance
+
+func TestMain(m *testing.M) {
+	ti = postgres.MustTestInstance()
+	defer ti.Close()
+	m.Run()
+}
+
+type fakeTaskInserter struct {
+	mu   sync.Mutex
+	jobs []river.JobArgs
+	opts []*river.InsertOpts
+	// duplicado simula a inserção de um job único que já está na fila.
+	duplicado bool
+}
+
+func (q *fakeTaskInserter) InsertTx(ctx context.Context, tx pgx.Tx, args river.JobArgs, opts *river.InsertOpts) (*rivertype.JobInsertResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, args)
+	q.opts = append(q.opts, opts)
+	return &rivertype.JobInsertResult{UniqueSkippedAsDuplicate: q.duplicado}, nil
+}
+
+type testEnv struct {
+	pool    *pgxpool.Pool
+	store   *database.Store
+	service *Service
+	queue   *fakeTaskInserter
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	pool := ti.NewDatabase(t)
+	queue := &fakeTaskInserter{}
+	svc := New(pool, queue, cache.NewMemoryCache(), &Config{})
+
+	return &testEnv{
+		pool:    pool,
+		store:   database.New(pool),
+		service: svc,
+		queue:   queue,
+	}
+}
+
+func seedUsuario(t *testing.T, store *database.Store, papel string) *database.Usuario {
+	t.Helper()
+
+	usuario := &database.Usuario{
+		Nome:  "Usuário " + papel,
+		CPF:   rand.Text(),
+		Email: rand.Text(),
+		Papel: sql.Null[string]{V: papel, Valid: true},
+	}
+	if err := store.SaveUsuario(t.Context(), usuario); err != nil {
+		t.Fatal(err)
+	}
+	return usuario
+}
+
+func seedAnalista(t *testing.T, store *database.Store) *database.Analista {
+	t.Helper()
+
+	usuario := seedUsuario(t, store, "ANALISTA")
+	analista := &database.Analista{
+		UsuarioID:       usuario.ID,
+		Orgao:           "SEPLAG",
+		SEIUnidadeID:    rand.Text(),
+		SEIUnidadeSigla: "SEPLAG/AP00",
+	}
+	if err := store.SaveAnalista(t.Context(), analista); err != nil {
+		t.Fatal(err)
+	}
+	return analista
+}
+
+func seedProcessoAposentadoria(t *testing.T, store *database.Store, status database.StatusProcesso) *database.ProcessoAposentadoria {
+	t.Helper()
+
+	p := &database.Processo{Numero: rand.Text(), SeiUnidadeID: rand.Text()}
+	if err := store.SaveProcesso(t.Context(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	pa := &database.ProcessoAposentadoria{
+		ProcessoID:               p.ID,
+		Status:                   status,
+		CPFRequerente:            "12345678901",
+		DataNascimentoRequerente: time.Date(1960, 5, 10, 0, 0, 0, 0, time.UTC),
+		DataRequerimento:         time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := store.SaveProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+	return pa
+}
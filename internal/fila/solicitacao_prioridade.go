@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/automatiza-mg/fila/internal/aposentadoria"
 	"github.com/automatiza-mg/fila/internal/auth"
 	"github.com/automatiza-mg/fila/internal/database"
 	"github.com/automatiza-mg/fila/internal/mail"
@@ -192,14 +191,7 @@ func (s *Service) AprovarSolicitacaoPrioridade(ctx context.Context, spID int64)
 	}
 
 	pa.Prioridade = true
-	pa.Score = aposentadoria.CalculateScore(
-		pa.DataNascimentoRequerente,
-		pa.Invalidez,
-		pa.Judicial,
-		pa.Prioridade,
-	)
-	err = store.UpdateProcessoAposentadoria(ctx, pa)
-	if err != nil {
+	if err := s.recalcularScore(ctx, store, pa); err != nil {
 		return err
 	}
 
@@ -234,14 +226,7 @@ func (s *Service) NegarSolicitacaoPrioridade(ctx context.Context, spID int64) er
 	}
 
 	pa.Prioridade = false
-	pa.Score = aposentadoria.CalculateScore(
-		pa.DataNascimentoRequerente,
-		pa.Invalidez,
-		pa.Judicial,
-		pa.Prioridade,
-	)
-	err = store.UpdateProcessoAposentadoria(ctx, pa)
-	if err != nil {
+	if err := s.recalcularScore(ctx, store, pa); err != nil {
 		return err
 	}
 
@@ -11,13 +11,22 @@ import (
 )
 
 type AnaliseAposentadoria struct {
-	Aposentadoria    bool   `json:"aposentadoria" jsonschema:"required" jsonschema_description:"Indica se o processo é ou não um pedido de aposentadoria"`
-	CPF              string `json:"cpf_requerente" jsonschema:"required" jsonschema_description:"O CPF do requerente da aposentadoria, sem pontos e traços"`
-	DataRequerimento string `json:"data_requerimento" jsonschema:"required,format=date" jsonschema_description:"A data em que o requerimento foi enviado, no formato YYYY-MM-DD"`
-	DataNascimento   string `json:"data_nascimento_requerente" jsonschema:"required,format=date" jsonschema_description:"A data de nascimento do requerente, no formato YYYY-MM-DD"`
-	Judicial         bool   `json:"judicial" jsonschema:"required" jsonschema_description:"Indica se houve pedido judicial para dar início ao processo"`
-	Invalidez        bool   `json:"invalidez" jsonschema:"required" jsonschema_description:"Indica se o requerente abriu o processo por invalidez"`
-	CPFDiligencia    string `json:"cpf_responsavel_diligencia" jsonschema:"not_required" jsonschema_description:"O CPF do responsável pelo envio da diligência, se houver, sem pontos e traços"`
+	Aposentadoria    bool      `json:"aposentadoria" jsonschema:"required" jsonschema_description:"Indica se o processo é ou não um pedido de aposentadoria"`
+	CPF              string    `json:"cpf_requerente" jsonschema:"required" jsonschema_description:"O CPF do requerente da aposentadoria, sem pontos e traços"`
+	DataRequerimento string    `json:"data_requerimento" jsonschema:"required,format=date" jsonschema_description:"A data em que o requerimento foi enviado, no formato YYYY-MM-DD"`
+	DataNascimento   string    `json:"data_nascimento_requerente" jsonschema:"required,format=date" jsonschema_description:"A data de nascimento do requerente, no formato YYYY-MM-DD"`
+	Judicial         bool      `json:"judicial" jsonschema:"required" jsonschema_description:"Indica se houve pedido judicial para dar início ao processo"`
+	Invalidez        bool      `json:"invalidez" jsonschema:"required" jsonschema_description:"Indica se o requerente abriu o processo por invalidez"`
+	CPFDiligencia    string    `json:"cpf_responsavel_diligencia" jsonschema:"not_required" jsonschema_description:"O CPF do responsável pelo envio da diligência, se houver, sem pontos e traços"`
+	Citacoes         []Citacao `json:"citacoes" jsonschema:"required" jsonschema_description:"Os trechos dos documentos que comprovam cada dado extraído"`
+}
+
+// Citacao indica o documento e o trecho que comprovam um dado extraído pela
+// IA.
+type Citacao struct {
+	Campo     string `json:"campo" jsonschema:"required,enum=cpf_requerente,enum=data_requerimento,enum=data_nascimento_requerente,enum=judicial,enum=invalidez" jsonschema_description:"O nome do campo comprovado pelo trecho"`
+	Documento string `json:"documento" jsonschema:"required" jsonschema_description:"O número do documento que contém o trecho, como informado em Numero"`
+	Trecho    string `json:"trecho" jsonschema:"required" jsonschema_description:"Trecho curto copiado literalmente do documento que comprova o valor"`
 }
 
 type Assinatura struct {
@@ -26,6 +35,8 @@ type Assinatura struct {
 }
 
 type Documento struct {
+	// Numero é o número do documento no SEI, usado nas citações.
+	Numero      string
 	Tipo        string
 	Data        string
 	Conteudo    string
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAposentadoriaPrompt(t *testing.T) {
+	t.Parallel()
+
+	prompt, err := NewAposentadoriaPrompt(AposentadoriaPromptParams{
+		Documentos: []Documento{
+			{
+				Numero:   "81234567",
+				Tipo:     "Requerimento",
+				Data:     "2024-03-10",
+				Conteudo: "Requerimento de aposentadoria",
+				Assinaturas: []Assinatura{
+					{Nome: "João da Silva", CPF: "12345678900"},
+				},
+			},
+			{
+				Numero:   "81234568",
+				Tipo:     "Despacho",
+				Data:     "2024-03-12",
+				Conteudo: "Encaminhe-se",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(prompt.System, "citacoes") {
+		t.Fatal("expected system prompt to request citações")
+	}
+
+	for _, want := range []string{
+		"Numero: 81234567\nTipo: Requerimento",
+		"  - João da Silva (12345678900)",
+		"Numero: 81234568\nTipo: Despacho",
+	} {
+		if !strings.Contains(prompt.User, want) {
+			t.Fatalf("expected user prompt to contain %q, got:\n%s", want, prompt.User)
+		}
+	}
+}
@@ -13,6 +13,9 @@ var (
 	setupTmpl      = template.Must(template.ParseFS(fs, "templates/cadastro.tmpl"))
 	resetSenhaTmpl = template.Must(template.ParseFS(fs, "templates/reset-senha.tmpl"))
 	prioridadeTmpl = template.Must(template.ParseFS(fs, "templates/prioridade.tmpl"))
+	prazosTmpl     = template.Must(template.ParseFS(fs, "templates/prazos.tmpl"))
+	diligenciaTmpl = template.Must(template.ParseFS(fs, "templates/diligencia.tmpl"))
+	lembreteTmpl   = template.Must(template.ParseFS(fs, "templates/lembrete-diligencia.tmpl"))
 )
 
 func executeTemplate(tmpl *template.Template, to []string, data any) (*Email, error) {
@@ -66,3 +69,51 @@ type PrioridadeEmailParams struct {
 func NewPrioridadeEmail(to []string, params PrioridadeEmailParams) (*Email, error) {
 	return executeTemplate(prioridadeTmpl, to, params)
 }
+
+// PrazoProcessoEmail é um processo listado no resumo de prazos.
+type PrazoProcessoEmail struct {
+	Numero        string
+	Tipo          string
+	DataLimite    string
+	DiasRestantes int
+}
+
+type PrazosEmailParams struct {
+	Vencidos []PrazoProcessoEmail
+	Proximos []PrazoProcessoEmail
+	FilaURL  string
+}
+
+func NewPrazosEmail(to []string, params PrazosEmailParams) (*Email, error) {
+	return executeTemplate(prazosTmpl, to, params)
+}
+
+// ItemDiligenciaEmail é um item listado na notificação de diligência.
+type ItemDiligenciaEmail struct {
+	Tipo          string
+	Subcategorias []string
+	Detalhe       string
+}
+
+type DiligenciaEmailParams struct {
+	NumeroProcesso string
+	Unidade        string
+	PrazoResposta  string
+	Itens          []ItemDiligenciaEmail
+}
+
+func NewDiligenciaEmail(to []string, params DiligenciaEmailParams) (*Email, error) {
+	return executeTemplate(diligenciaTmpl, to, params)
+}
+
+type LembreteDiligenciaEmailParams struct {
+	NumeroProcesso string
+	Unidade        string
+	PrazoResposta  string
+	Vencida        bool
+	Itens          []ItemDiligenciaEmail
+}
+
+func NewLembreteDiligenciaEmail(to []string, params LembreteDiligenciaEmailParams) (*Email, error) {
+	return executeTemplate(lembreteTmpl, to, params)
+}
@@ -0,0 +1,15 @@
+package prazos
+
+// Config define os prazos, em dias corridos a partir da data do requerimento,
+// para cada tipo de processo de aposentadoria.
+type Config struct {
+	// Prazo dos processos judicializados.
+	DiasJudicial int `env:"PRAZO_DIAS_JUDICIAL" envDefault:"30"`
+	// Prazo dos processos com doença grave ou invalidez.
+	DiasInvalidez int `env:"PRAZO_DIAS_INVALIDEZ" envDefault:"60"`
+	// Prazo dos demais processos.
+	DiasOrdinario int `env:"PRAZO_DIAS_ORDINARIO" envDefault:"90"`
+	// Antecedência, em dias, com que um processo passa a ser sinalizado como
+	// próximo do vencimento.
+	DiasAlerta int `env:"PRAZO_DIAS_ALERTA" envDefault:"10"`
+}
@@ -0,0 +1,150 @@
+// Package prazos calcula os prazos legais dos processos de aposentadoria.
+//
+// O prazo é contado em dias corridos a partir da data do requerimento e fica
+// suspenso enquanto o processo está em diligência, já que a resposta depende
+// de outra unidade. Os períodos de suspensão são obtidos do histórico de
+// status do processo.
+package prazos
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+)
+
+// Tipo identifica a regra de prazo aplicada a um processo.
+type Tipo string
+
+const (
+	TipoJudicial  Tipo = "judicial"
+	TipoInvalidez Tipo = "invalidez"
+	TipoOrdinario Tipo = "ordinario"
+)
+
+// Situacao indica a situação de um processo em relação ao seu prazo.
+type Situacao string
+
+const (
+	SituacaoNoPrazo   Situacao = "no_prazo"
+	SituacaoProximo   Situacao = "proximo_vencimento"
+	SituacaoVencido   Situacao = "vencido"
+	SituacaoConcluido Situacao = "concluido"
+)
+
+// Prazo é o prazo calculado de um processo de aposentadoria.
+type Prazo struct {
+	Tipo          Tipo      `json:"tipo"`
+	Dias          int       `json:"dias"`
+	DataLimite    time.Time `json:"data_limite"`
+	DiasRestantes int       `json:"dias_restantes"`
+	DiasPausados  int       `json:"dias_pausados"`
+	Pausado       bool      `json:"pausado"`
+	Situacao      Situacao  `json:"situacao"`
+}
+
+// Periodo é um intervalo em que a contagem do prazo ficou suspensa. Fim é zero
+// quando a suspensão ainda está em curso.
+type Periodo struct {
+	Inicio time.Time
+	Fim    time.Time
+}
+
+// TipoProcesso retorna o tipo de prazo de um processo. Processos judicializados
+// têm precedência sobre os de invalidez.
+func TipoProcesso(pa *database.ProcessoAposentadoria) Tipo {
+	switch {
+	case pa.Judicial:
+		return TipoJudicial
+	case pa.Invalidez:
+		return TipoInvalidez
+	default:
+		return TipoOrdinario
+	}
+}
+
+func (c *Config) dias(t Tipo) int {
+	switch t {
+	case TipoJudicial:
+		return c.DiasJudicial
+	case TipoInvalidez:
+		return c.DiasInvalidez
+	default:
+		return c.DiasOrdinario
+	}
+}
+
+// Pausas retorna os períodos em que o processo esteve em diligência, a partir
+// do seu histórico de status.
+func Pausas(historico []*database.HistoricoStatusProcesso) []Periodo {
+	hh := slices.Clone(historico)
+	slices.SortStableFunc(hh, func(a, b *database.HistoricoStatusProcesso) int {
+		if c := a.AlteradoEm.Compare(b.AlteradoEm); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+
+	var (
+		pausas []Periodo
+		atual  *Periodo
+	)
+	for _, h := range hh {
+		emDiligencia := h.StatusNovo == database.StatusProcessoEmDiligencia
+		switch {
+		case emDiligencia && atual == nil:
+			atual = &Periodo{Inicio: h.AlteradoEm}
+		case !emDiligencia && atual != nil:
+			atual.Fim = h.AlteradoEm
+			pausas = append(pausas, *atual)
+			atual = nil
+		}
+	}
+	if atual != nil {
+		pausas = append(pausas, *atual)
+	}
+	return pausas
+}
+
+// Calcular calcula o prazo de um processo de aposentadoria em now, estendendo a
+// data limite pelo tempo em que o processo esteve em diligência.
+func Calcular(cfg *Config, pa *database.ProcessoAposentadoria, pausas []Periodo, now time.Time) *Prazo {
+	tipo := TipoProcesso(pa)
+	prazo := &Prazo{
+		Tipo: tipo,
+		Dias: cfg.dias(tipo),
+	}
+
+	var pausado time.Duration
+	for _, p := range pausas {
+		fim := p.Fim
+		if fim.IsZero() {
+			fim = now
+			prazo.Pausado = true
+		}
+		if fim.After(p.Inicio) {
+			pausado += fim.Sub(p.Inicio)
+		}
+	}
+
+	prazo.DiasPausados = int(pausado.Hours() / 24)
+	prazo.DataLimite = pa.DataRequerimento.AddDate(0, 0, prazo.Dias).Add(pausado)
+
+	if pa.Status == database.StatusProcessoConcluido {
+		prazo.Situacao = SituacaoConcluido
+		prazo.Pausado = false
+		return prazo
+	}
+
+	prazo.DiasRestantes = int(math.Floor(prazo.DataLimite.Sub(now).Hours() / 24))
+	switch {
+	case now.After(prazo.DataLimite):
+		prazo.Situacao = SituacaoVencido
+	case prazo.DiasRestantes <= cfg.DiasAlerta:
+		prazo.Situacao = SituacaoProximo
+	default:
+		prazo.Situacao = SituacaoNoPrazo
+	}
+	return prazo
+}
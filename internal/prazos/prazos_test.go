@@ -0,0 +1,149 @@
+package prazos
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/google/go-cmp/cmp"
+)
+
+func historico(id int64, anterior, novo database.StatusProcesso, em time.Time) *database.HistoricoStatusProcesso {
+	return &database.HistoricoStatusProcesso{
+		ID:             id,
+		StatusAnterior: sql.Null[database.StatusProcesso]{V: anterior, Valid: anterior != ""},
+		StatusNovo:     novo,
+		AlteradoEm:     em,
+	}
+}
+
+func TestPausas(t *testing.T) {
+	base := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	hh := []*database.HistoricoStatusProcesso{
+		// Fora de ordem para garantir a ordenação por data.
+		historico(4, database.StatusProcessoRetornoDiligencia, database.StatusProcessoEmAnalise, base.AddDate(0, 0, 20)),
+		historico(1, "", database.StatusProcessoAnalisePendente, base),
+		historico(2, database.StatusProcessoEmAnalise, database.StatusProcessoEmDiligencia, base.AddDate(0, 0, 5)),
+		// Tramitação no SEI registrada sem mudança de status.
+		historico(3, database.StatusProcessoEmDiligencia, database.StatusProcessoEmDiligencia, base.AddDate(0, 0, 6)),
+		// O retorno da diligência encerra a primeira pausa.
+		historico(5, database.StatusProcessoEmDiligencia, database.StatusProcessoRetornoDiligencia, base.AddDate(0, 0, 15)),
+		historico(6, database.StatusProcessoEmAnalise, database.StatusProcessoEmDiligencia, base.AddDate(0, 0, 30)),
+	}
+
+	expected := []Periodo{
+		{Inicio: base.AddDate(0, 0, 5), Fim: base.AddDate(0, 0, 15)},
+		{Inicio: base.AddDate(0, 0, 30)},
+	}
+	if diff := cmp.Diff(expected, Pausas(hh)); diff != "" {
+		t.Fatalf("mismatch:\n%s", diff)
+	}
+}
+
+func TestCalcular(t *testing.T) {
+	cfg := &Config{
+		DiasJudicial:  30,
+		DiasInvalidez: 60,
+		DiasOrdinario: 90,
+		DiasAlerta:    10,
+	}
+	requerimento := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pa       *database.ProcessoAposentadoria
+		pausas   []Periodo
+		now      time.Time
+		expected *Prazo
+	}{
+		{
+			name: "ordinário no prazo",
+			pa:   &database.ProcessoAposentadoria{DataRequerimento: requerimento, Status: database.StatusProcessoEmAnalise},
+			now:  requerimento.AddDate(0, 0, 30),
+			expected: &Prazo{
+				Tipo:          TipoOrdinario,
+				Dias:          90,
+				DataLimite:    requerimento.AddDate(0, 0, 90),
+				DiasRestantes: 60,
+				Situacao:      SituacaoNoPrazo,
+			},
+		},
+		{
+			name: "judicial próximo do vencimento",
+			pa: &database.ProcessoAposentadoria{
+				DataRequerimento: requerimento,
+				Judicial:         true,
+				Invalidez:        true,
+				Status:           database.StatusProcessoAnalisePendente,
+			},
+			now: requerimento.AddDate(0, 0, 25),
+			expected: &Prazo{
+				Tipo:          TipoJudicial,
+				Dias:          30,
+				DataLimite:    requerimento.AddDate(0, 0, 30),
+				DiasRestantes: 5,
+				Situacao:      SituacaoProximo,
+			},
+		},
+		{
+			name: "invalidez vencido",
+			pa: &database.ProcessoAposentadoria{
+				DataRequerimento: requerimento,
+				Invalidez:        true,
+				Status:           database.StatusProcessoEmAnalise,
+			},
+			now: requerimento.AddDate(0, 0, 62),
+			expected: &Prazo{
+				Tipo:          TipoInvalidez,
+				Dias:          60,
+				DataLimite:    requerimento.AddDate(0, 0, 60),
+				DiasRestantes: -2,
+				Situacao:      SituacaoVencido,
+			},
+		},
+		{
+			name: "pausas estendem a data limite",
+			pa: &database.ProcessoAposentadoria{
+				DataRequerimento: requerimento,
+				Invalidez:        true,
+				Status:           database.StatusProcessoEmDiligencia,
+			},
+			pausas: []Periodo{
+				{Inicio: requerimento.AddDate(0, 0, 10), Fim: requerimento.AddDate(0, 0, 20)},
+				{Inicio: requerimento.AddDate(0, 0, 50)},
+			},
+			now: requerimento.AddDate(0, 0, 65),
+			expected: &Prazo{
+				Tipo:          TipoInvalidez,
+				Dias:          60,
+				DataLimite:    requerimento.AddDate(0, 0, 85),
+				DiasRestantes: 20,
+				DiasPausados:  25,
+				Pausado:       true,
+				Situacao:      SituacaoNoPrazo,
+			},
+		},
+		{
+			name: "concluído",
+			pa:   &database.ProcessoAposentadoria{DataRequerimento: requerimento, Status: database.StatusProcessoConcluido},
+			now:  requerimento.AddDate(0, 0, 120),
+			expected: &Prazo{
+				Tipo:       TipoOrdinario,
+				Dias:       90,
+				DataLimite: requerimento.AddDate(0, 0, 90),
+				Situacao:   SituacaoConcluido,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calcular(cfg, tt.pa, tt.pausas, tt.now)
+			if diff := cmp.Diff(tt.expected, got); diff != "" {
+				t.Fatalf("mismatch:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package processos
+
+import (
+	"context"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/pagination"
+)
+
+// Ingestao representa uma execução da ingestão automática de processos.
+type Ingestao struct {
+	ID          int64           `json:"id"`
+	Unidade     string          `json:"unidade"`
+	DryRun      bool            `json:"dry_run"`
+	Encontrados int             `json:"encontrados"`
+	Existentes  int             `json:"existentes"`
+	Criados     int             `json:"criados"`
+	Ignorados   int             `json:"ignorados"`
+	Falhas      int             `json:"falhas"`
+	IniciadaEm  time.Time       `json:"iniciada_em"`
+	ConcluidaEm *time.Time      `json:"concluida_em"`
+	Erro        string          `json:"erro"`
+	Itens       []*ItemIngestao `json:"itens,omitempty"`
+}
+
+// ItemIngestao representa o resultado da ingestão de um processo.
+type ItemIngestao struct {
+	Numero    string `json:"numero"`
+	Resultado string `json:"resultado"`
+	Detalhe   string `json:"detalhe"`
+}
+
+func mapIngestao(ing *database.IngestaoProcessos) *Ingestao {
+	return &Ingestao{
+		ID:          ing.ID,
+		Unidade:     ing.Unidade,
+		DryRun:      ing.DryRun,
+		Encontrados: ing.Encontrados,
+		Existentes:  ing.Existentes,
+		Criados:     ing.Criados,
+		Ignorados:   ing.Ignorados,
+		Falhas:      ing.Falhas,
+		IniciadaEm:  ing.IniciadaEm,
+		ConcluidaEm: database.Ptr(ing.ConcluidaEm),
+		Erro:        ing.Erro,
+	}
+}
+
+type ListIngestoesParams struct {
+	Page  int
+	Limit int
+}
+
+// ListIngestoes retorna o relatório paginado das execuções de ingestão, sem
+// os itens de cada execução.
+func (s *Service) ListIngestoes(ctx context.Context, params ListIngestoesParams) (*pagination.Result[*Ingestao], error) {
+	offset := pagination.Offset(params.Page, params.Limit)
+
+	ii, totalCount, err := s.store.ListIngestoesProcessos(ctx, database.ListIngestoesProcessosParams{
+		Limit:  params.Limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ingestoes := make([]*Ingestao, len(ii))
+	for i, ing := range ii {
+		ingestoes[i] = mapIngestao(ing)
+	}
+
+	return pagination.NewResult(ingestoes, params.Page, totalCount, params.Limit), nil
+}
+
+// GetIngestao retorna uma execução de ingestão com todos os seus itens.
+func (s *Service) GetIngestao(ctx context.Context, id int64) (*Ingestao, error) {
+	ing, err := s.store.GetIngestaoProcessos(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	itens, err := s.store.ListItensIngestaoProcessos(ctx, ing.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := mapIngestao(ing)
+	result.Itens = make([]*ItemIngestao, len(itens))
+	for i, item := range itens {
+		result.Itens[i] = &ItemIngestao{
+			Numero:    item.Numero,
+			Resultado: string(item.Resultado),
+			Detalhe:   item.Detalhe,
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package relatorios
+
+import (
+	"cmp"
+	"context"
+	"io"
+	"slices"
+	"time"
+)
+
+// Devolucao é um processo devolvido à fila por um analista.
+type Devolucao struct {
+	ProcessoAposentadoriaID int64     `json:"processo_aposentadoria_id"`
+	Numero                  string    `json:"numero"`
+	AnalistaID              int64     `json:"analista_id"`
+	Analista                string    `json:"analista"`
+	Unidade                 string    `json:"unidade"`
+	Motivo                  string    `json:"motivo"`
+	ExcluirAnalista         bool      `json:"excluir_analista"`
+	DevolvidoEm             time.Time `json:"devolvido_em"`
+}
+
+// DevolucoesAnalista totaliza as devoluções de um analista no período.
+type DevolucoesAnalista struct {
+	AnalistaID int64  `json:"analista_id"`
+	Analista   string `json:"analista"`
+	Total      int    `json:"total"`
+	Exclusoes  int    `json:"exclusoes"`
+}
+
+// RelatorioDevolucoes é o relatório dos processos devolvidos à fila pelos
+// analistas em um período. Fim não pertence ao período.
+type RelatorioDevolucoes struct {
+	Inicio     time.Time             `json:"inicio"`
+	Fim        time.Time             `json:"fim"`
+	Totais     []*DevolucoesAnalista `json:"totais"`
+	Devolucoes []*Devolucao          `json:"devolucoes"`
+}
+
+// Devolucoes gera o relatório de devoluções de processos no intervalo
+// [inicio, fim). Os totais são ordenados do analista com mais devoluções para
+// o com menos.
+func (s *Service) Devolucoes(ctx context.Context, inicio, fim time.Time) (*RelatorioDevolucoes, error) {
+	rr, err := s.store.ListDevolucoesPeriodo(ctx, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &RelatorioDevolucoes{
+		Inicio:     inicio,
+		Fim:        fim,
+		Totais:     []*DevolucoesAnalista{},
+		Devolucoes: make([]*Devolucao, len(rr)),
+	}
+	totais := make(map[int64]*DevolucoesAnalista)
+	for i, r := range rr {
+		rel.Devolucoes[i] = &Devolucao{
+			ProcessoAposentadoriaID: r.ProcessoAposentadoriaID,
+			Numero:                  r.Numero,
+			AnalistaID:              r.AnalistaID,
+			Analista:                r.Analista,
+			Unidade:                 r.Unidade,
+			Motivo:                  r.Motivo,
+			ExcluirAnalista:         r.ExcluirAnalista,
+			DevolvidoEm:             r.CriadoEm,
+		}
+
+		t, ok := totais[r.AnalistaID]
+		if !ok {
+			t = &DevolucoesAnalista{AnalistaID: r.AnalistaID, Analista: r.Analista}
+			totais[r.AnalistaID] = t
+			rel.Totais = append(rel.Totais, t)
+		}
+		t.Total++
+		if r.ExcluirAnalista {
+			t.Exclusoes++
+		}
+	}
+	slices.SortStableFunc(rel.Totais, func(a, b *DevolucoesAnalista) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), cmp.Compare(a.Analista, b.Analista))
+	})
+
+	return rel, nil
+}
+
+var cabecalhoDevolucoes = []string{
+	"Processo",
+	"Analista",
+	"Unidade",
+	"Motivo",
+	"Analista excluído",
+	"Devolvido em",
+}
+
+func (r *RelatorioDevolucoes) linhas() [][]any {
+	linhas := make([][]any, 0, len(r.Devolucoes))
+	for _, d := range r.Devolucoes {
+		excluido := "Não"
+		if d.ExcluirAnalista {
+			excluido = "Sim"
+		}
+		linhas = append(linhas, []any{
+			d.Numero,
+			d.Analista,
+			d.Unidade,
+			d.Motivo,
+			excluido,
+			d.DevolvidoEm,
+		})
+	}
+	return linhas
+}
+
+// WriteCSV escreve as devoluções do relatório em formato CSV.
+func (r *RelatorioDevolucoes) WriteCSV(w io.Writer) error {
+	return WriteCSV(w, cabecalhoDevolucoes, r.linhas())
+}
+
+// WriteXLSX escreve as devoluções do relatório em formato XLSX.
+func (r *RelatorioDevolucoes) WriteXLSX(w io.Writer) error {
+	return WriteXLSX(w, "Devoluções", cabecalhoDevolucoes, r.linhas())
+}
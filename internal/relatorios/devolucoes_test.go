@@ -0,0 +1,35 @@
+package relatorios
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRelatorioDevolucoesWriteCSV(t *testing.T) {
+	rel := &RelatorioDevolucoes{
+		Inicio: time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC),
+		Fim:    time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC),
+		Devolucoes: []*Devolucao{
+			{
+				Numero:          "1500.01.0000001/2026-01",
+				Analista:        "Fulano",
+				Unidade:         "SEPLAG/AP01",
+				Motivo:          "Processo de parente",
+				ExcluirAnalista: true,
+				DevolvidoEm:     time.Date(2026, time.May, 4, 10, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := rel.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Processo,Analista,Unidade,Motivo,Analista excluído,Devolvido em\n" +
+		"1500.01.0000001/2026-01,Fulano,SEPLAG/AP01,Processo de parente,Sim,2026-05-04 10:30:00\n"
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
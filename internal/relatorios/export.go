@@ -0,0 +1,57 @@
+package relatorios
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/xlsx"
+)
+
+// WriteCSV escreve o cabeçalho e as linhas de um relatório em formato CSV.
+func WriteCSV(w io.Writer, cabecalho []string, linhas [][]any) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cabecalho); err != nil {
+		return err
+	}
+
+	record := make([]string, len(cabecalho))
+	for _, linha := range linhas {
+		for i, v := range linha {
+			switch v := v.(type) {
+			case string:
+				record[i] = v
+			case int:
+				record[i] = strconv.Itoa(v)
+			case float64:
+				record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			case time.Time:
+				record[i] = v.Format(time.DateTime)
+			default:
+				record[i] = ""
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteXLSX escreve o cabeçalho e as linhas de um relatório em uma planilha
+// XLSX com uma única aba.
+func WriteXLSX(w io.Writer, aba string, cabecalho []string, linhas [][]any) error {
+	rows := make([][]any, 0, len(linhas)+1)
+
+	header := make([]any, len(cabecalho))
+	for i, c := range cabecalho {
+		header[i] = c
+	}
+	rows = append(rows, header)
+	rows = append(rows, linhas...)
+
+	return xlsx.Write(w, aba, rows)
+}
@@ -0,0 +1,101 @@
+package relatorios
+
+import (
+	"context"
+	"io"
+	"math"
+	"time"
+)
+
+// ProdutividadeAnalista resume o que um analista fez no período do relatório.
+type ProdutividadeAnalista struct {
+	UsuarioID         int64   `json:"usuario_id"`
+	Nome              string  `json:"nome"`
+	Unidade           string  `json:"unidade"`
+	Concluidos        int     `json:"concluidos"`
+	Diligencias       int     `json:"diligencias"`
+	LeiturasInvalidas int     `json:"leituras_invalidas"`
+	Analises          int     `json:"analises"`
+	HorasEmAnalise    float64 `json:"horas_em_analise_media"`
+	DiasAfastado      float64 `json:"dias_afastado"`
+}
+
+// RelatorioProdutividade é o relatório de produtividade dos analistas em um
+// período. Fim não pertence ao período.
+type RelatorioProdutividade struct {
+	Inicio    time.Time                `json:"inicio"`
+	Fim       time.Time                `json:"fim"`
+	Analistas []*ProdutividadeAnalista `json:"analistas"`
+}
+
+// Produtividade gera o relatório de produtividade dos analistas no intervalo
+// [inicio, fim).
+func (s *Service) Produtividade(ctx context.Context, inicio, fim time.Time) (*RelatorioProdutividade, error) {
+	rr, err := s.store.ListProdutividadePeriodo(ctx, inicio, fim)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &RelatorioProdutividade{
+		Inicio:    inicio,
+		Fim:       fim,
+		Analistas: make([]*ProdutividadeAnalista, len(rr)),
+	}
+	for i, r := range rr {
+		rel.Analistas[i] = &ProdutividadeAnalista{
+			UsuarioID:         r.UsuarioID,
+			Nome:              r.Nome,
+			Unidade:           r.Unidade,
+			Concluidos:        r.Concluidos,
+			Diligencias:       r.Diligencias,
+			LeiturasInvalidas: r.LeiturasInvalidas,
+			Analises:          r.Analises,
+			HorasEmAnalise:    arredondar(r.HorasEmAnalise),
+			DiasAfastado:      arredondar(r.HorasAfastado / 24),
+		}
+	}
+	return rel, nil
+}
+
+var cabecalhoProdutividade = []string{
+	"Analista",
+	"Unidade",
+	"Concluídos",
+	"Diligências",
+	"Leituras inválidas",
+	"Análises",
+	"Tempo médio em análise (horas)",
+	"Dias afastado",
+}
+
+func (r *RelatorioProdutividade) linhas() [][]any {
+	linhas := make([][]any, 0, len(r.Analistas))
+	for _, a := range r.Analistas {
+		linhas = append(linhas, []any{
+			a.Nome,
+			a.Unidade,
+			a.Concluidos,
+			a.Diligencias,
+			a.LeiturasInvalidas,
+			a.Analises,
+			a.HorasEmAnalise,
+			a.DiasAfastado,
+		})
+	}
+	return linhas
+}
+
+// WriteCSV escreve o relatório em formato CSV.
+func (r *RelatorioProdutividade) WriteCSV(w io.Writer) error {
+	return WriteCSV(w, cabecalhoProdutividade, r.linhas())
+}
+
+// WriteXLSX escreve o relatório em formato XLSX.
+func (r *RelatorioProdutividade) WriteXLSX(w io.Writer) error {
+	return WriteXLSX(w, "Produtividade", cabecalhoProdutividade, r.linhas())
+}
+
+// arredondar arredonda v para duas casas decimais.
+func arredondar(v float64) float64 {
+	return math.Round(v*100) / 100
+}
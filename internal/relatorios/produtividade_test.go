@@ -0,0 +1,42 @@
+package relatorios
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRelatorioProdutividadeWriteCSV(t *testing.T) {
+	rel := &RelatorioProdutividade{
+		Inicio: time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC),
+		Fim:    time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC),
+		Analistas: []*ProdutividadeAnalista{
+			{
+				Nome:           "Fulano, da Silva",
+				Unidade:        "SEPLAG/AP01",
+				Concluidos:     4,
+				Diligencias:    1,
+				Analises:       5,
+				HorasEmAnalise: 12.5,
+				DiasAfastado:   2,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := rel.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Analista,Unidade,Concluídos,Diligências,Leituras inválidas,Análises,Tempo médio em análise (horas),Dias afastado\n" +
+		"\"Fulano, da Silva\",SEPLAG/AP01,4,1,0,5,12.5,2\n"
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestArredondar(t *testing.T) {
+	if got := arredondar(1.23456); got != 1.23 {
+		t.Fatalf("expected 1.23, got %v", got)
+	}
+}
@@ -0,0 +1,20 @@
+// Package relatorios gera os relatórios gerenciais da fila de processos de
+// aposentadoria.
+package relatorios
+
+import (
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Service gera os relatórios da aplicação.
+type Service struct {
+	store *database.Store
+}
+
+// New cria uma nova instância de [Service].
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{
+		store: database.New(pool),
+	}
+}
@@ -52,3 +52,59 @@ func (c *Client) ConsultarDocumento(ctx context.Context, protocolo string) (*Con
 		SinRetornarAssinaturas: "S",
 	})
 }
+
+// Tipos de documento aceitos por [Client.IncluirDocumento].
+const (
+	// DocumentoGerado é um documento editado no SEI, cujo conteúdo é HTML.
+	DocumentoGerado = "G"
+	// DocumentoRecebido é um documento externo, anexado como arquivo.
+	DocumentoRecebido = "R"
+)
+
+// Documento descreve um documento a ser incluído em um processo do SEI. O
+// conteúdo deve estar codificado em base64.
+type Documento struct {
+	Tipo                  string
+	IdProcedimento        string `xml:",omitempty"`
+	ProtocoloProcedimento string `xml:",omitempty"`
+	IdSerie               string
+	Numero                string `xml:",omitempty"`
+	Data                  string `xml:",omitempty"`
+	Descricao             string `xml:",omitempty"`
+	Observacao            string `xml:",omitempty"`
+	NomeArquivo           string `xml:",omitempty"`
+	NivelAcesso           string `xml:",omitempty"`
+	IdHipoteseLegal       string `xml:",omitempty"`
+	Conteudo              string `xml:",omitempty"`
+	SinBloqueado          string `xml:",omitempty"`
+}
+
+type IncluirDocumentoRequest struct {
+	XMLName              xml.Name `xml:"Sei incluirDocumento"`
+	SiglaSistema         string
+	IdentificacaoServico string
+	IdUnidade            string
+	Documento            Documento
+}
+
+type RetornoInclusaoDocumento struct {
+	IdDocumento        string
+	DocumentoFormatado string
+	LinkAcesso         string
+}
+
+type IncluirDocumentoResponse struct {
+	XMLName    xml.Name                 `xml:"Sei incluirDocumentoResponse"`
+	Parametros RetornoInclusaoDocumento `xml:"parametros"`
+}
+
+// IncluirDocumento inclui um documento em um processo do SEI, a partir da
+// unidade informada. O processo deve estar aberto na unidade.
+func (c *Client) IncluirDocumento(ctx context.Context, unidade string, doc Documento) (*IncluirDocumentoResponse, error) {
+	return doReq[IncluirDocumentoRequest, IncluirDocumentoResponse](ctx, c, IncluirDocumentoRequest{
+		SiglaSistema:         c.cfg.SiglaSistema,
+		IdentificacaoServico: c.cfg.IdentificacaoServico,
+		IdUnidade:            unidade,
+		Documento:            doc,
+	})
+}
@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/automatiza-mg/fila/internal/llm"
 	"github.com/automatiza-mg/fila/internal/sei"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/riverqueue/river"
 )
@@ -26,6 +28,10 @@ const (
 
 type AnalisarProcessoArgs struct {
 	ProcessoID uuid.UUID `json:"processo_id"`
+	// SolicitadoPor é o usuário que solicitou o reprocessamento de um processo
+	// com leitura inválida. Quando informado, o processo de aposentadoria
+	// existente é atualizado com os dados da nova extração.
+	SolicitadoPor int64 `json:"solicitado_por,omitempty"`
 }
 
 func (args AnalisarProcessoArgs) Kind() string {
@@ -61,6 +67,16 @@ func (w *AnalisarProcessoWorker) Work(ctx context.Context, job *river.Job[Analis
 		return fmt.Errorf("failed to get processo: %w", err)
 	}
 
+	// Apenas processos com leitura inválida podem ser reprocessados, e somente
+	// a pedido de um usuário.
+	existente, err := w.store.GetProcessoAposentadoriaByProcessoID(ctx, p.ID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("failed to get processo aposentadoria: %w", err)
+	}
+	if existente != nil && (job.Args.SolicitadoPor == 0 || existente.Status != database.StatusProcessoLeituraInvalid) {
+		return river.JobCancel(fmt.Errorf("processo aposentadoria %d já existe com status %s", existente.ID, existente.Status))
+	}
+
 	dd, err := w.store.ListDocumentos(ctx, p.ID)
 	if err != nil {
 		return fmt.Errorf("failed to list docs: %w", err)
@@ -101,66 +117,66 @@ func (w *AnalisarProcessoWorker) Work(ctx context.Context, job *river.Job[Analis
 		return fmt.Errorf("failed to marshal analise: %w", err)
 	}
 
+	if existente != nil {
+		pendente, err := w.reprocessar(ctx, store, p, existente, analise, job.Args.SolicitadoPor)
+		if err != nil {
+			return err
+		}
+		if err := store.UpdateProcesso(ctx, p); err != nil {
+			return fmt.Errorf("failed to update processo: %w", err)
+		}
+		if pendente {
+			client := river.ClientFromContext[pgx.Tx](ctx)
+			_, err = client.InsertTx(ctx, tx, AtribuirProcessosArgs{}, nil)
+			if err != nil {
+				return fmt.Errorf("failed to insert atribuicao task: %w", err)
+			}
+		}
+		return tx.Commit(ctx)
+	}
+
 	// Atualiza e retorna.
 	if !analise.Aposentadoria {
 		return store.UpdateProcesso(ctx, p)
 	}
 
-	dataNascimento, err := time.Parse(time.DateOnly, analise.DataNascimento)
+	dados, fontes, err := w.dadosRequerente(ctx, p, analise)
 	if err != nil {
 		return err
 	}
 
-	invalidez := analise.Invalidez
-
-	var alertas []string
-
-	// Enriquece os dados do processo com informações do servidor no datalake.
-	servidor, err := w.servidorFetcher.GetServidor(ctx, analise.CPF)
+	versao, err := aposentadoria.LoadVersaoAtiva(ctx, store)
 	if err != nil {
-		w.logger.Warn("falha ao buscar servidor no datalake, usando dados da IA",
-			slog.String("cpf", analise.CPF),
-			slog.String("erro", err.Error()),
-		)
-		alertas = append(alertas, "Não foi possível obter os dados do servidor no datalake. Utilizando dados extraídos pela IA.")
-	} else {
-		dataNascimento = servidor.DataNascimento
-		invalidez = invalidez || servidor.PossuiDeficiencia
-	}
-
-	// Busca a informação complementar da data de recebimento do processo.
-	dataRequerimento, err := w.dataFetcher.GetDataRecebimento(ctx, p.Numero, UnidadeRecebimento)
-	if err != nil {
-		alertas = append(alertas, "Não foi possível obter a data de recebimento no SEI. Utilizando data extraída pela IA.")
-		dataRequerimento, err = time.Parse(time.DateOnly, analise.DataRequerimento)
-		if err != nil {
-			return err
-		}
+		return err
 	}
 
-	score := aposentadoria.CalculateScore(
-		dataNascimento,
-		invalidez,
-		analise.Judicial,
-		false,
-	)
-
 	pa := &database.ProcessoAposentadoria{
 		ProcessoID:               p.ID,
-		CPFRequerente:            analise.CPF,
-		Invalidez:                invalidez,
-		Judicial:                 analise.Judicial,
-		DataNascimentoRequerente: dataNascimento,
-		DataRequerimento:         dataRequerimento,
+		CPFRequerente:            dados.CPF,
+		Invalidez:                dados.Invalidez,
+		Judicial:                 dados.Judicial,
+		DataNascimentoRequerente: dados.DataNascimento,
+		DataRequerimento:         dados.DataRequerimento,
 		Status:                   database.StatusProcessoAnalisePendente,
-		Score:                    score,
-		Alertas:                  alertas,
 	}
+	score := versao.Calcular(pa, time.Now())
+	pa.Score = score.Score
+
 	err = store.SaveProcessoAposentadoria(ctx, pa)
 	if err != nil {
 		return err
 	}
 
+	err = versao.Registrar(ctx, store, pa.ID, sql.Null[int]{}, score)
+	if err != nil {
+		return fmt.Errorf("failed to save score: %w", err)
+	}
+
+	err = aposentadoria.SalvarFontes(ctx, store, pa.ID, fontes)
+	if err != nil {
+		return fmt.Errorf("failed to save fontes: %w", err)
+	}
+
 	hist := &database.HistoricoStatusProcesso{
 		ProcessoAposentadoriaID: pa.ID,
 		StatusNovo:              database.StatusProcessoAnalisePendente,
@@ -177,9 +193,129 @@ func (w *AnalisarProcessoWorker) Work(ctx context.Context, job *river.Job[Analis
 		return fmt.Errorf("failed to update processo: %w", err)
 	}
 
+	client := river.ClientFromContext[pgx.Tx](ctx)
+	_, err = client.InsertTx(ctx, tx, AtribuirProcessosArgs{}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to insert atribuicao task: %w", err)
+	}
+
 	return tx.Commit(ctx)
 }
 
+// dadosRequerente combina os dados extraídos pela IA com os dados do servidor
+// no datalake e a data de recebimento no SEI, retornando também a procedência
+// de cada campo.
+func (w *AnalisarProcessoWorker) dadosRequerente(ctx context.Context, p *database.Processo, analise *llm.AnaliseAposentadoria) (aposentadoria.DadosRequerente, []*database.FonteCampoProcesso, error) {
+	e := aposentadoria.Extracao{
+		IA: aposentadoria.DadosRequerente{
+			CPF:       analise.CPF,
+			Invalidez: analise.Invalidez,
+			Judicial:  analise.Judicial,
+		},
+	}
+
+	// As datas inválidas da IA só impedem a análise se não houver fonte
+	// externa para substituí-las.
+	dataNascimento, errNascimento := time.Parse(time.DateOnly, analise.DataNascimento)
+	if errNascimento == nil {
+		e.IA.DataNascimento = dataNascimento
+	}
+	dataRequerimento, errRequerimento := time.Parse(time.DateOnly, analise.DataRequerimento)
+	if errRequerimento == nil {
+		e.IA.DataRequerimento = dataRequerimento
+	}
+
+	// Enriquece os dados do processo com informações do servidor no datalake.
+	servidor, err := w.servidorFetcher.GetServidor(ctx, analise.CPF)
+	if err != nil {
+		w.logger.Warn("falha ao buscar servidor no datalake, usando dados da IA",
+			slog.String("cpf", analise.CPF),
+			slog.String("erro", err.Error()),
+		)
+	} else {
+		e.Servidor = servidor
+	}
+	if e.Servidor == nil && errNascimento != nil {
+		return aposentadoria.DadosRequerente{}, nil, errNascimento
+	}
+
+	// Busca a informação complementar da data de recebimento do processo.
+	dataRecebimento, err := w.dataFetcher.GetDataRecebimento(ctx, p.Numero, UnidadeRecebimento)
+	if err == nil {
+		e.DataRecebimento = &dataRecebimento
+	} else if errRequerimento != nil {
+		return aposentadoria.DadosRequerente{}, nil, errRequerimento
+	}
+
+	dados, fontes := e.Dados()
+	return dados, fontes, nil
+}
+
+// reprocessar atualiza um processo com leitura inválida com os dados da nova
+// extração, registrando os campos alterados, e o devolve para a fila como
+// ANALISE_PENDENTE com o score recalculado, retornando true. Se a nova análise
+// não identificar um pedido de aposentadoria, o processo permanece com leitura
+// inválida e o resultado é registrado nos eventos.
+func (w *AnalisarProcessoWorker) reprocessar(ctx context.Context, store *database.Store, p *database.Processo, pa *database.ProcessoAposentadoria, analise *llm.AnaliseAposentadoria, usuarioID int64) (bool, error) {
+	usuario := sql.Null[int64]{V: usuarioID, Valid: true}
+
+	if !analise.Aposentadoria {
+		err := store.SaveEventoProcesso(ctx, &database.EventoProcesso{
+			ProcessoAposentadoriaID: pa.ID,
+			Tipo:                    database.EventoReprocessamento,
+			Descricao:               "Reprocessamento não identificou um pedido de aposentadoria",
+			UsuarioID:               usuario,
+		})
+		return false, err
+	}
+
+	dados, fontes, err := w.dadosRequerente(ctx, p, analise)
+	if err != nil {
+		return false, err
+	}
+
+	// Os alertas de texto da extração anterior foram substituídos pela
+	// procedência registrada agora e não valem para os novos dados.
+	pa.Alertas = aposentadoria.RemoverAlertasExtracao(pa.Alertas)
+	alteracoes, err := aposentadoria.AtualizarDados(ctx, store, pa, dados, database.OrigemAlteracaoReprocessamento, "", usuario)
+	if err != nil {
+		return false, fmt.Errorf("failed to update dados: %w", err)
+	}
+	if err := aposentadoria.SalvarFontes(ctx, store, pa.ID, fontes); err != nil {
+		return false, fmt.Errorf("failed to save fontes: %w", err)
+	}
+
+	versao, err := aposentadoria.LoadVersaoAtiva(ctx, store)
+	if err != nil {
+		return false, err
+	}
+
+	anterior := sql.Null[int]{V: pa.Score, Valid: true}
+	statusAnterior := pa.Status
+	score := versao.Calcular(pa, time.Now())
+	pa.Score = score.Score
+	pa.Status = database.StatusProcessoAnalisePendente
+	if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
+		return false, err
+	}
+	if err := versao.Registrar(ctx, store, pa.ID, anterior, score); err != nil {
+		return false, fmt.Errorf("failed to save score: %w", err)
+	}
+
+	hist := &database.HistoricoStatusProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		StatusAnterior:          sql.Null[database.StatusProcesso]{V: statusAnterior, Valid: true},
+		StatusNovo:              database.StatusProcessoAnalisePendente,
+		UsuarioID:               usuario,
+	}
+	hist.SetObservacao(fmt.Sprintf("Processo reprocessado após leitura inválida, %d campo(s) alterado(s)", len(alteracoes)))
+	if err := store.SaveHistoricoStatusProcesso(ctx, hist); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func NewAnalisarProcessoWorker(pool *pgxpool.Pool, logger *slog.Logger, llm *llm.Client, dataFetcher DataRecebimentoFetcher, servidorFetcher ServidorFetcher) *AnalisarProcessoWorker {
 	return &AnalisarProcessoWorker{
 		pool:            pool,
@@ -217,6 +353,7 @@ func mapDocumentos(dd []*database.Documento, arquivoMap map[string]*database.Arq
 		}
 
 		docs = append(docs, llm.Documento{
+			Numero:      d.Numero,
 			Tipo:        d.Tipo,
 			Data:        seiDoc.Data,
 			Conteudo:    conteudo,
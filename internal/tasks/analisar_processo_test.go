@@ -0,0 +1,174 @@
+package tasks
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/datalake"
+	"github.com/automatiza-mg/fila/internal/llm"
+	"github.com/automatiza-mg/fila/internal/postgres"
+)
+
+var ti *postgres.TestInstance
+
+func TestMain(m *testing.M) {
+	ti = postgres.MustTestInstance()
+	defer ti.Close()
+	m.Run()
+}
+
+// fontesIndisponiveis simula a falha das fontes externas, de forma que os
+// dados da IA sejam usados.
+type fontesIndisponiveis struct{}
+
+func (fontesIndisponiveis) GetDataRecebimento(ctx context.Context, numero, unidade string) (time.Time, error) {
+	return time.Time{}, errors.New("sei indisponível")
+}
+
+func (fontesIndisponiveis) GetServidor(ctx context.Context, cpf string) (*datalake.Servidor, error) {
+	return nil, errors.New("datalake indisponível")
+}
+
+func newTestAnalisarProcessoWorker(t *testing.T) (*AnalisarProcessoWorker, *database.Store) {
+	t.Helper()
+
+	pool := ti.NewDatabase(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	w := NewAnalisarProcessoWorker(pool, logger, nil, fontesIndisponiveis{}, fontesIndisponiveis{})
+	return w, w.store
+}
+
+func seedLeituraInvalida(t *testing.T, store *database.Store) (*database.Processo, *database.ProcessoAposentadoria, *database.Usuario) {
+	t.Helper()
+
+	gestor := &database.Usuario{CPF: rand.Text(), Email: rand.Text()}
+	if err := store.SaveUsuario(t.Context(), gestor); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &database.Processo{Numero: rand.Text(), SeiUnidadeID: rand.Text()}
+	if err := store.SaveProcesso(t.Context(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	pa := &database.ProcessoAposentadoria{
+		ProcessoID:               p.ID,
+		Status:                   database.StatusProcessoLeituraInvalid,
+		CPFRequerente:            "12345678901",
+		DataNascimentoRequerente: time.Date(1960, 5, 10, 0, 0, 0, 0, time.UTC),
+		DataRequerimento:         time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Alertas: []string{
+			"Não foi possível obter a data de recebimento no SEI. Utilizando data extraída pela IA.",
+			"Falha ao enviar processo no SEI",
+		},
+	}
+	if err := store.SaveProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+
+	return p, pa, gestor
+}
+
+func TestReprocessar(t *testing.T) {
+	t.Parallel()
+	w, store := newTestAnalisarProcessoWorker(t)
+	p, pa, gestor := seedLeituraInvalida(t, store)
+
+	pendente, err := w.reprocessar(t.Context(), store, p, pa, &llm.AnaliseAposentadoria{
+		Aposentadoria:    true,
+		CPF:              "98765432100",
+		DataNascimento:   "1960-05-10",
+		DataRequerimento: "2024-03-01",
+		Invalidez:        true,
+	}, gestor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pendente {
+		t.Fatal("expected processo to return to the fila")
+	}
+
+	got, err := store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != database.StatusProcessoAnalisePendente || got.CPFRequerente != "98765432100" || !got.Invalidez {
+		t.Fatalf("unexpected processo: %+v", got)
+	}
+	if len(got.Alertas) != 1 || got.Alertas[0] != "Falha ao enviar processo no SEI" {
+		t.Fatalf("expected only the extraction alerta to be removed, got %v", got.Alertas)
+	}
+
+	aa, err := store.ListAlteracoesCamposProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aa) != 2 {
+		t.Fatalf("expected 2 alterações, got %d", len(aa))
+	}
+	for _, a := range aa {
+		if a.Origem != database.OrigemAlteracaoReprocessamento || a.UsuarioID.V != gestor.ID {
+			t.Fatalf("unexpected alteração: %+v", a)
+		}
+	}
+
+	fontes, err := store.ListFontesCamposProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fontes) != 5 {
+		t.Fatalf("expected 5 fontes, got %d", len(fontes))
+	}
+
+	hh, err := store.ListHistoricoStatusProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 1 || hh[0].StatusAnterior.V != database.StatusProcessoLeituraInvalid || hh[0].StatusNovo != database.StatusProcessoAnalisePendente {
+		t.Fatalf("unexpected histórico: %+v", hh)
+	}
+}
+
+func TestReprocessar_SemAposentadoria(t *testing.T) {
+	t.Parallel()
+	w, store := newTestAnalisarProcessoWorker(t)
+	p, pa, gestor := seedLeituraInvalida(t, store)
+
+	pendente, err := w.reprocessar(t.Context(), store, p, pa, &llm.AnaliseAposentadoria{}, gestor.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pendente {
+		t.Fatal("expected processo to keep leitura inválida")
+	}
+
+	got, err := store.GetProcessoAposentadoria(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != database.StatusProcessoLeituraInvalid {
+		t.Fatalf("expected status LEITURA_INVALIDA, got %s", got.Status)
+	}
+
+	// O resultado é registrado nos eventos, sem entrada no histórico.
+	hh, err := store.ListHistoricoStatusProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 0 {
+		t.Fatalf("expected no histórico, got %d", len(hh))
+	}
+	ee, err := store.ListEventosProcesso(t.Context(), pa.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ee) != 1 || ee[0].Tipo != database.EventoReprocessamento || ee[0].UsuarioID.V != gestor.ID {
+		t.Fatalf("unexpected eventos: %+v", ee)
+	}
+}
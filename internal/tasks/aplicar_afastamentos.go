@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/riverqueue/river"
+)
+
+// AplicarAfastamentosArgs são os argumentos para o job periódico que inicia e
+// encerra os afastamentos programados dos analistas.
+type AplicarAfastamentosArgs struct{}
+
+func (args AplicarAfastamentosArgs) Kind() string {
+	return "analista:aplicar-afastamentos"
+}
+
+func (args AplicarAfastamentosArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue: river.QueueDefault,
+	}
+}
+
+// AplicarAfastamentosFunc inicia os afastamentos programados que chegaram ao
+// início e encerra os que chegaram ao fim, retornando as quantidades de cada.
+type AplicarAfastamentosFunc func(ctx context.Context) (iniciados, encerrados int, err error)
+
+// AplicarAfastamentosWorker aplica os afastamentos programados dos analistas.
+type AplicarAfastamentosWorker struct {
+	aplicar AplicarAfastamentosFunc
+	logger  *slog.Logger
+	river.WorkerDefaults[AplicarAfastamentosArgs]
+}
+
+// NewAplicarAfastamentosWorker cria uma nova instância de [AplicarAfastamentosWorker].
+func NewAplicarAfastamentosWorker(logger *slog.Logger, aplicar AplicarAfastamentosFunc) *AplicarAfastamentosWorker {
+	return &AplicarAfastamentosWorker{
+		aplicar: aplicar,
+		logger:  logger.With(slog.String("worker", "aplicar_afastamentos")),
+	}
+}
+
+func (w *AplicarAfastamentosWorker) Work(ctx context.Context, job *river.Job[AplicarAfastamentosArgs]) error {
+	iniciados, encerrados, err := w.aplicar(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply afastamentos: %w", err)
+	}
+	if iniciados > 0 || encerrados > 0 {
+		w.logger.Info("afastamentos programados aplicados",
+			slog.Int("iniciados", iniciados),
+			slog.Int("encerrados", encerrados),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/riverqueue/river"
+)
+
+// AtribuirProcessosArgs são os argumentos para o job que atribui processos aos
+// analistas disponíveis. O job é agendado na mesma transação dos eventos que
+// liberam um analista ou colocam um processo na fila.
+type AtribuirProcessosArgs struct {
+	// Devolucao é a devolução que agendou a atribuição, se houver. O analista
+	// que devolveu o processo fica temporariamente excluído dele e a exclusão
+	// é removida ao fim da atribuição.
+	Devolucao *DevolucaoAtribuicao `json:"devolucao,omitempty"`
+}
+
+// DevolucaoAtribuicao identifica o processo devolvido à fila e o analista que
+// o devolveu.
+type DevolucaoAtribuicao struct {
+	ProcessoAposentadoriaID int64 `json:"processo_aposentadoria_id"`
+	AnalistaID              int64 `json:"analista_id"`
+}
+
+func (args AtribuirProcessosArgs) Kind() string {
+	return "fila:atribuir-processos"
+}
+
+func (args AtribuirProcessosArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue: river.QueueDefault,
+	}
+}
+
+// AtribuirProcessosFunc atribui processos enquanto houver analistas disponíveis
+// e processos aguardando análise, retornando a quantidade de atribuições.
+type AtribuirProcessosFunc func(ctx context.Context) (int, error)
+
+// LiberarExclusaoFunc remove a exclusão temporária do analista que devolveu o
+// processo.
+type LiberarExclusaoFunc func(ctx context.Context, paID, analistaID int64) error
+
+// AtribuirProcessosWorker executa a atribuição de processos da fila.
+type AtribuirProcessosWorker struct {
+	atribuir AtribuirProcessosFunc
+	liberar  LiberarExclusaoFunc
+	logger   *slog.Logger
+	river.WorkerDefaults[AtribuirProcessosArgs]
+}
+
+// NewAtribuirProcessosWorker cria uma nova instância de [AtribuirProcessosWorker].
+func NewAtribuirProcessosWorker(logger *slog.Logger, atribuir AtribuirProcessosFunc, liberar LiberarExclusaoFunc) *AtribuirProcessosWorker {
+	return &AtribuirProcessosWorker{
+		atribuir: atribuir,
+		liberar:  liberar,
+		logger:   logger.With(slog.String("worker", "atribuir_processos")),
+	}
+}
+
+func (w *AtribuirProcessosWorker) Work(ctx context.Context, job *river.Job[AtribuirProcessosArgs]) error {
+	n, err := w.atribuir(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to assign processos: %w", err)
+	}
+	if n > 0 {
+		w.logger.Info("processos atribuídos", slog.Int("total", n))
+	}
+
+	if d := job.Args.Devolucao; d != nil {
+		if err := w.liberar(ctx, d.ProcessoAposentadoriaID, d.AnalistaID); err != nil {
+			return fmt.Errorf("failed to release exclusion: %w", err)
+		}
+	}
+	return nil
+}
@@ -22,7 +22,16 @@ import (
 )
 
 type DownloadProcessoArgs struct {
-	ProcessoID uuid.UUID `json:"processo_id"`
+	// ProcessoID identifica o job nas inserções únicas por argumentos, como a
+	// do reprocessamento.
+	ProcessoID uuid.UUID `json:"processo_id" river:"unique"`
+	// SemAnalise indica que os documentos devem ser apenas atualizados, sem
+	// enfileirar uma nova análise de IA. Usado no retorno de diligência, quando
+	// o processo de aposentadoria já existe.
+	SemAnalise bool `json:"sem_analise,omitempty"`
+	// SolicitadoPor é o usuário que solicitou o reprocessamento do processo,
+	// repassado para a análise de IA.
+	SolicitadoPor int64 `json:"solicitado_por,omitempty"`
 }
 
 func (args DownloadProcessoArgs) Kind() string {
@@ -164,12 +173,15 @@ func (w *DownloadProcessoWorker) Work(ctx context.Context, job *river.Job[Downlo
 		return fmt.Errorf("failed to update processo: %w", err)
 	}
 
-	client := river.ClientFromContext[pgx.Tx](ctx)
-	_, err = client.InsertTx(ctx, tx, AnalisarProcessoArgs{
-		ProcessoID: p.ID,
-	}, nil)
-	if err != nil {
-		return fmt.Errorf("failed to insert analise task: %w", err)
+	if !job.Args.SemAnalise {
+		client := river.ClientFromContext[pgx.Tx](ctx)
+		_, err = client.InsertTx(ctx, tx, AnalisarProcessoArgs{
+			ProcessoID:    p.ID,
+			SolicitadoPor: job.Args.SolicitadoPor,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to insert analise task: %w", err)
+		}
 	}
 
 	return tx.Commit(ctx)
@@ -0,0 +1,172 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/sei"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// EnviarProcessoSEIArgs são os argumentos para o job que tramita um processo
+// de aposentadoria entre unidades do SEI.
+type EnviarProcessoSEIArgs struct {
+	ProcessoAposentadoriaID int64  `json:"processo_aposentadoria_id"`
+	UnidadeOrigem           string `json:"unidade_origem"`
+	UnidadeDestino          string `json:"unidade_destino"`
+	// Motivo descreve a ação que originou a tramitação e é usado nos
+	// eventos e nos alertas do processo.
+	Motivo string `json:"motivo"`
+	// RegistrarDocumentos indica que os documentos do processo no SEI devem ser
+	// registrados antes da tramitação, para que o retorno da diligência seja
+	// identificado pelos documentos incluídos depois do envio.
+	RegistrarDocumentos bool `json:"registrar_documentos,omitempty"`
+}
+
+func (args EnviarProcessoSEIArgs) Kind() string {
+	return "fila:enviar-processo-sei"
+}
+
+func (args EnviarProcessoSEIArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue:       river.QueueDefault,
+		MaxAttempts: 10,
+	}
+}
+
+// EnviarProcessoSEIWorker envia um processo para outra unidade do SEI e
+// registra o resultado nos eventos do processo de aposentadoria. Caso todas as
+// tentativas falhem, a falha também é registrada nos alertas do processo.
+type EnviarProcessoSEIWorker struct {
+	pool  *pgxpool.Pool
+	store *database.Store
+	sei   *sei.Client
+	river.WorkerDefaults[EnviarProcessoSEIArgs]
+}
+
+// NewEnviarProcessoSEIWorker cria uma nova instância de [EnviarProcessoSEIWorker].
+func NewEnviarProcessoSEIWorker(pool *pgxpool.Pool, sei *sei.Client) *EnviarProcessoSEIWorker {
+	return &EnviarProcessoSEIWorker{
+		pool:  pool,
+		store: database.New(pool),
+		sei:   sei,
+	}
+}
+
+func (w *EnviarProcessoSEIWorker) Work(ctx context.Context, job *river.Job[EnviarProcessoSEIArgs]) error {
+	args := job.Args
+
+	numero, err := w.store.GetNumeroProcessoAposentadoria(ctx, args.ProcessoAposentadoriaID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return river.JobCancel(err)
+		}
+		return fmt.Errorf("failed to get numero: %w", err)
+	}
+
+	if args.RegistrarDocumentos {
+		if err := w.registrarDocumentos(ctx, args.ProcessoAposentadoriaID); err != nil {
+			return fmt.Errorf("failed to register documentos: %w", err)
+		}
+	}
+
+	res, err := w.sei.EnviarProcesso(ctx, numero, args.UnidadeOrigem, []string{args.UnidadeDestino})
+	if err != nil {
+		if job.Attempt >= job.MaxAttempts {
+			obs := fmt.Sprintf("Falha ao enviar processo no SEI para a unidade %s (%s): %v", args.UnidadeDestino, args.Motivo, err)
+			if rerr := w.registrar(ctx, args.ProcessoAposentadoriaID, obs, true); rerr != nil {
+				return errors.Join(err, rerr)
+			}
+		}
+		return fmt.Errorf("failed to send processo: %w", err)
+	}
+
+	obs := fmt.Sprintf("Processo enviado no SEI para a unidade %s (%s)", args.UnidadeDestino, args.Motivo)
+	if resposta := strings.TrimSpace(res.Parametros); resposta != "" {
+		obs = fmt.Sprintf("%s. Resposta do SEI: %s", obs, resposta)
+	}
+	return w.registrar(ctx, args.ProcessoAposentadoriaID, obs, false)
+}
+
+// registrarDocumentos registra os números dos documentos do processo no SEI,
+// caso ainda não tenham sido registrados para a diligência em curso.
+func (w *EnviarProcessoSEIWorker) registrarDocumentos(ctx context.Context, paID int64) error {
+	_, err := w.store.GetDocumentosSEIDiligencia(ctx, paID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, database.ErrNotFound) {
+		return err
+	}
+
+	pa, err := w.store.GetProcessoAposentadoria(ctx, paID)
+	if err != nil {
+		return err
+	}
+	return salvarDocumentosSEI(ctx, w.store, w.sei, pa)
+}
+
+// salvarDocumentosSEI lista os documentos do processo no SEI e registra os
+// seus números como referência para o retorno da diligência.
+func salvarDocumentosSEI(ctx context.Context, store *database.Store, client *sei.Client, pa *database.ProcessoAposentadoria) error {
+	p, err := store.GetProcesso(ctx, pa.ProcessoID)
+	if err != nil {
+		return err
+	}
+
+	docs, err := client.ListarDocumentos(ctx, p.LinkAcesso)
+	if err != nil {
+		return err
+	}
+
+	numeros := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Numero != "" {
+			numeros = append(numeros, doc.Numero)
+		}
+	}
+	return store.SaveDocumentosSEIDiligencia(ctx, pa.ID, numeros)
+}
+
+// registrar salva a tramitação nos eventos do processo e opcionalmente
+// adiciona a mesma mensagem aos alertas.
+func (w *EnviarProcessoSEIWorker) registrar(ctx context.Context, paID int64, obs string, alerta bool) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := registrarEvento(ctx, w.store.WithTx(tx), paID, database.EventoTramitacaoSEI, obs, alerta); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// registrarEvento salva um evento do processo, sem alteração de status, e
+// opcionalmente adiciona a mesma mensagem aos alertas.
+func registrarEvento(ctx context.Context, store *database.Store, paID int64, tipo, obs string, alerta bool) error {
+	err := store.SaveEventoProcesso(ctx, &database.EventoProcesso{
+		ProcessoAposentadoriaID: paID,
+		Tipo:                    tipo,
+		Descricao:               obs,
+	})
+	if err != nil {
+		return err
+	}
+
+	if alerta {
+		return store.AddAlertaProcessoAposentadoria(ctx, paID, obs)
+	}
+	return nil
+}
+
+func (w *EnviarProcessoSEIWorker) Timeout(job *river.Job[EnviarProcessoSEIArgs]) time.Duration {
+	return time.Minute
+}
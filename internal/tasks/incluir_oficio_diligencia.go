@@ -0,0 +1,180 @@
+package tasks
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/sei"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// IncluirOficioDiligenciaArgs são os argumentos para o job que inclui o ofício
+// de uma diligência no processo do SEI.
+type IncluirOficioDiligenciaArgs struct {
+	SolicitacaoDiligenciaID int64 `json:"solicitacao_diligencia_id"`
+	// Unidade do SEI em que o documento é incluído. O processo deve estar
+	// aberto nesta unidade.
+	Unidade string `json:"unidade"`
+	// Tipo de documento (série) do SEI usado para o ofício.
+	IdSerie string `json:"id_serie"`
+	// Tramitacao, quando informada, é agendada após a inclusão do ofício, para
+	// que o processo só deixe a unidade com o documento anexado.
+	Tramitacao *EnviarProcessoSEIArgs `json:"tramitacao,omitempty"`
+}
+
+func (args IncluirOficioDiligenciaArgs) Kind() string {
+	return "fila:incluir-oficio-diligencia"
+}
+
+func (args IncluirOficioDiligenciaArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue:       river.QueueDefault,
+		MaxAttempts: 10,
+	}
+}
+
+// IncluirOficioDiligenciaWorker inclui o ofício gerado no envio de uma
+// diligência como documento do processo no SEI e registra o número do
+// documento na solicitação. Caso todas as tentativas falhem, a falha é
+// registrada nos alertas do processo e a tramitação segue sem o ofício.
+type IncluirOficioDiligenciaWorker struct {
+	pool  *pgxpool.Pool
+	store *database.Store
+	sei   *sei.Client
+	river.WorkerDefaults[IncluirOficioDiligenciaArgs]
+}
+
+// NewIncluirOficioDiligenciaWorker cria uma nova instância de [IncluirOficioDiligenciaWorker].
+func NewIncluirOficioDiligenciaWorker(pool *pgxpool.Pool, sei *sei.Client) *IncluirOficioDiligenciaWorker {
+	return &IncluirOficioDiligenciaWorker{
+		pool:  pool,
+		store: database.New(pool),
+		sei:   sei,
+	}
+}
+
+func (w *IncluirOficioDiligenciaWorker) Work(ctx context.Context, job *river.Job[IncluirOficioDiligenciaArgs]) error {
+	args := job.Args
+
+	sd, err := w.store.GetSolicitacaoDiligencia(ctx, args.SolicitacaoDiligenciaID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return river.JobCancel(err)
+		}
+		return fmt.Errorf("failed to get solicitacao: %w", err)
+	}
+	if sd.SEIDocumentoID.Valid {
+		// O ofício já foi incluído em uma tentativa anterior, que falhou antes
+		// de registrar o evento e agendar a tramitação.
+		return w.concluir(ctx, sd.ProcessoAposentadoriaID, sd.SEIDocumentoNumero.V, args.Tramitacao)
+	}
+	if sd.SEIInclusaoIniciadaEm.Valid {
+		// Uma tentativa anterior enviou o ofício ao SEI sem salvar o documento
+		// criado. Para não incluir o ofício em duplicidade, o processo é
+		// marcado para conferência manual.
+		obs := "Não foi possível confirmar a inclusão do ofício de diligência no SEI. Verifique o processo antes de incluí-lo manualmente."
+		return w.falhar(ctx, sd.ProcessoAposentadoriaID, obs, args.Tramitacao)
+	}
+
+	numero, err := w.store.GetNumeroProcessoAposentadoria(ctx, sd.ProcessoAposentadoriaID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return river.JobCancel(err)
+		}
+		return fmt.Errorf("failed to get numero: %w", err)
+	}
+
+	if err := w.store.IniciarInclusaoSEISolicitacaoDiligencia(ctx, sd.ID); err != nil {
+		return fmt.Errorf("failed to start inclusao: %w", err)
+	}
+
+	res, err := w.sei.IncluirDocumento(ctx, args.Unidade, sei.Documento{
+		Tipo:                  sei.DocumentoGerado,
+		ProtocoloProcedimento: numero,
+		IdSerie:               args.IdSerie,
+		Descricao:             "Diligência",
+		Conteudo:              base64.StdEncoding.EncodeToString([]byte(sd.Oficio)),
+	})
+	if err != nil {
+		// O SEI recusou a inclusão, que pode ser tentada novamente.
+		if cerr := w.store.CancelarInclusaoSEISolicitacaoDiligencia(ctx, sd.ID); cerr != nil {
+			return errors.Join(err, cerr)
+		}
+		if job.Attempt >= job.MaxAttempts {
+			obs := fmt.Sprintf("Falha ao incluir o ofício de diligência no SEI: %v", err)
+			if rerr := w.falhar(ctx, sd.ProcessoAposentadoriaID, obs, args.Tramitacao); rerr != nil {
+				return errors.Join(err, rerr)
+			}
+		}
+		return fmt.Errorf("failed to include documento: %w", err)
+	}
+
+	doc := res.Parametros
+	if err := w.store.SetDocumentoSEISolicitacaoDiligencia(ctx, sd.ID, doc.IdDocumento, doc.DocumentoFormatado); err != nil {
+		return fmt.Errorf("failed to save documento: %w", err)
+	}
+
+	return w.concluir(ctx, sd.ProcessoAposentadoriaID, doc.DocumentoFormatado, args.Tramitacao)
+}
+
+// concluir registra a inclusão do ofício nos eventos do processo e agenda a
+// tramitação do processo.
+func (w *IncluirOficioDiligenciaWorker) concluir(ctx context.Context, paID int64, documento string, tramitacao *EnviarProcessoSEIArgs) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	obs := fmt.Sprintf("Ofício de diligência incluído no SEI (documento %s)", documento)
+	if err := registrarEvento(ctx, w.store.WithTx(tx), paID, database.EventoOficioDiligencia, obs, false); err != nil {
+		return err
+	}
+	if err := w.tramitar(ctx, tx, tramitacao); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// falhar registra a falha na inclusão do ofício nos alertas do processo e
+// agenda a tramitação mesmo assim.
+func (w *IncluirOficioDiligenciaWorker) falhar(ctx context.Context, paID int64, obs string, tramitacao *EnviarProcessoSEIArgs) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := registrarEvento(ctx, w.store.WithTx(tx), paID, database.EventoOficioDiligencia, obs, true); err != nil {
+		return err
+	}
+	if err := w.tramitar(ctx, tx, tramitacao); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (w *IncluirOficioDiligenciaWorker) tramitar(ctx context.Context, tx pgx.Tx, tramitacao *EnviarProcessoSEIArgs) error {
+	if tramitacao == nil {
+		return nil
+	}
+
+	client := river.ClientFromContext[pgx.Tx](ctx)
+	_, err := client.InsertTx(ctx, tx, *tramitacao, nil)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+func (w *IncluirOficioDiligenciaWorker) Timeout(job *river.Job[IncluirOficioDiligenciaArgs]) time.Duration {
+	return time.Minute
+}
@@ -0,0 +1,187 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/datalake"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// IngestaoConfig configura a ingestão automática de processos abertos na
+// unidade de recebimento.
+type IngestaoConfig struct {
+	// Habilita o agendamento periódico da ingestão.
+	Enabled bool `env:"INGESTAO_ENABLED" envDefault:"false"`
+	// Intervalo entre as execuções da ingestão.
+	Intervalo time.Duration `env:"INGESTAO_INTERVALO" envDefault:"1h"`
+	// Quando habilitado, registra o relatório sem cadastrar os processos.
+	DryRun bool `env:"INGESTAO_DRY_RUN" envDefault:"false"`
+	// Quantidade máxima de processos cadastrados por execução. Zero
+	// desabilita o limite.
+	Limite int `env:"INGESTAO_LIMITE" envDefault:"50"`
+}
+
+// IngerirProcessosArgs são os argumentos para o job de ingestão de processos
+// abertos a partir do DataLake.
+type IngerirProcessosArgs struct{}
+
+func (args IngerirProcessosArgs) Kind() string {
+	return "processo:ingerir"
+}
+
+func (args IngerirProcessosArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue: river.QueueDefault,
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: 15 * time.Minute,
+		},
+	}
+}
+
+// ProcessosAbertosLister lista os processos abertos em uma unidade do SEI.
+type ProcessosAbertosLister interface {
+	ListProcessosAbertos(ctx context.Context, unidade string) ([]datalake.Processo, int, error)
+}
+
+// CreateProcessoFunc cadastra um processo pelo número SEI, enfileirando o
+// download e a análise dos seus documentos.
+type CreateProcessoFunc func(ctx context.Context, numero string) error
+
+// IngerirProcessosWorker compara os processos abertos na unidade de
+// recebimento com os processos cadastrados e cadastra os que estão faltando.
+// Cada execução é registrada em um relatório de ingestão.
+type IngerirProcessosWorker struct {
+	store          *database.Store
+	lister         ProcessosAbertosLister
+	createProcesso CreateProcessoFunc
+	cfg            *IngestaoConfig
+	logger         *slog.Logger
+	river.WorkerDefaults[IngerirProcessosArgs]
+}
+
+// NewIngerirProcessosWorker cria uma nova instância de [IngerirProcessosWorker].
+func NewIngerirProcessosWorker(pool *pgxpool.Pool, logger *slog.Logger, lister ProcessosAbertosLister, createProcesso CreateProcessoFunc, cfg *IngestaoConfig) *IngerirProcessosWorker {
+	return &IngerirProcessosWorker{
+		store:          database.New(pool),
+		lister:         lister,
+		createProcesso: createProcesso,
+		cfg:            cfg,
+		logger:         logger.With(slog.String("worker", "ingerir_processos")),
+	}
+}
+
+func (w *IngerirProcessosWorker) Work(ctx context.Context, job *river.Job[IngerirProcessosArgs]) error {
+	abertos, _, err := w.lister.ListProcessosAbertos(ctx, UnidadeRecebimento)
+	if err != nil {
+		return fmt.Errorf("failed to list processos abertos: %w", err)
+	}
+
+	// A view pode retornar mais de um andamento por processo.
+	numeros := make([]string, 0, len(abertos))
+	vistos := make(map[string]bool, len(abertos))
+	for _, p := range abertos {
+		if p.NumeroProcesso == "" || vistos[p.NumeroProcesso] {
+			continue
+		}
+		vistos[p.NumeroProcesso] = true
+		numeros = append(numeros, p.NumeroProcesso)
+	}
+
+	existentes, err := w.store.ListNumerosProcessosExistentes(ctx, numeros)
+	if err != nil {
+		return fmt.Errorf("failed to list existing processos: %w", err)
+	}
+
+	ing := &database.IngestaoProcessos{
+		Unidade:     UnidadeRecebimento,
+		DryRun:      w.cfg.DryRun,
+		Encontrados: len(numeros),
+		Existentes:  len(existentes),
+	}
+	if err := w.store.SaveIngestaoProcessos(ctx, ing); err != nil {
+		return fmt.Errorf("failed to save ingestao: %w", err)
+	}
+
+	if err := w.ingerir(ctx, ing, numeros, existentes); err != nil {
+		// A execução é encerrada com o erro para não ficar em aberto. A nova
+		// tentativa do job registra uma nova execução.
+		ing.ConcluidaEm = sql.Null[time.Time]{V: time.Now(), Valid: true}
+		ing.Erro = err.Error()
+		if uerr := w.store.UpdateIngestaoProcessos(context.WithoutCancel(ctx), ing); uerr != nil {
+			return errors.Join(err, fmt.Errorf("failed to update ingestao: %w", uerr))
+		}
+		return err
+	}
+
+	ing.ConcluidaEm = sql.Null[time.Time]{V: time.Now(), Valid: true}
+	if err := w.store.UpdateIngestaoProcessos(ctx, ing); err != nil {
+		return fmt.Errorf("failed to update ingestao: %w", err)
+	}
+
+	w.logger.Info("ingestão de processos concluída",
+		slog.Int64("ingestao_id", ing.ID),
+		slog.Bool("dry_run", ing.DryRun),
+		slog.Int("encontrados", ing.Encontrados),
+		slog.Int("criados", ing.Criados),
+		slog.Int("ignorados", ing.Ignorados),
+		slog.Int("falhas", ing.Falhas),
+	)
+
+	return nil
+}
+
+// ingerir cadastra os processos que ainda não existem e registra um item para
+// cada processo encontrado, inclusive os já cadastrados, atualizando os
+// totalizadores da execução.
+func (w *IngerirProcessosWorker) ingerir(ctx context.Context, ing *database.IngestaoProcessos, numeros []string, existentes map[string]bool) error {
+	for _, numero := range numeros {
+		item := &database.ItemIngestaoProcessos{
+			IngestaoID: ing.ID,
+			Numero:     numero,
+		}
+
+		switch {
+		case existentes[numero]:
+			item.Resultado = database.ResultadoIngestaoExistente
+			item.Detalhe = "Processo já cadastrado"
+		case w.cfg.DryRun:
+			item.Resultado = database.ResultadoIngestaoIgnorado
+			item.Detalhe = "Execução em modo de simulação"
+		case w.cfg.Limite > 0 && ing.Criados+ing.Falhas >= w.cfg.Limite:
+			item.Resultado = database.ResultadoIngestaoIgnorado
+			item.Detalhe = "Limite de processos por execução atingido"
+		default:
+			if err := w.createProcesso(ctx, numero); err != nil {
+				item.Resultado = database.ResultadoIngestaoFalha
+				item.Detalhe = err.Error()
+			} else {
+				item.Resultado = database.ResultadoIngestaoCriado
+			}
+		}
+
+		switch item.Resultado {
+		case database.ResultadoIngestaoCriado:
+			ing.Criados++
+		case database.ResultadoIngestaoIgnorado:
+			ing.Ignorados++
+		case database.ResultadoIngestaoFalha:
+			ing.Falhas++
+		}
+
+		if err := w.store.SaveItemIngestaoProcessos(ctx, item); err != nil {
+			return fmt.Errorf("failed to save item: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w *IngerirProcessosWorker) Timeout(job *river.Job[IngerirProcessosArgs]) time.Duration {
+	return 30 * time.Minute
+}
@@ -0,0 +1,166 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/datalake"
+	"github.com/riverqueue/river"
+)
+
+type fakeProcessosAbertosLister struct {
+	numeros []string
+}
+
+func (l *fakeProcessosAbertosLister) ListProcessosAbertos(ctx context.Context, unidade string) ([]datalake.Processo, int, error) {
+	pp := make([]datalake.Processo, 0, len(l.numeros))
+	for _, numero := range l.numeros {
+		pp = append(pp, datalake.Processo{NumeroProcesso: numero})
+	}
+	return pp, len(pp), nil
+}
+
+func newIngerirProcessosWorker(t *testing.T, cfg *IngestaoConfig, createProcesso CreateProcessoFunc) (*IngerirProcessosWorker, *database.Store) {
+	t.Helper()
+
+	pool := ti.NewDatabase(t)
+	store := database.New(pool)
+
+	existente := &database.Processo{Numero: "existente", SeiUnidadeID: "1"}
+	if err := store.SaveProcesso(t.Context(), existente); err != nil {
+		t.Fatal(err)
+	}
+
+	lister := &fakeProcessosAbertosLister{numeros: []string{"existente", "novo-1", "novo-1", "novo-2"}}
+	w := NewIngerirProcessosWorker(pool, slog.New(slog.DiscardHandler), lister, createProcesso, cfg)
+	return w, store
+}
+
+func listItensIngestao(t *testing.T, store *database.Store, ingestaoID int64) map[string]database.ResultadoIngestao {
+	t.Helper()
+
+	itens, err := store.ListItensIngestaoProcessos(t.Context(), ingestaoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resultados := make(map[string]database.ResultadoIngestao, len(itens))
+	for _, item := range itens {
+		resultados[item.Numero] = item.Resultado
+	}
+	return resultados
+}
+
+func latestIngestao(t *testing.T, store *database.Store) *database.IngestaoProcessos {
+	t.Helper()
+
+	ii, _, err := store.ListIngestoesProcessos(t.Context(), database.ListIngestoesProcessosParams{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ii) == 0 {
+		t.Fatal("expected ingestão to be registered")
+	}
+	return ii[0]
+}
+
+func TestIngerirProcessos_DryRun(t *testing.T) {
+	t.Parallel()
+
+	createProcesso := func(ctx context.Context, numero string) error {
+		t.Errorf("unexpected processo %s created in dry run", numero)
+		return nil
+	}
+	w, store := newIngerirProcessosWorker(t, &IngestaoConfig{DryRun: true}, createProcesso)
+
+	if err := w.Work(t.Context(), &river.Job[IngerirProcessosArgs]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ing := latestIngestao(t, store)
+	if !ing.DryRun || ing.Encontrados != 3 || ing.Existentes != 1 || ing.Criados != 0 || ing.Ignorados != 2 || !ing.ConcluidaEm.Valid {
+		t.Fatalf("unexpected ingestão: %+v", ing)
+	}
+
+	want := map[string]database.ResultadoIngestao{
+		"existente": database.ResultadoIngestaoExistente,
+		"novo-1":    database.ResultadoIngestaoIgnorado,
+		"novo-2":    database.ResultadoIngestaoIgnorado,
+	}
+	got := listItensIngestao(t, store, ing.ID)
+	for numero, resultado := range want {
+		if got[numero] != resultado {
+			t.Fatalf("expected %s to be %s, got %+v", numero, resultado, got)
+		}
+	}
+}
+
+func TestIngerirProcessos_Limite(t *testing.T) {
+	t.Parallel()
+
+	var criados []string
+	createProcesso := func(ctx context.Context, numero string) error {
+		criados = append(criados, numero)
+		return nil
+	}
+	w, store := newIngerirProcessosWorker(t, &IngestaoConfig{Limite: 1}, createProcesso)
+
+	if err := w.Work(t.Context(), &river.Job[IngerirProcessosArgs]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(criados) != 1 || criados[0] != "novo-1" {
+		t.Fatalf("expected only novo-1 to be created, got %v", criados)
+	}
+
+	ing := latestIngestao(t, store)
+	if ing.Criados != 1 || ing.Ignorados != 1 || ing.Falhas != 0 {
+		t.Fatalf("unexpected ingestão: %+v", ing)
+	}
+
+	got := listItensIngestao(t, store, ing.ID)
+	if got["novo-1"] != database.ResultadoIngestaoCriado || got["novo-2"] != database.ResultadoIngestaoIgnorado {
+		t.Fatalf("unexpected itens: %+v", got)
+	}
+}
+
+func TestIngerirProcessos_Retry(t *testing.T) {
+	t.Parallel()
+
+	// Na primeira execução o contexto é cancelado durante o cadastro, o que
+	// interrompe o registro dos itens.
+	ctx, cancel := context.WithCancel(t.Context())
+	tentativa := 1
+	createProcesso := func(ctx context.Context, numero string) error {
+		if tentativa == 1 {
+			cancel()
+		}
+		return nil
+	}
+	w, store := newIngerirProcessosWorker(t, &IngestaoConfig{}, createProcesso)
+
+	err := w.Work(ctx, &river.Job[IngerirProcessosArgs]{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+
+	falha := latestIngestao(t, store)
+	if !falha.ConcluidaEm.Valid || falha.Erro == "" {
+		t.Fatalf("expected failed ingestão to be closed with the error, got %+v", falha)
+	}
+
+	tentativa++
+	if err := w.Work(t.Context(), &river.Job[IngerirProcessosArgs]{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ing := latestIngestao(t, store)
+	if ing.ID == falha.ID {
+		t.Fatal("expected retry to register a new ingestão")
+	}
+	if !ing.ConcluidaEm.Valid || ing.Erro != "" || ing.Criados != 2 {
+		t.Fatalf("unexpected ingestão: %+v", ing)
+	}
+}
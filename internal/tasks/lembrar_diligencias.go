@@ -0,0 +1,190 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/mail"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// LembrarDiligenciasArgs são os argumentos para o job que envia às unidades de
+// origem os lembretes do prazo de resposta das diligências.
+type LembrarDiligenciasArgs struct{}
+
+func (args LembrarDiligenciasArgs) Kind() string {
+	return "fila:lembrar-diligencias"
+}
+
+func (args LembrarDiligenciasArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue: river.QueueDefault,
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: 12 * time.Hour,
+		},
+	}
+}
+
+// LembrarDiligenciasWorker verifica o prazo de resposta das diligências
+// aguardando resposta. Nos dias configurados, relativos ao prazo, os contatos
+// da unidade de origem recebem um lembrete com as pendências. Quando o prazo
+// vence, o vencimento é registrado nos alertas do processo.
+type LembrarDiligenciasWorker struct {
+	pool      *pgxpool.Pool
+	store     *database.Store
+	lembretes []int
+	logger    *slog.Logger
+	river.WorkerDefaults[LembrarDiligenciasArgs]
+}
+
+// NewLembrarDiligenciasWorker cria uma nova instância de
+// [LembrarDiligenciasWorker]. Os lembretes são os dias, relativos ao prazo de
+// resposta, em que a unidade é lembrada: negativos antes do prazo e positivos
+// depois.
+func NewLembrarDiligenciasWorker(pool *pgxpool.Pool, logger *slog.Logger, lembretes []int) *LembrarDiligenciasWorker {
+	return &LembrarDiligenciasWorker{
+		pool:      pool,
+		store:     database.New(pool),
+		lembretes: lembretes,
+		logger:    logger.With(slog.String("worker", "lembrar_diligencias")),
+	}
+}
+
+func (w *LembrarDiligenciasWorker) Work(ctx context.Context, job *river.Job[LembrarDiligenciasArgs]) error {
+	dd, err := w.store.ListDiligenciasAguardandoResposta(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list diligências: %w", err)
+	}
+
+	hoje := database.DataBrasilia(time.Now())
+	var errs []error
+	for _, d := range dd {
+		atraso := int(hoje.Sub(d.PrazoResposta).Hours() / 24)
+		devidos := lembretesDevidos(w.lembretes, d.LembretesEnviados, atraso)
+		vencer := atraso > 0 && !d.VencimentoNotificadoEm.Valid
+		if len(devidos) == 0 && !vencer {
+			continue
+		}
+
+		if err := w.processar(ctx, d, devidos, vencer, atraso > 0); err != nil {
+			errs = append(errs, fmt.Errorf("solicitacao %d: %w", d.SolicitacaoID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// processar registra os lembretes devidos e o vencimento de uma diligência e
+// agenda o email para a unidade de origem na mesma transação, para que cada
+// lembrete seja enviado uma única vez.
+func (w *LembrarDiligenciasWorker) processar(ctx context.Context, d *database.DiligenciaAguardandoResposta, devidos []int, vencer, vencida bool) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	store := w.store.WithTx(tx)
+	prazo := d.PrazoResposta.Format("02/01/2006")
+
+	if vencer {
+		obs := fmt.Sprintf("Prazo de resposta da diligência vencido em %s", prazo)
+		if err := registrarEvento(ctx, store, d.ProcessoAposentadoriaID, database.EventoPrazoDiligencia, obs, true); err != nil {
+			return err
+		}
+		if err := store.SetVencimentoNotificadoSolicitacaoDiligencia(ctx, d.SolicitacaoID); err != nil {
+			return err
+		}
+	}
+
+	if len(devidos) > 0 {
+		for _, dias := range devidos {
+			if _, err := store.SaveLembreteDiligencia(ctx, d.SolicitacaoID, dias); err != nil {
+				return err
+			}
+		}
+		if err := w.agendarEmail(ctx, tx, d, prazo, vencida); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (w *LembrarDiligenciasWorker) agendarEmail(ctx context.Context, tx pgx.Tx, d *database.DiligenciaAguardandoResposta, prazo string, vencida bool) error {
+	store := w.store.WithTx(tx)
+
+	var contatos []*database.ContatoUnidade
+	if d.UnidadeSigla != "" {
+		var err error
+		contatos, err = store.ListContatosUnidade(ctx, d.UnidadeSigla)
+		if err != nil {
+			return err
+		}
+	}
+	if len(contatos) == 0 {
+		w.logger.Info("unidade sem contatos para lembrete de diligência",
+			slog.Int64("processo_aposentadoria_id", d.ProcessoAposentadoriaID),
+			slog.String("unidade", d.UnidadeSigla),
+		)
+		return nil
+	}
+
+	itens, err := store.ListItensDiligencia(ctx, d.SolicitacaoID)
+	if err != nil {
+		return err
+	}
+
+	to := make([]string, len(contatos))
+	for i, c := range contatos {
+		to[i] = c.Email
+	}
+
+	params := mail.LembreteDiligenciaEmailParams{
+		NumeroProcesso: d.NumeroProcesso,
+		Unidade:        d.UnidadeSigla,
+		PrazoResposta:  prazo,
+		Vencida:        vencida,
+		Itens:          make([]mail.ItemDiligenciaEmail, len(itens)),
+	}
+	for i, it := range itens {
+		params.Itens[i] = mail.ItemDiligenciaEmail{
+			Tipo:          it.Tipo,
+			Subcategorias: it.Subcategorias,
+			Detalhe:       it.Detalhe,
+		}
+	}
+
+	email, err := mail.NewLembreteDiligenciaEmail(to, params)
+	if err != nil {
+		return err
+	}
+
+	client := river.ClientFromContext[pgx.Tx](ctx)
+	if _, err := client.InsertTx(ctx, tx, SendEmailArgs{Email: email}, nil); err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// lembretesDevidos retorna os lembretes configurados que já deveriam ter sido
+// enviados para uma diligência com o atraso informado, em dias relativos ao
+// prazo, e que ainda não foram registrados. Lembretes acumulados, como os de
+// diligências anteriores à configuração, resultam em um único email.
+func lembretesDevidos(lembretes, enviados []int, atraso int) []int {
+	var devidos []int
+	for _, dias := range lembretes {
+		if dias <= atraso && !slices.Contains(enviados, dias) && !slices.Contains(devidos, dias) {
+			devidos = append(devidos, dias)
+		}
+	}
+	slices.Sort(devidos)
+	return devidos
+}
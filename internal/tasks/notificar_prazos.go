@@ -0,0 +1,146 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/mail"
+	"github.com/automatiza-mg/fila/internal/prazos"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// NotificarPrazosArgs são os argumentos para o job que envia aos gestores o
+// resumo diário dos prazos dos processos de aposentadoria.
+type NotificarPrazosArgs struct{}
+
+func (args NotificarPrazosArgs) Kind() string {
+	return "fila:notificar-prazos"
+}
+
+func (args NotificarPrazosArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue: river.QueueDefault,
+		// O resumo é enviado aos gestores no máximo uma vez por dia, mesmo
+		// que o job seja agendado novamente na inicialização.
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: 24 * time.Hour,
+		},
+	}
+}
+
+// NotificarPrazosWorker calcula o prazo dos processos em aberto e envia aos
+// gestores um resumo dos processos vencidos e próximos do vencimento.
+type NotificarPrazosWorker struct {
+	store   *database.Store
+	sender  mail.Sender
+	cfg     *prazos.Config
+	filaURL string
+	logger  *slog.Logger
+	river.WorkerDefaults[NotificarPrazosArgs]
+}
+
+// NewNotificarPrazosWorker cria uma nova instância de [NotificarPrazosWorker].
+// O filaURL é incluído no email como link de acesso à fila.
+func NewNotificarPrazosWorker(pool *pgxpool.Pool, logger *slog.Logger, sender mail.Sender, cfg *prazos.Config, filaURL string) *NotificarPrazosWorker {
+	return &NotificarPrazosWorker{
+		store:   database.New(pool),
+		sender:  sender,
+		cfg:     cfg,
+		filaURL: filaURL,
+		logger:  logger.With(slog.String("worker", "notificar_prazos")),
+	}
+}
+
+func (w *NotificarPrazosWorker) Work(ctx context.Context, job *river.Job[NotificarPrazosArgs]) error {
+	gestores, err := w.store.ListEmailsByPapel(ctx, database.PapelGestor)
+	if err != nil {
+		return fmt.Errorf("failed to list gestores: %w", err)
+	}
+	if len(gestores) == 0 {
+		return nil
+	}
+
+	paa, err := w.store.ListAllProcessoAposentadoria(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list processos: %w", err)
+	}
+
+	pendentes := make([]*database.ProcessoAposentadoria, 0, len(paa))
+	paIDs := make([]int64, 0, len(paa))
+	for _, pa := range paa {
+		if pa.Status != database.StatusProcessoConcluido {
+			pendentes = append(pendentes, pa)
+			paIDs = append(paIDs, pa.ID)
+		}
+	}
+
+	historico, err := w.store.ListHistoricoDiligenciaMap(ctx, paIDs)
+	if err != nil {
+		return fmt.Errorf("failed to list historico: %w", err)
+	}
+
+	now := time.Now()
+	type alerta struct {
+		pa    *database.ProcessoAposentadoria
+		prazo *prazos.Prazo
+	}
+	var alertas []alerta
+	var ids []uuid.UUID
+	for _, pa := range pendentes {
+		prazo := prazos.Calcular(w.cfg, pa, prazos.Pausas(historico[pa.ID]), now)
+		if prazo.Situacao != prazos.SituacaoVencido && prazo.Situacao != prazos.SituacaoProximo {
+			continue
+		}
+		alertas = append(alertas, alerta{pa: pa, prazo: prazo})
+		ids = append(ids, pa.ProcessoID)
+	}
+	if len(alertas) == 0 {
+		return nil
+	}
+
+	processos, err := w.store.GetProcessosMap(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get processos: %w", err)
+	}
+
+	params := mail.PrazosEmailParams{FilaURL: w.filaURL}
+	for _, a := range alertas {
+		var numero string
+		if p, ok := processos[a.pa.ProcessoID]; ok {
+			numero = p.Numero
+		}
+
+		item := mail.PrazoProcessoEmail{
+			Numero:        numero,
+			Tipo:          string(a.prazo.Tipo),
+			DataLimite:    a.prazo.DataLimite.Format("02/01/2006"),
+			DiasRestantes: a.prazo.DiasRestantes,
+		}
+		if a.prazo.Situacao == prazos.SituacaoVencido {
+			params.Vencidos = append(params.Vencidos, item)
+		} else {
+			params.Proximos = append(params.Proximos, item)
+		}
+	}
+
+	email, err := mail.NewPrazosEmail(gestores, params)
+	if err != nil {
+		return err
+	}
+	if err := w.sender.Send(ctx, email); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	w.logger.Info("resumo de prazos enviado",
+		slog.Int("gestores", len(gestores)),
+		slog.Int("vencidos", len(params.Vencidos)),
+		slog.Int("proximos", len(params.Proximos)),
+	)
+
+	return nil
+}
@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
 	"time"
@@ -13,7 +14,12 @@ import (
 )
 
 // RecalcularScoresArgs são os argumentos para o job de recálculo de scores.
-type RecalcularScoresArgs struct{}
+type RecalcularScoresArgs struct {
+	// VersaoRegrasID identifica a versão de regras cuja ativação motivou o
+	// recálculo. O recálculo sempre usa a versão ativa; o campo apenas
+	// diferencia o job para fins de unicidade.
+	VersaoRegrasID int64 `json:"versao_regras_id,omitempty"`
+}
 
 func (args RecalcularScoresArgs) Kind() string {
 	return "fila:recalcular-scores"
@@ -23,13 +29,14 @@ func (args RecalcularScoresArgs) InsertOpts() river.InsertOpts {
 	return river.InsertOpts{
 		Queue: river.QueueDefault,
 		UniqueOpts: river.UniqueOpts{
+			ByArgs:   true,
 			ByPeriod: 5 * time.Minute,
 		},
 	}
 }
 
-// RecalcularScoresWorker processa o recálculo de scores de todos os processos
-// de aposentadoria.
+// RecalcularScoresWorker processa o recálculo de scores dos processos de
+// aposentadoria ainda não concluídos.
 type RecalcularScoresWorker struct {
 	pool  *pgxpool.Pool
 	store *database.Store
@@ -45,25 +52,42 @@ func (w *RecalcularScoresWorker) Work(ctx context.Context, job *river.Job[Recalc
 
 	store := w.store.WithTx(tx)
 
-	paa, err := store.ListAllProcessoAposentadoria(ctx)
+	// Processos concluídos mantêm o score com que foram analisados.
+	paa, err := store.ListProcessoAposentadoriaAbertos(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list processos: %w", err)
 	}
 
+	versoes, err := store.ListVersoesScoreProcessos(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list versões: %w", err)
+	}
+
+	versao, err := aposentadoria.LoadVersaoAtiva(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
 	updated := 0
 	for _, pa := range paa {
-		novo := aposentadoria.CalculateScore(
-			pa.DataNascimentoRequerente,
-			pa.Invalidez,
-			pa.Judicial,
-			pa.Prioridade,
-		)
-
-		if novo == pa.Score {
+		res := versao.Calcular(pa, now)
+
+		// O detalhamento só é registrado quando o score ou a versão das
+		// regras mudam, e o histórico apenas quando o score muda.
+		if versaoID, ok := versoes[pa.ID]; ok && versaoID == versao.ID && res.Score == pa.Score {
+			continue
+		}
+		anterior := sql.Null[int]{V: pa.Score, Valid: true}
+		if err := versao.Registrar(ctx, store, pa.ID, anterior, res); err != nil {
+			return fmt.Errorf("failed to save score for processo %d: %w", pa.ID, err)
+		}
+
+		if res.Score == pa.Score {
 			continue
 		}
 
-		pa.Score = novo
+		pa.Score = res.Score
 		if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
 			return fmt.Errorf("failed to update processo %d: %w", pa.ID, err)
 		}
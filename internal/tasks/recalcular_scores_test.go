@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/riverqueue/river"
+)
+
+func seedProcessoScore(t *testing.T, store *database.Store, status database.StatusProcesso) *database.ProcessoAposentadoria {
+	t.Helper()
+
+	p := &database.Processo{Numero: rand.Text(), SeiUnidadeID: rand.Text()}
+	if err := store.SaveProcesso(t.Context(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	pa := &database.ProcessoAposentadoria{
+		ProcessoID:               p.ID,
+		Status:                   status,
+		CPFRequerente:            "12345678901",
+		DataNascimentoRequerente: time.Date(1940, 5, 10, 0, 0, 0, 0, time.UTC),
+		DataRequerimento:         time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := store.SaveProcessoAposentadoria(t.Context(), pa); err != nil {
+		t.Fatal(err)
+	}
+	return pa
+}
+
+func TestRecalcularScores(t *testing.T) {
+	t.Parallel()
+
+	pool := ti.NewDatabase(t)
+	w := NewRecalcularScoresWorker(pool)
+	store := w.store
+
+	aberto := seedProcessoScore(t, store, database.StatusProcessoAnalisePendente)
+	concluido := seedProcessoScore(t, store, database.StatusProcessoConcluido)
+
+	job := &river.Job[RecalcularScoresArgs]{}
+	if err := w.Work(t.Context(), job); err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := store.GetScoreProcessoAposentadoria(t.Context(), aberto.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Score == 0 {
+		t.Fatal("expected score to be recalculated")
+	}
+
+	if _, err := store.GetScoreProcessoAposentadoria(t.Context(), concluido.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Fatalf("expected concluded processo to keep its score, got %v", err)
+	}
+
+	// Sem mudança de score ou de versão, o recálculo não registra nada.
+	if err := w.Work(t.Context(), job); err != nil {
+		t.Fatal(err)
+	}
+
+	sc2, err := store.GetScoreProcessoAposentadoria(t.Context(), aberto.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc2.CalculadoEm.Equal(sc.CalculadoEm) {
+		t.Fatalf("expected score details to be kept, got %v and %v", sc.CalculadoEm, sc2.CalculadoEm)
+	}
+
+	hh, err := store.ListHistoricoScoreProcesso(t.Context(), aberto.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hh) != 1 {
+		t.Fatalf("expected 1 score change, got %d", len(hh))
+	}
+}
@@ -26,6 +26,13 @@ func WithTestOnly() RiverOptFunc {
 	}
 }
 
+// WithPeriodicJobs registra jobs periódicos a serem agendados pelo client.
+func WithPeriodicJobs(jobs ...*river.PeriodicJob) RiverOptFunc {
+	return func(cfg *river.Config) {
+		cfg.PeriodicJobs = append(cfg.PeriodicJobs, jobs...)
+	}
+}
+
 func NewQueue(ctx context.Context, pool *pgxpool.Pool, opts ...RiverOptFunc) (*river.Client[pgx.Tx], error) {
 	driver := riverpgxv5.New(pool)
 
@@ -45,7 +52,7 @@ func NewQueue(ctx context.Context, pool *pgxpool.Pool, opts ...RiverOptFunc) (*r
 	return river.NewClient(driver, &cfg)
 }
 
-func NewWorker(ctx context.Context, pool *pgxpool.Pool, workers *river.Workers) (*river.Client[pgx.Tx], error) {
+func NewWorker(ctx context.Context, pool *pgxpool.Pool, workers *river.Workers, opts ...RiverOptFunc) (*river.Client[pgx.Tx], error) {
 	driver := riverpgxv5.New(pool)
 
 	migrator, err := rivermigrate.New(driver, nil)
@@ -56,11 +63,16 @@ func NewWorker(ctx context.Context, pool *pgxpool.Pool, workers *river.Workers)
 		return nil, err
 	}
 
-	return river.NewClient(driver, &river.Config{
+	cfg := river.Config{
 		Workers: workers,
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: 100},
 			QueueProcessos:     {MaxWorkers: 2},
 		},
-	})
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return river.NewClient(driver, &cfg)
 }
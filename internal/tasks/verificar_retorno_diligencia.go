@@ -0,0 +1,249 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/automatiza-mg/fila/internal/database"
+	"github.com/automatiza-mg/fila/internal/sei"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riverqueue/river"
+)
+
+// VerificarRetornoDiligenciaArgs são os argumentos para o job que verifica
+// se processos em diligência receberam novos documentos no SEI.
+type VerificarRetornoDiligenciaArgs struct{}
+
+func (args VerificarRetornoDiligenciaArgs) Kind() string {
+	return "fila:verificar-retorno-diligencia"
+}
+
+func (args VerificarRetornoDiligenciaArgs) InsertOpts() river.InsertOpts {
+	return river.InsertOpts{
+		Queue: river.QueueDefault,
+		UniqueOpts: river.UniqueOpts{
+			ByPeriod: 15 * time.Minute,
+		},
+	}
+}
+
+// VerificarRetornoDiligenciaWorker compara os documentos de cada processo
+// EM_DILIGENCIA no SEI com os registrados no envio da diligência. Quando há
+// documentos novos, o processo é movido para RETORNO_DILIGENCIA, preservando o
+// último analista, e os documentos são baixados novamente.
+type VerificarRetornoDiligenciaWorker struct {
+	pool   *pgxpool.Pool
+	store  *database.Store
+	sei    *sei.Client
+	logger *slog.Logger
+	river.WorkerDefaults[VerificarRetornoDiligenciaArgs]
+}
+
+// NewVerificarRetornoDiligenciaWorker cria uma nova instância de
+// [VerificarRetornoDiligenciaWorker].
+func NewVerificarRetornoDiligenciaWorker(pool *pgxpool.Pool, logger *slog.Logger, sei *sei.Client) *VerificarRetornoDiligenciaWorker {
+	return &VerificarRetornoDiligenciaWorker{
+		pool:   pool,
+		store:  database.New(pool),
+		sei:    sei,
+		logger: logger.With(slog.String("worker", "verificar_retorno_diligencia")),
+	}
+}
+
+func (w *VerificarRetornoDiligenciaWorker) Work(ctx context.Context, job *river.Job[VerificarRetornoDiligenciaArgs]) error {
+	paa, err := w.store.ListProcessoAposentadoriaByStatus(ctx, database.StatusProcessoEmDiligencia)
+	if err != nil {
+		return fmt.Errorf("failed to list processos: %w", err)
+	}
+
+	retornados := 0
+	for _, pa := range paa {
+		novos, err := w.countDocumentosNovos(ctx, pa)
+		if err != nil {
+			// Uma falha no SEI para um processo não deve impedir a verificação
+			// dos demais. O processo será verificado novamente na próxima execução.
+			w.logger.Warn("falha ao verificar retorno de diligência",
+				slog.Int64("processo_aposentadoria_id", pa.ID),
+				slog.String("erro", err.Error()),
+			)
+			continue
+		}
+		if novos == 0 {
+			continue
+		}
+
+		if err := w.registrarRetorno(ctx, pa.ID, novos); err != nil {
+			return fmt.Errorf("failed to register retorno for processo %d: %w", pa.ID, err)
+		}
+		retornados++
+	}
+
+	w.logger.Info("retornos de diligência verificados",
+		slog.Int("total", len(paa)),
+		slog.Int("retornados", retornados),
+	)
+
+	return nil
+}
+
+// countDocumentosNovos retorna a quantidade de documentos presentes no SEI
+// que não constavam no processo quando ele foi enviado para diligência. Os
+// documentos são registrados pela tramitação do envio; enquanto ela não
+// ocorre, o processo não é verificado. A lista atual não é usada como
+// referência, pois absorveria as respostas incluídas desde o envio.
+func (w *VerificarRetornoDiligenciaWorker) countDocumentosNovos(ctx context.Context, pa *database.ProcessoAposentadoria) (int, error) {
+	enviados, err := w.store.GetDocumentosSEIDiligencia(ctx, pa.ID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get documentos enviados: %w", err)
+	}
+
+	ignorar, err := w.documentosDiligencia(ctx, pa.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	p, err := w.store.GetProcesso(ctx, pa.ProcessoID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get processo: %w", err)
+	}
+
+	docs, err := w.sei.ListarDocumentos(ctx, p.LinkAcesso)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list documentos: %w", err)
+	}
+
+	return len(documentosNovos(docs, append(enviados, ignorar.numeros...), ignorar.unidade)), nil
+}
+
+// documentosIgnorados identifica os documentos produzidos pela própria
+// diligência, que não indicam uma resposta da unidade.
+type documentosIgnorados struct {
+	numeros []string
+	unidade string
+}
+
+// documentosDiligencia retorna o número do ofício da última diligência
+// enviada e a unidade do SEI do analista que a enviou.
+func (w *VerificarRetornoDiligenciaWorker) documentosDiligencia(ctx context.Context, paID int64) (documentosIgnorados, error) {
+	var ignorar documentosIgnorados
+
+	ss, err := w.store.ListSolicitacoesDiligenciaByProcesso(ctx, database.ListSolicitacoesDiligenciaParams{
+		ProcessoAposentadoriaID: paID,
+		Status:                  database.StatusSolicitacaoEnviada,
+	})
+	if err != nil {
+		return ignorar, fmt.Errorf("failed to list solicitações: %w", err)
+	}
+	if len(ss) == 0 {
+		return ignorar, nil
+	}
+
+	sd := ss[0]
+	if sd.SEIDocumentoNumero.Valid {
+		ignorar.numeros = append(ignorar.numeros, sd.SEIDocumentoNumero.V)
+	}
+
+	analista, err := w.store.GetAnalista(ctx, sd.AnalistaID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return ignorar, fmt.Errorf("failed to get analista: %w", err)
+	}
+	if analista != nil {
+		ignorar.unidade = analista.SEIUnidadeSigla
+	}
+	return ignorar, nil
+}
+
+// documentosNovos retorna os documentos do SEI cujos números não constam em
+// enviados. Documentos criados na unidade informada, a do analista
+// responsável pela diligência, não são considerados.
+func documentosNovos(docs []sei.LinhaDocumento, enviados []string, unidade string) []sei.LinhaDocumento {
+	existentes := make(map[string]struct{}, len(enviados))
+	for _, numero := range enviados {
+		existentes[numero] = struct{}{}
+	}
+
+	var novos []sei.LinhaDocumento
+	for _, doc := range docs {
+		if doc.Numero == "" {
+			continue
+		}
+		if unidade != "" && doc.Unidade == unidade {
+			continue
+		}
+		if _, ok := existentes[doc.Numero]; !ok {
+			novos = append(novos, doc)
+		}
+	}
+	return novos
+}
+
+// registrarRetorno move o processo para RETORNO_DILIGENCIA e enfileira o
+// download dos documentos na mesma transação.
+func (w *VerificarRetornoDiligenciaWorker) registrarRetorno(ctx context.Context, paID int64, novos int) error {
+	tx, err := w.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	store := w.store.WithTx(tx)
+
+	pa, err := store.GetProcessoAposentadoria(ctx, paID)
+	if err != nil {
+		return err
+	}
+
+	// O status pode ter sido alterado desde a listagem.
+	if pa.Status != database.StatusProcessoEmDiligencia {
+		return nil
+	}
+
+	// O ultimo_analista_id é preservado para que o processo retorne
+	// preferencialmente ao mesmo analista.
+	pa.Status = database.StatusProcessoRetornoDiligencia
+	pa.AnalistaID = sql.Null[int64]{}
+	if err := store.UpdateProcessoAposentadoria(ctx, pa); err != nil {
+		return err
+	}
+	if err := store.DeleteDocumentosSEIDiligencia(ctx, pa.ID); err != nil {
+		return err
+	}
+
+	hist := &database.HistoricoStatusProcesso{
+		ProcessoAposentadoriaID: pa.ID,
+		StatusAnterior:          sql.Null[database.StatusProcesso]{V: database.StatusProcessoEmDiligencia, Valid: true},
+		StatusNovo:              database.StatusProcessoRetornoDiligencia,
+	}
+	hist.SetObservacao(fmt.Sprintf("Retorno de diligência identificado no SEI (%d novo(s) documento(s))", novos))
+	if err := store.SaveHistoricoStatusProcesso(ctx, hist); err != nil {
+		return err
+	}
+
+	client := river.ClientFromContext[pgx.Tx](ctx)
+	_, err = client.InsertTx(ctx, tx, DownloadProcessoArgs{
+		ProcessoID: pa.ProcessoID,
+		SemAnalise: true,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to insert download task: %w", err)
+	}
+
+	_, err = client.InsertTx(ctx, tx, AtribuirProcessosArgs{}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to insert atribuicao task: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (w *VerificarRetornoDiligenciaWorker) Timeout(job *river.Job[VerificarRetornoDiligenciaArgs]) time.Duration {
+	return 10 * time.Minute
+}
@@ -0,0 +1,51 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/automatiza-mg/fila/internal/sei"
+)
+
+func TestDocumentosNovos(t *testing.T) {
+	docs := []sei.LinhaDocumento{
+		{Numero: "100"},
+		{Numero: "101"},
+		{Numero: ""},
+		{Numero: "102"},
+	}
+
+	// O documento 101 não foi baixado para o processo, mas já constava no
+	// SEI no envio da diligência.
+	novos := documentosNovos(docs, []string{"100", "101"}, "")
+	if len(novos) != 1 || novos[0].Numero != "102" {
+		t.Fatalf("expected only documento 102, got %+v", novos)
+	}
+
+	if novos := documentosNovos(docs, []string{"100", "101", "102"}, ""); len(novos) != 0 {
+		t.Fatalf("expected no documentos novos, got %+v", novos)
+	}
+}
+
+func TestDocumentosNovos_IgnoraDiligencia(t *testing.T) {
+	docs := []sei.LinhaDocumento{
+		{Numero: "100", Unidade: "SEE/RH"},
+		// Ofício da diligência incluído pelo analista.
+		{Numero: "200", Unidade: "SEPLAG/AP00"},
+		// Despacho do analista incluído depois do envio.
+		{Numero: "201", Unidade: "SEPLAG/AP00"},
+		// Resposta da unidade de origem.
+		{Numero: "202", Unidade: "SEE/RH"},
+	}
+
+	// O número do ofício é somado aos documentos registrados no envio.
+	novos := documentosNovos(docs, []string{"100", "200"}, "SEPLAG/AP00")
+	if len(novos) != 1 || novos[0].Numero != "202" {
+		t.Fatalf("expected only documento 202, got %+v", novos)
+	}
+
+	// Sem a resposta, o ofício e os documentos do analista não indicam o
+	// retorno.
+	if novos := documentosNovos(docs[:3], []string{"100", "200"}, "SEPLAG/AP00"); len(novos) != 0 {
+		t.Fatalf("expected no documentos novos, got %+v", novos)
+	}
+}
@@ -0,0 +1,156 @@
+// Package xlsx gera planilhas XLSX simples, com uma única aba, usando apenas a
+// biblioteca padrão.
+//
+// A planilha gerada contém somente os valores das células, sem estilos ou
+// fórmulas, o suficiente para exportar relatórios tabulares.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+	rootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	workbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+	workbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+)
+
+// Write escreve em w uma planilha com uma aba chamada aba e as linhas
+// informadas. São aceitos valores string, bool, inteiros, float32, float64 e
+// [time.Time] (escrito no formato AAAA-MM-DD HH:MM:SS). Valores nil geram
+// células vazias.
+func Write(w io.Writer, aba string, rows [][]any) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes},
+		{"_rels/.rels", rootRels},
+		{"xl/_rels/workbook.xml.rels", workbookRels},
+		{"xl/workbook.xml", fmt.Sprintf(workbook, escape(aba))},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if err := writeSheet(fw, rows); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeSheet(w io.Writer, rows [][]any) error {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	for i, row := range rows {
+		fmt.Fprintf(&sb, `<row r="%d">`, i+1)
+		for j, v := range row {
+			ref := columnName(j) + strconv.Itoa(i+1)
+			cell, err := formatCell(ref, v)
+			if err != nil {
+				return err
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString(`</row>`)
+	}
+
+	sb.WriteString(`</sheetData></worksheet>`)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func formatCell(ref string, v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return inlineString(ref, v), nil
+	case time.Time:
+		return inlineString(ref, v.Format(time.DateTime)), nil
+	case bool:
+		b := "0"
+		if v {
+			b = "1"
+		}
+		return fmt.Sprintf(`<c r="%s" t="b"><v>%s</v></c>`, ref, b), nil
+	case int:
+		return number(ref, strconv.Itoa(v)), nil
+	case int32:
+		return number(ref, strconv.FormatInt(int64(v), 10)), nil
+	case int64:
+		return number(ref, strconv.FormatInt(v, 10)), nil
+	case float32:
+		return number(ref, strconv.FormatFloat(float64(v), 'f', -1, 32)), nil
+	case float64:
+		return number(ref, strconv.FormatFloat(v, 'f', -1, 64)), nil
+	default:
+		return "", fmt.Errorf("xlsx: unsupported cell type %T", v)
+	}
+}
+
+func inlineString(ref, s string) string {
+	return fmt.Sprintf(`<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(s))
+}
+
+func number(ref, n string) string {
+	return fmt.Sprintf(`<c r="%s"><v>%s</v></c>`, ref, n)
+}
+
+func escape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// columnName converte o índice de uma coluna, a partir de zero, para o nome
+// usado nas referências da planilha (A, B, ..., Z, AA, AB, ...).
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,84 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{
+		0:   "A",
+		25:  "Z",
+		26:  "AA",
+		27:  "AB",
+		701: "ZZ",
+		702: "AAA",
+	}
+	for i, expected := range tests {
+		if got := columnName(i); got != expected {
+			t.Errorf("columnName(%d): expected %q, got %q", i, expected, got)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]any{
+		{"Nome", "Total", "Média"},
+		{"Fulano & Cia", 3, 1.5},
+		{time.Date(2026, time.May, 1, 10, 0, 0, 0, time.UTC), nil, true},
+	}
+	if err := Write(&buf, "Relatório", rows); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(b)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing file %q", name)
+		}
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `name="Relatório"`) {
+		t.Errorf("unexpected workbook: %s", files["xl/workbook.xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, expected := range []string{
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Fulano &amp; Cia</t></is></c>`,
+		`<c r="B2"><v>3</v></c>`,
+		`<c r="C2"><v>1.5</v></c>`,
+		`<t xml:space="preserve">2026-05-01 10:00:00</t>`,
+		`<c r="C3" t="b"><v>1</v></c>`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("expected sheet to contain %q", expected)
+		}
+	}
+
+	if err := Write(io.Discard, "x", [][]any{{struct{}{}}}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE "resultado_ingestao" AS ENUM (
+    'criado',
+    'ignorado',
+    'falha'
+);
+
+CREATE TABLE "ingestoes_processos" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "unidade" TEXT NOT NULL,
+    "dry_run" BOOLEAN NOT NULL DEFAULT FALSE,
+    "encontrados" INT NOT NULL DEFAULT 0,
+    "existentes" INT NOT NULL DEFAULT 0,
+    "criados" INT NOT NULL DEFAULT 0,
+    "ignorados" INT NOT NULL DEFAULT 0,
+    "falhas" INT NOT NULL DEFAULT 0,
+    "iniciada_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "concluida_em" TIMESTAMPTZ
+);
+
+CREATE TABLE "itens_ingestao_processos" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "ingestao_id" BIGINT NOT NULL REFERENCES "ingestoes_processos"("id") ON DELETE CASCADE,
+    "numero" TEXT NOT NULL,
+    "resultado" resultado_ingestao NOT NULL,
+    "detalhe" TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX "itens_ingestao_processos_ingestao_idx" ON "itens_ingestao_processos"("ingestao_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "itens_ingestao_processos";
+DROP TABLE "ingestoes_processos";
+DROP TYPE "resultado_ingestao";
+-- +goose StatementEnd
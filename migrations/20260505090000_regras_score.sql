@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "versoes_regras_score" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "versao" INT NOT NULL UNIQUE,
+    "descricao" TEXT NOT NULL DEFAULT '',
+    "regras" JSONB NOT NULL,
+    "ativa" BOOLEAN NOT NULL DEFAULT FALSE,
+    "criado_por" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "ativada_em" TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX "versoes_regras_score_ativa_idx" ON "versoes_regras_score"("ativa") WHERE "ativa";
+
+INSERT INTO "versoes_regras_score" ("versao", "descricao", "regras", "ativa", "ativada_em")
+VALUES (
+    1,
+    'Regras iniciais (docs/score.md)',
+    '{
+        "precedencias": [
+            {"codigo": "judicial", "descricao": "Processo judicializado", "condicao": "judicial", "score": 7},
+            {"codigo": "prioridade", "descricao": "Prioridade aprovada", "condicao": "prioridade", "score": 6}
+        ],
+        "criterios": [
+            {"codigo": "idade_60", "descricao": "Idade do requerente >= 60 anos", "condicao": "idade_minima", "valor": 60, "pontos": 1},
+            {"codigo": "idade_80", "descricao": "Idade do requerente >= 80 anos", "condicao": "idade_minima", "valor": 80, "pontos": 2},
+            {"codigo": "invalidez", "descricao": "Doença grave ou invalidez", "condicao": "invalidez", "pontos": 2}
+        ]
+    }',
+    TRUE,
+    CURRENT_TIMESTAMP
+);
+
+CREATE TABLE "scores_processo_aposentadoria" (
+    "processo_aposentadoria_id" BIGINT PRIMARY KEY REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "versao_regras_id" BIGINT NOT NULL REFERENCES "versoes_regras_score"("id") ON DELETE RESTRICT,
+    "score" INT NOT NULL,
+    "detalhes" JSONB NOT NULL,
+    "calculado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "scores_processo_aposentadoria";
+DROP TABLE "versoes_regras_score";
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "historico_scores_processo" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "processo_aposentadoria_id" BIGINT NOT NULL REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "versao_regras_id" BIGINT NOT NULL REFERENCES "versoes_regras_score"("id") ON DELETE RESTRICT,
+    "score_anterior" INT,
+    "score_novo" INT NOT NULL,
+    "detalhes" JSONB NOT NULL,
+    "calculado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX "historico_scores_processo_pa_idx" ON "historico_scores_processo"("processo_aposentadoria_id");
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE "historico_scores_processo";
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "afastamentos_analista" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "analista_id" BIGINT NOT NULL REFERENCES "analistas"("usuario_id") ON DELETE CASCADE,
+    "inicio" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "fim" TIMESTAMPTZ
+);
+-- Apenas um afastamento em aberto por analista.
+CREATE UNIQUE INDEX "afastamentos_analista_aberto_idx" ON "afastamentos_analista"("analista_id") WHERE "fim" IS NULL;
+
+-- Analistas já afastados passam a ter um afastamento em aberto a partir da migração.
+INSERT INTO "afastamentos_analista" ("analista_id")
+SELECT "usuario_id" FROM "analistas" WHERE "afastado";
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE "afastamentos_analista";
@@ -0,0 +1,92 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "categorias_diligencia" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "codigo" TEXT NOT NULL,
+    "versao" INT NOT NULL,
+    "nome" TEXT NOT NULL,
+    "descricao" TEXT NOT NULL DEFAULT '',
+    "lista_fechada" BOOLEAN NOT NULL DEFAULT FALSE,
+    "ordem" INT NOT NULL DEFAULT 0,
+    "ativa" BOOLEAN NOT NULL DEFAULT FALSE,
+    "criado_por" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE ("codigo", "versao")
+);
+CREATE UNIQUE INDEX "categorias_diligencia_ativa_idx" ON "categorias_diligencia"("codigo") WHERE "ativa";
+
+CREATE TABLE "subcategorias_diligencia" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "categoria_id" BIGINT NOT NULL REFERENCES "categorias_diligencia"("id") ON DELETE CASCADE,
+    "codigo" TEXT NOT NULL,
+    "nome" TEXT NOT NULL,
+    "ordem" INT NOT NULL DEFAULT 0,
+    UNIQUE ("categoria_id", "codigo")
+);
+
+ALTER TABLE "itens_diligencia"
+    ADD COLUMN "categoria_id" BIGINT REFERENCES "categorias_diligencia"("id") ON DELETE RESTRICT;
+
+INSERT INTO "categorias_diligencia" ("codigo", "versao", "nome", "descricao", "lista_fechada", "ordem", "ativa")
+VALUES
+    ('documentos_ausentes', 1, 'Documentos Obrigatórios Ausentes', 'Falta algum documento obrigatório do checklist aplicável ao caso.', TRUE, 1, TRUE),
+    ('informacoes_incompletas', 1, 'Documentos com Informações Incompletas/Faltantes', 'Documento preenchido parcialmente ou sem as informações necessárias para análise.', FALSE, 2, TRUE),
+    ('documentos_incorretos', 1, 'Documentos com Erros ou Incompatíveis com o Processo Analisado', 'Documento incorreto ou que não pertence ao processo do requerente.', FALSE, 3, TRUE),
+    ('divergencia_sisap', 1, 'Divergências de Informações entre Processo e SISAP', 'Dado do processo diferente do que consta no SISAP.', FALSE, 4, TRUE),
+    ('alteracao_dados', 1, 'Alteração de Dados Após o Envio', 'Informação do requerente alterada depois do envio do processo.', FALSE, 5, TRUE),
+    ('baixa_nitidez', 1, 'Documento com Baixa Nitidez', 'Documento com baixa nitidez, dificultando a leitura.', TRUE, 6, TRUE),
+    ('inconsistencia_origem', 1, 'Inconsistência na Análise do Órgão de Origem', 'Inconsistência relacionada à análise feita pelo órgão de origem.', FALSE, 7, TRUE);
+
+INSERT INTO "subcategorias_diligencia" ("categoria_id", "codigo", "nome", "ordem")
+SELECT c.id, s.codigo, s.nome, s.ordem
+FROM "categorias_diligencia" c
+JOIN (VALUES
+    ('fipa_conferencia', 'Dois relatórios de conferência extraídos da Fipa Eletrônica/SISAP: Dados cadastrais e Dados Funcionais', 1),
+    ('requerimento_voluntaria', 'Aposentadoria Voluntária: Requerimento de Aposentadoria', 2),
+    ('laudo_incapacidade', 'Aposentadoria por incapacidade permanente: Laudo Médico Oficial', 3),
+    ('certidao_compulsoria', 'Aposentadoria Compulsória: Cópia autenticada da certidão de nascimento ou casamento', 4),
+    ('acumulo_cargos', 'Declaração de Acúmulo de Cargos/Proventos', 5),
+    ('licitude_cargos', 'Cópia da publicação constando as informações referentes à licitude de cargos', 6),
+    ('decisao_processo', 'Cópia da decisão do processo administrativo ou declaração informando a finalização e os termos da decisão do processo administrativo. Cópia da decisão judicial, quando se tratar de direitos reconhecidos judicialmente', 7),
+    ('documento_identificacao', 'Cópia da certidão de nascimento ou casamento ou carteira de identidade ou outro documento público que comprove o nome completo e a idade do(a) servidor(a)', 8),
+    ('certidoes_tempo', 'Certidões de tempo de serviço/contribuição averbadas (INSS municipal, outro estado, federal e declarações ou demais documentos inerentes à averbação)', 9),
+    ('fipa_tempo_averbado', 'FIPA - Tempo Averbado', 10),
+    ('fipa_matriz_apuracao', 'FIPA - Matriz de Apuração de Tempo de acordo à regra da aposentadoria', 11),
+    ('fipa_matriz_contagem', 'FIPA - Matriz de Contagem de Tempo', 12),
+    ('fipa_dados_cadastrais', 'FIPA - Dados Cadastrais', 13),
+    ('planilha_media_ufr', 'Planilha de cálculo de proventos por média e Formulário da Última remuneração nos casos de aposentadoria por média com vigência anterior a 15.09.2020 e direito adquirido da EC 104/20', 14),
+    ('planilha_media', 'Planilha de cálculo de proventos por média nos casos de aposentadoria por média após EC 104/20', 15),
+    ('demonstrativo_pagamento', 'Demonstrativo de pagamento do mês de vigência da aposentadoria', 16),
+    ('declaracao_exercicio', 'Declaração do efetivo exercício expedido pelo órgão que recebeu o servidor na situação de adjunção ou disposição', 17)
+) AS s (codigo, nome, ordem) ON TRUE
+WHERE c.codigo = 'documentos_ausentes';
+
+INSERT INTO "subcategorias_diligencia" ("categoria_id", "codigo", "nome", "ordem")
+SELECT c.id, s.codigo, s.nome, s.ordem
+FROM "categorias_diligencia" c
+JOIN (VALUES
+    ('requerimento_voluntaria', 'Aposentadoria Voluntária: Requerimento de Aposentadoria', 1),
+    ('laudo_incapacidade', 'Aposentadoria por incapacidade permanente: Laudo Médico Oficial', 2),
+    ('certidao_compulsoria', 'Aposentadoria Compulsória: Cópia autenticada da certidão de nascimento ou casamento', 3),
+    ('acumulo_cargos', 'Declaração de Acúmulo de Cargos/Proventos', 4),
+    ('licitude_cargos', 'Cópia da publicação constando as informações referentes à licitude de cargos', 5),
+    ('decisao_processo', 'Cópia da decisão do processo administrativo ou declaração informando a finalização e os termos da decisão do processo administrativo. Cópia da decisão judicial, quando se tratar de direitos reconhecidos judicialmente', 6),
+    ('documento_identificacao', 'Cópia da certidão de nascimento ou casamento ou carteira de identidade ou outro documento público que comprove o nome completo e a idade do(a) servidor(a)', 7),
+    ('certidoes_tempo', 'Certidões de tempo de serviço/contribuição averbadas (INSS municipal, outro estado, federal e declarações ou demais documentos inerentes à averbação)', 8),
+    ('declaracao_exercicio', 'Declaração do efetivo exercício expedido pelo órgão que recebeu o servidor na situação de adjunção ou disposição', 9)
+) AS s (codigo, nome, ordem) ON TRUE
+WHERE c.codigo = 'baixa_nitidez';
+
+-- Vincula os itens existentes às categorias pelo nome. Os nomes são os mesmos
+-- usados pela interface antes do catálogo.
+UPDATE "itens_diligencia" i SET "categoria_id" = c.id
+FROM "categorias_diligencia" c
+WHERE c.nome = i.tipo;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "itens_diligencia" DROP COLUMN "categoria_id";
+DROP TABLE "subcategorias_diligencia";
+DROP TABLE "categorias_diligencia";
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "solicitacoes_diligencia"
+    ADD COLUMN "oficio" TEXT NOT NULL DEFAULT '',
+    ADD COLUMN "sei_documento_id" TEXT,
+    ADD COLUMN "sei_documento_numero" TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "solicitacoes_diligencia"
+    DROP COLUMN "sei_documento_numero",
+    DROP COLUMN "sei_documento_id",
+    DROP COLUMN "oficio";
+-- +goose StatementEnd
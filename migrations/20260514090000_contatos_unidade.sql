@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "contatos_unidade" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "unidade_sigla" TEXT NOT NULL,
+    "nome" TEXT NOT NULL DEFAULT '',
+    "email" TEXT NOT NULL,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "atualizado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT "contatos_unidade_email_key" UNIQUE ("unidade_sigla", "email")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "contatos_unidade";
+-- +goose StatementEnd
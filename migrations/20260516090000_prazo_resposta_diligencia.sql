@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "solicitacoes_diligencia"
+    ADD COLUMN "prazo_resposta" DATE,
+    ADD COLUMN "vencimento_notificado_em" TIMESTAMPTZ;
+
+-- Diligências já enviadas recebem o prazo padrão de 30 dias.
+UPDATE "solicitacoes_diligencia"
+SET "prazo_resposta" = ("enviada_em" AT TIME ZONE 'America/Sao_Paulo')::date + 30
+WHERE "status" = 'enviada' AND "enviada_em" IS NOT NULL;
+
+CREATE TABLE "lembretes_diligencia" (
+    "solicitacao_diligencia_id" BIGINT NOT NULL REFERENCES "solicitacoes_diligencia"("id") ON DELETE CASCADE,
+    "dias" INT NOT NULL,
+    "enviado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("solicitacao_diligencia_id", "dias")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "lembretes_diligencia";
+ALTER TABLE "solicitacoes_diligencia"
+    DROP COLUMN "vencimento_notificado_em",
+    DROP COLUMN "prazo_resposta";
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "itens_diligencia"
+    ADD COLUMN "item_origem_id" BIGINT REFERENCES "itens_diligencia"("id") ON DELETE SET NULL,
+    ADD COLUMN "atendido" BOOLEAN,
+    ADD COLUMN "observacao_atendimento" TEXT NOT NULL DEFAULT '',
+    ADD COLUMN "atendimento_registrado_por" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    ADD COLUMN "atendimento_registrado_em" TIMESTAMPTZ;
+
+CREATE INDEX "itens_diligencia_item_origem_idx" ON "itens_diligencia"("item_origem_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX "itens_diligencia_item_origem_idx";
+ALTER TABLE "itens_diligencia"
+    DROP COLUMN "atendimento_registrado_em",
+    DROP COLUMN "atendimento_registrado_por",
+    DROP COLUMN "observacao_atendimento",
+    DROP COLUMN "atendido",
+    DROP COLUMN "item_origem_id";
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "processos_fixados" (
+    "processo_aposentadoria_id" BIGINT PRIMARY KEY REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "analista_id" BIGINT NOT NULL REFERENCES "analistas"("usuario_id") ON DELETE CASCADE,
+    "fixado_por" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    "motivo" TEXT NOT NULL DEFAULT '',
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX "processos_fixados_analista_idx" ON "processos_fixados"("analista_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "processos_fixados";
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "analistas"
+    ADD COLUMN "competencias" TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN "nivel" TEXT NOT NULL DEFAULT 'PLENO';
+
+-- Os analistas existentes atendiam todos os processos.
+UPDATE "analistas" SET "competencias" = '{JUDICIAL,INVALIDEZ,ORGAO_SEE}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "analistas"
+    DROP COLUMN "nivel",
+    DROP COLUMN "competencias";
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "analistas"
+    ADD COLUMN "limite_processos" INT NOT NULL DEFAULT 1 CHECK ("limite_processos" > 0);
+
+DROP INDEX "processos_um_por_analista_idx";
+CREATE INDEX "processos_em_analise_analista_idx" ON "processos_aposentadoria"("analista_id") WHERE "status" = 'EM_ANALISE';
+
+-- Garante que nenhum analista receba mais processos EM_ANALISE do que o seu
+-- limite. A linha do analista é bloqueada para serializar atribuições
+-- concorrentes. Apenas a entrada do processo em análise ou a troca de analista
+-- são verificadas, para que a redução do limite não impeça a atualização dos
+-- processos já atribuídos.
+CREATE FUNCTION "verificar_limite_processos_analista"() RETURNS TRIGGER AS $$
+DECLARE
+    limite INT;
+    total INT;
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND OLD.status = NEW.status
+        AND OLD.analista_id IS NOT DISTINCT FROM NEW.analista_id THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT "limite_processos" INTO limite
+    FROM "analistas"
+    WHERE "usuario_id" = NEW.analista_id
+    FOR UPDATE;
+
+    SELECT COUNT(*) INTO total
+    FROM "processos_aposentadoria"
+    WHERE "analista_id" = NEW.analista_id
+    AND "status" = 'EM_ANALISE'
+    AND "id" <> NEW.id;
+
+    IF total >= COALESCE(limite, 1) THEN
+        RAISE EXCEPTION 'processos_limite_analista: analista % atingiu o limite de % processo(s) em análise', NEW.analista_id, COALESCE(limite, 1)
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "processos_limite_analista"
+    BEFORE INSERT OR UPDATE OF "status", "analista_id" ON "processos_aposentadoria"
+    FOR EACH ROW
+    WHEN (NEW.status = 'EM_ANALISE' AND NEW.analista_id IS NOT NULL)
+    EXECUTE FUNCTION "verificar_limite_processos_analista"();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER "processos_limite_analista" ON "processos_aposentadoria";
+DROP FUNCTION "verificar_limite_processos_analista"();
+DROP INDEX "processos_em_analise_analista_idx";
+CREATE UNIQUE INDEX "processos_um_por_analista_idx" ON "processos_aposentadoria"("analista_id") WHERE "status" = 'EM_ANALISE';
+ALTER TABLE "analistas" DROP COLUMN "limite_processos";
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "afastamentos_programados" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "analista_id" BIGINT NOT NULL REFERENCES "analistas"("usuario_id") ON DELETE CASCADE,
+    "inicio" TIMESTAMPTZ NOT NULL,
+    "fim" TIMESTAMPTZ NOT NULL,
+    "motivo" TEXT NOT NULL DEFAULT '',
+    "criado_por" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "iniciado_em" TIMESTAMPTZ,
+    "encerrado_em" TIMESTAMPTZ,
+    "cancelado_em" TIMESTAMPTZ,
+    CHECK ("fim" > "inicio")
+);
+CREATE INDEX "afastamentos_programados_analista_idx" ON "afastamentos_programados"("analista_id", "inicio");
+-- Afastamentos que ainda precisam ser iniciados ou encerrados pelo job.
+CREATE INDEX "afastamentos_programados_pendentes_idx" ON "afastamentos_programados"("inicio")
+    WHERE "encerrado_em" IS NULL AND "cancelado_em" IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "afastamentos_programados";
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE "devolucoes_processo" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "processo_aposentadoria_id" BIGINT NOT NULL REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "analista_id" BIGINT NOT NULL REFERENCES "analistas"("usuario_id") ON DELETE CASCADE,
+    "motivo" TEXT NOT NULL,
+    "excluir_analista" BOOLEAN NOT NULL DEFAULT FALSE,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX "devolucoes_processo_criado_em_idx" ON "devolucoes_processo"("criado_em");
+CREATE INDEX "devolucoes_processo_pa_idx" ON "devolucoes_processo"("processo_aposentadoria_id");
+
+-- Analistas que não devem voltar a receber o processo que devolveram.
+CREATE TABLE "processos_analistas_excluidos" (
+    "processo_aposentadoria_id" BIGINT NOT NULL REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "analista_id" BIGINT NOT NULL REFERENCES "analistas"("usuario_id") ON DELETE CASCADE,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("processo_aposentadoria_id", "analista_id")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "processos_analistas_excluidos";
+DROP TABLE "devolucoes_processo";
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Trilha de auditoria das alterações dos dados extraídos dos processos de
+-- aposentadoria, com um registro por campo alterado.
+CREATE TABLE "alteracoes_campos_processo" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "processo_aposentadoria_id" BIGINT NOT NULL REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "campo" TEXT NOT NULL,
+    "valor_anterior" TEXT NOT NULL,
+    "valor_novo" TEXT NOT NULL,
+    "origem" TEXT NOT NULL,
+    "usuario_id" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX "alteracoes_campos_processo_pa_idx" ON "alteracoes_campos_processo"("processo_aposentadoria_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "alteracoes_campos_processo";
+-- +goose StatementEnd
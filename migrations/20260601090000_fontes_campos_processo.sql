@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Procedência do valor atual de cada dado extraído dos processos de
+-- aposentadoria.
+CREATE TABLE "fontes_campos_processo" (
+    "processo_aposentadoria_id" BIGINT NOT NULL REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "campo" TEXT NOT NULL,
+    -- Fonte do valor atual do campo: IA, DATALAKE, SEI ou MANUAL.
+    "fonte" TEXT NOT NULL,
+    "valor_ia" TEXT,
+    -- Fonte externa consultada para confirmar o valor extraído pela IA. O valor
+    -- externo nulo indica que a fonte não pôde ser consultada.
+    "fonte_externa" TEXT,
+    "valor_externo" TEXT,
+    "atualizado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("processo_aposentadoria_id", "campo")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "fontes_campos_processo";
+-- +goose StatementEnd
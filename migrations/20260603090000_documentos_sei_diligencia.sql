@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Números dos documentos do processo no SEI no momento em que ele foi enviado
+-- para diligência. Os documentos fora desta lista indicam o retorno.
+CREATE TABLE "documentos_sei_diligencia" (
+    "processo_aposentadoria_id" BIGINT PRIMARY KEY REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "documentos" TEXT[] NOT NULL,
+    "registrado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE "documentos_sei_diligencia";
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Eventos dos processos de aposentadoria que não alteram o status, como as
+-- tramitações no SEI. O histórico de status registra apenas as transições.
+CREATE TABLE "eventos_processo" (
+    "id" BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    "processo_aposentadoria_id" BIGINT NOT NULL REFERENCES "processos_aposentadoria"("id") ON DELETE CASCADE,
+    "tipo" TEXT NOT NULL,
+    "descricao" TEXT NOT NULL,
+    "usuario_id" BIGINT REFERENCES "usuarios"("id") ON DELETE SET NULL,
+    "criado_em" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX "eventos_processo_pa_idx" ON "eventos_processo"("processo_aposentadoria_id");
+
+-- Entradas do histórico sem mudança de status passam a ser eventos.
+INSERT INTO "eventos_processo" ("processo_aposentadoria_id", "tipo", "descricao", "usuario_id", "criado_em")
+SELECT
+    h.processo_aposentadoria_id,
+    CASE
+        WHEN h.observacao LIKE 'Processo enviado no SEI%'
+            OR h.observacao LIKE 'Falha ao enviar processo no SEI%' THEN 'TRAMITACAO_SEI'
+        WHEN h.observacao LIKE 'Ofício de diligência incluído no SEI%'
+            OR h.observacao LIKE 'Falha ao incluir o ofício de diligência%' THEN 'OFICIO_DILIGENCIA'
+        WHEN h.observacao LIKE 'Prazo de resposta da diligência%' THEN 'PRAZO_DILIGENCIA'
+        WHEN h.observacao LIKE 'Atendimento da diligência verificado%' THEN 'ATENDIMENTO_DILIGENCIA'
+        WHEN h.observacao LIKE 'Processo reatribuído de%' THEN 'REATRIBUICAO'
+        WHEN h.observacao LIKE 'Processo fixado para o analista%'
+            OR h.observacao LIKE 'Fixação do processo removida%' THEN 'FIXACAO'
+        WHEN h.observacao LIKE 'Reprocessamento%' THEN 'REPROCESSAMENTO'
+        ELSE 'OBSERVACAO'
+    END,
+    COALESCE(h.observacao, ''),
+    h.usuario_id,
+    h.alterado_em
+FROM "historico_status_processo" h
+WHERE h.status_anterior = h.status_novo;
+
+DELETE FROM "historico_status_processo" WHERE status_anterior = status_novo;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+INSERT INTO "historico_status_processo" ("processo_aposentadoria_id", "status_anterior", "status_novo", "usuario_id", "observacao", "alterado_em")
+SELECT e.processo_aposentadoria_id, pa.status, pa.status, e.usuario_id, e.descricao, e.criado_em
+FROM "eventos_processo" e
+JOIN "processos_aposentadoria" pa ON pa.id = e.processo_aposentadoria_id;
+
+DROP TABLE "eventos_processo";
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "afastamentos_analista" ADD COLUMN "manual" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Afastamentos em aberto sem afastamento programado em curso foram abertos manualmente.
+UPDATE "afastamentos_analista" aa SET "manual" = TRUE
+WHERE aa."fim" IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM "afastamentos_programados" ap
+    WHERE ap."analista_id" = aa."analista_id"
+    AND ap."iniciado_em" IS NOT NULL
+    AND ap."encerrado_em" IS NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "afastamentos_analista" DROP COLUMN "manual";
+-- +goose StatementEnd
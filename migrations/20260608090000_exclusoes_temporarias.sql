@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Exclusões temporárias valem apenas para a atribuição agendada pela devolução do processo.
+ALTER TABLE "processos_analistas_excluidos" ADD COLUMN "temporaria" BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM "processos_analistas_excluidos" WHERE "temporaria";
+ALTER TABLE "processos_analistas_excluidos" DROP COLUMN "temporaria";
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Motivo informado na correção manual, que deixa de ser registrada no histórico de status.
+ALTER TABLE "alteracoes_campos_processo" ADD COLUMN "motivo" TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "alteracoes_campos_processo" DROP COLUMN "motivo";
+-- +goose StatementEnd
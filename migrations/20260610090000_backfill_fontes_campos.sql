@@ -0,0 +1,84 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Processos analisados antes do registro da procedência têm a fonte de cada
+-- campo reconstruída a partir da análise de IA e dos alertas da extração, que
+-- indicavam as fontes externas que não puderam ser consultadas. Campos
+-- corrigidos manualmente mantêm a fonte MANUAL.
+INSERT INTO "fontes_campos_processo" ("processo_aposentadoria_id", "campo", "fonte", "valor_ia", "fonte_externa", "valor_externo")
+SELECT
+    b.id,
+    c.campo,
+    CASE
+        WHEN EXISTS (
+            SELECT 1
+            FROM "alteracoes_campos_processo" a
+            WHERE a.processo_aposentadoria_id = b.id
+            AND a.campo = c.campo
+            AND a.origem = 'MANUAL'
+        ) THEN 'MANUAL'
+        ELSE c.fonte
+    END,
+    c.valor_ia,
+    c.fonte_externa,
+    c.valor_externo
+FROM (
+    SELECT
+        pa.id,
+        pa.cpf_requerente,
+        pa.data_nascimento_requerente,
+        pa.data_requerimento,
+        pa.invalidez,
+        NULLIF(p.metadados_ia->>'cpf_requerente', '') AS ia_cpf,
+        NULLIF(p.metadados_ia->>'data_nascimento_requerente', '') AS ia_nascimento,
+        NULLIF(p.metadados_ia->>'data_requerimento', '') AS ia_requerimento,
+        COALESCE((p.metadados_ia->>'invalidez')::BOOLEAN, FALSE) AS ia_invalidez,
+        COALESCE((p.metadados_ia->>'judicial')::BOOLEAN, FALSE) AS ia_judicial,
+        NOT ('Não foi possível obter os dados do servidor no datalake. Utilizando dados extraídos pela IA.' = ANY(pa.alertas)) AS datalake,
+        NOT ('Não foi possível obter a data de recebimento no SEI. Utilizando data extraída pela IA.' = ANY(pa.alertas)) AS sei
+    FROM "processos_aposentadoria" pa
+    JOIN "processos" p ON p.id = pa.processo_id
+    WHERE NOT EXISTS (
+        SELECT 1 FROM "fontes_campos_processo" f WHERE f.processo_aposentadoria_id = pa.id
+    )
+) b
+CROSS JOIN LATERAL (
+    VALUES
+        ('cpf_requerente', 'IA', b.ia_cpf, NULL, NULL),
+        (
+            'data_nascimento_requerente',
+            CASE WHEN b.datalake THEN 'DATALAKE' ELSE 'IA' END,
+            b.ia_nascimento,
+            'DATALAKE',
+            CASE WHEN b.datalake THEN to_char(b.data_nascimento_requerente, 'YYYY-MM-DD') END
+        ),
+        (
+            'data_requerimento',
+            CASE WHEN b.sei THEN 'SEI' ELSE 'IA' END,
+            b.ia_requerimento,
+            'SEI',
+            CASE WHEN b.sei THEN to_char(b.data_requerimento, 'YYYY-MM-DD') END
+        ),
+        -- A deficiência do servidor não era registrada, então a invalidez
+        -- informada pela IA é considerada confirmada pelo datalake.
+        (
+            'invalidez',
+            CASE WHEN b.datalake AND b.invalidez AND NOT b.ia_invalidez THEN 'DATALAKE' ELSE 'IA' END,
+            b.ia_invalidez::TEXT,
+            'DATALAKE',
+            CASE WHEN b.datalake THEN b.invalidez::TEXT END
+        ),
+        ('judicial', 'IA', b.ia_judicial::TEXT, NULL, NULL)
+) AS c(campo, fonte, valor_ia, fonte_externa, valor_externo);
+
+-- Os alertas de texto da extração foram substituídos pelas discrepâncias.
+UPDATE "processos_aposentadoria" SET "alertas" = array_remove(array_remove("alertas",
+    'Não foi possível obter os dados do servidor no datalake. Utilizando dados extraídos pela IA.'),
+    'Não foi possível obter a data de recebimento no SEI. Utilizando data extraída pela IA.');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- A procedência reconstruída é mantida, pois não se distingue da registrada na
+-- análise.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Exclusões temporárias expiram mesmo que a atribuição agendada pela devolução
+-- não chegue a removê-las.
+ALTER TABLE "processos_analistas_excluidos" ADD COLUMN "expira_em" TIMESTAMPTZ;
+
+-- Exclusões temporárias existentes já deveriam ter sido removidas.
+UPDATE "processos_analistas_excluidos" SET "expira_em" = CURRENT_TIMESTAMP WHERE "temporaria";
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "processos_analistas_excluidos" DROP COLUMN "expira_em";
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Processos em diligência sem os documentos do envio registrados não têm o
+-- retorno identificado automaticamente e devem ser verificados manualmente.
+UPDATE "processos_aposentadoria" pa SET
+    "alertas" = array_append(pa."alertas", 'Os documentos do envio da diligência não foram registrados. Verifique manualmente o retorno da diligência no SEI.'),
+    "atualizado_em" = CURRENT_TIMESTAMP
+WHERE pa."status" = 'EM_DILIGENCIA'
+AND NOT EXISTS (
+    SELECT 1 FROM "documentos_sei_diligencia" d WHERE d."processo_aposentadoria_id" = pa."id"
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE "processos_aposentadoria" SET "alertas" = array_remove("alertas",
+    'Os documentos do envio da diligência não foram registrados. Verifique manualmente o retorno da diligência no SEI.');
+-- +goose StatementEnd
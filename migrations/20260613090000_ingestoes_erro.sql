@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Erro que interrompeu a execução da ingestão, vazio quando concluída.
+ALTER TABLE "ingestoes_processos" ADD COLUMN "erro" TEXT NOT NULL DEFAULT '';
+
+-- Execuções interrompidas antes deste registro ficaram sem conclusão.
+UPDATE "ingestoes_processos" SET
+    "concluida_em" = "iniciada_em",
+    "erro" = 'Execução interrompida'
+WHERE "concluida_em" IS NULL;
+
+-- Os processos já cadastrados passam a ser registrados como itens.
+ALTER TYPE "resultado_ingestao" ADD VALUE 'existente';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "ingestoes_processos" DROP COLUMN "erro";
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Registrado antes do envio do ofício ao SEI, para que uma nova tentativa não
+-- inclua o ofício em duplicidade quando o documento criado não foi salvo.
+ALTER TABLE "solicitacoes_diligencia"
+    ADD COLUMN "sei_inclusao_iniciada_em" TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE "solicitacoes_diligencia"
+    DROP COLUMN "sei_inclusao_iniciada_em";
+-- +goose StatementEnd
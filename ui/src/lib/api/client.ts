@@ -15,6 +15,7 @@ import {
   type Paginated,
   type Processo,
   type ProcessoAposentadoria,
+  type ProcessoEvento,
   type ProcessoHistorico,
   type RecuperarSenha,
   type RedefinirSenha,
@@ -293,6 +294,12 @@ export class Client {
     );
   }
 
+  async getEventos(id: number): Promise<ProcessoEvento[]> {
+    return this.request<ProcessoEvento[]>(
+      `/api/v1/aposentadoria/${id}/eventos`,
+    );
+  }
+
   async solicitarPrioridade(
     paId: number,
     data: SolicitarPrioridade,
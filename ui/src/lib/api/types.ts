@@ -107,6 +107,15 @@ export type ProcessoAposentadoria = {
   alertas: string[];
   criado_em: string;
   atualizado_em: string;
+  discrepancias?: Discrepancia[];
+};
+
+export type Discrepancia = {
+  campo: string;
+  tipo: "DIVERGENCIA" | "FONTE_INDISPONIVEL";
+  valor_ia: string | null;
+  fonte_externa: string;
+  valor_externo: string | null;
 };
 
 export type Analista = {
@@ -154,6 +163,14 @@ export type ProcessoHistorico = {
   alterado_em: string;
 };
 
+export type ProcessoEvento = {
+  tipo: string;
+  descricao: string;
+  usuario_id: number | null;
+  usuario: string | null;
+  criado_em: string;
+};
+
 export type SolicitacaoPrioridade = {
   id: number;
   numero_processo: string;
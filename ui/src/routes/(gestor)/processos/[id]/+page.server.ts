@@ -7,9 +7,11 @@ export const load = async ({ params }) => {
 
   const processo = await client.getAposentadoria(processoId);
   const historico = await client.getHistorico(processoId);
+  const eventos = await client.getEventos(processoId);
 
   return {
     processo,
     historico,
+    eventos,
   };
 };